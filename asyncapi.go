@@ -0,0 +1,159 @@
+package typed
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/asyncapi"
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// NewAsyncAPIDocument creates AsyncAPI document with info and servers of OpenAPI spec.
+// Server URLs are converted to websocket protocols: http to ws and https to wss.
+func NewAsyncAPIDocument(spec *openapi3.T) *asyncapi.Document {
+	var title, version string
+	if spec.Info != nil {
+		title, version = spec.Info.Title, spec.Info.Version
+	}
+
+	doc := asyncapi.New(title, version)
+	for i, server := range spec.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		protocol := "ws"
+		if u.Scheme == "https" || u.Scheme == "wss" {
+			protocol = "wss"
+		}
+
+		doc.Servers[fmt.Sprintf("server%d", i+1)] = &asyncapi.Server{
+			Host:     u.Host,
+			Protocol: protocol,
+			Pathname: strings.TrimSuffix(u.Path, "/"),
+		}
+	}
+
+	return doc
+}
+
+// addAsyncAPIChannel describes websocket handler as channel with send and receive operations.
+// Message payloads reference the same component schemas as OpenAPI document.
+func addAsyncAPIChannel(
+	doc *asyncapi.Document,
+	handler handlers.Handler,
+	models ModelSource,
+	schemas openapi3.Schemas,
+) error {
+	ws := handler.WebSocket()
+	if ws == nil {
+		return nil
+	}
+
+	name := SanitizeComponentName(handler.HandlerName())
+	key := name
+	for i := 2; doc.Channels[key] != nil; i++ {
+		key = fmt.Sprintf("%s%d", name, i)
+	}
+
+	channel := &asyncapi.Channel{
+		Address:     handler.Path(),
+		Description: handler.Description(),
+		Messages:    make(map[string]*asyncapi.Message),
+	}
+
+	for _, p := range handler.PathParams() {
+		if channel.Parameters == nil {
+			channel.Parameters = make(map[string]*asyncapi.Parameter)
+		}
+		channel.Parameters[p.Name] = &asyncapi.Parameter{}
+	}
+
+	addOperation := func(action string, messages []*typing.Type) error {
+		if len(messages) == 0 {
+			return nil
+		}
+
+		op := &asyncapi.Operation{
+			Action:  action,
+			Channel: asyncapi.Reference{Ref: "#/channels/" + key},
+		}
+
+		for _, model := range messages {
+			ref, err := models.SchemaRef(model, schemas)
+			if err != nil {
+				return fmt.Errorf("failed to generate schema ref for message %s: %w", model, err)
+			}
+
+			msgName, contentType := messageName(model, ref)
+			channel.Messages[msgName] = &asyncapi.Message{
+				Name:        msgName,
+				ContentType: contentType,
+				Payload: &asyncapi.Payload{
+					SchemaFormat: asyncapi.OpenAPISchemaFormat,
+					Schema:       ref,
+				},
+			}
+			op.Messages = append(op.Messages, asyncapi.Reference{Ref: "#/channels/" + key + "/messages/" + msgName})
+		}
+
+		doc.Operations[key+strings.Title(action)] = op
+		return nil
+	}
+
+	if err := addOperation(asyncapi.ActionSend, ws.Send); err != nil {
+		return err
+	}
+
+	if err := addOperation(asyncapi.ActionReceive, ws.Receive); err != nil {
+		return err
+	}
+
+	doc.Channels[key] = channel
+	return nil
+}
+
+// channelSchemas returns component schemas, which are referenced by channel messages directly or through other
+// schemas. Nil is returned, when document has no such references
+func channelSchemas(doc *asyncapi.Document, schemas openapi3.Schemas) (openapi3.Schemas, error) {
+	queue, err := schemaRefs(doc.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable, err := reachableSchemas(schemas, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reachable) == 0 {
+		return nil, nil
+	}
+
+	res := make(openapi3.Schemas, len(reachable))
+	for name := range reachable {
+		res[name] = schemas[name]
+	}
+	return res, nil
+}
+
+// messageName returns message name and content type. Messages with component schemas are named after component,
+// raw string messages are sent as text
+func messageName(model *typing.Type, ref *openapi3.SchemaRef) (string, string) {
+	if name, ok := strings.CutPrefix(ref.Ref, schemaRefPrefix); ok {
+		return name, echo.MIMEApplicationJSON
+	}
+
+	if model.Kind() == typing.TypeKindBasic && model.Name() == "string" {
+		return "text", echo.MIMETextPlain
+	}
+
+	return SanitizeComponentName(model.String()), echo.MIMEApplicationJSON
+}
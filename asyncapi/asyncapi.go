@@ -0,0 +1,85 @@
+// Package asyncapi describes subset of AsyncAPI 3.0 document, used to describe websocket handlers.
+package asyncapi
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+const (
+	Version = "3.0.0"
+
+	// OpenAPISchemaFormat is a format of message payloads, which are described with OpenAPI 3.0 schemas
+	OpenAPISchemaFormat = "application/vnd.oai.openapi;version=3.0.0"
+
+	ActionSend    = "send"
+	ActionReceive = "receive"
+)
+
+type Document struct {
+	AsyncAPI   string                `json:"asyncapi" yaml:"asyncapi"`
+	Info       Info                  `json:"info" yaml:"info"`
+	Servers    map[string]*Server    `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Channels   map[string]*Channel   `json:"channels,omitempty" yaml:"channels,omitempty"`
+	Operations map[string]*Operation `json:"operations,omitempty" yaml:"operations,omitempty"`
+	Components *Components           `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// New creates empty document
+func New(title string, version string) *Document {
+	return &Document{
+		AsyncAPI:   Version,
+		Info:       Info{Title: title, Version: version},
+		Servers:    make(map[string]*Server),
+		Channels:   make(map[string]*Channel),
+		Operations: make(map[string]*Operation),
+		Components: &Components{},
+	}
+}
+
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type Server struct {
+	Host     string `json:"host" yaml:"host"`
+	Protocol string `json:"protocol" yaml:"protocol"`
+	Pathname string `json:"pathname,omitempty" yaml:"pathname,omitempty"`
+}
+
+type Channel struct {
+	Address     string                `json:"address" yaml:"address"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Messages    map[string]*Message   `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Parameters  map[string]*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type Parameter struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type Message struct {
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty"`
+	ContentType string   `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Payload     *Payload `json:"payload,omitempty" yaml:"payload,omitempty"`
+}
+
+// Payload is a multi format schema object
+type Payload struct {
+	SchemaFormat string              `json:"schemaFormat" yaml:"schemaFormat"`
+	Schema       *openapi3.SchemaRef `json:"schema" yaml:"schema"`
+}
+
+type Operation struct {
+	Action   string      `json:"action" yaml:"action"`
+	Channel  Reference   `json:"channel" yaml:"channel"`
+	Messages []Reference `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+type Reference struct {
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+// Components holds schemas, referenced by message payloads. Payloads use the same references as OpenAPI document:
+// #/components/schemas/<name>
+type Components struct {
+	Schemas openapi3.Schemas `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
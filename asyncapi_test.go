@@ -0,0 +1,130 @@
+package typed
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/asyncapi"
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
+	"github.com/d1vbyz3r0/typed/internal/parser/websocket"
+	"github.com/d1vbyz3r0/typed/testdata/dto"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAsyncAPIDocument(t *testing.T) {
+	doc := NewAsyncAPIDocument(&openapi3.T{
+		Info: &openapi3.Info{Title: "api", Version: "1.0.0"},
+		Servers: openapi3.Servers{
+			{URL: "http://localhost:8080"},
+			{URL: "https://example.com/api/"},
+		},
+	})
+
+	require.Equal(t, asyncapi.Version, doc.AsyncAPI)
+	require.Equal(t, asyncapi.Info{Title: "api", Version: "1.0.0"}, doc.Info)
+	require.Equal(t, map[string]*asyncapi.Server{
+		"server1": {Host: "localhost:8080", Protocol: "ws"},
+		"server2": {Host: "example.com", Protocol: "wss", Pathname: "/api"},
+	}, doc.Servers)
+}
+
+func TestGenerateAsyncAPI(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(dto.User), Type: typing.Named(dtoPkg, "User")},
+		T{Val: new(string), Type: typing.Basic("string")},
+		T{Val: new(dto.Form), Type: typing.Named(dtoPkg, "Form")},
+	)
+
+	ws := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/rooms/:id/ws"},
+		nil,
+		parser.Handler{
+			Name:    "roomEvents",
+			Doc:     "roomEvents streams room events",
+			Request: &request.Request{},
+			WebSocket: &websocket.Messages{
+				Send:    []*typing.Type{typing.Named(dtoPkg, "User")},
+				Receive: []*typing.Type{typing.Basic("string")},
+			},
+		},
+	)
+	regular := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/users"},
+		nil,
+		parser.Handler{Name: "getUsers", Request: &request.Request{}},
+	)
+
+	spec := &openapi3.T{Info: &openapi3.Info{Title: "api", Version: "1.0.0"}}
+	doc := NewAsyncAPIDocument(spec)
+	require.NoError(t, Generate(GenerateOptions{
+		Spec:     spec,
+		Registry: registry,
+		Handlers: []handlers.Handler{ws, regular},
+		AsyncAPI: doc,
+		// unused components are kept in OpenAPI spec, but not copied to AsyncAPI document
+		KeepUnusedComponents: true,
+	}))
+
+	require.Len(t, doc.Channels, 1)
+	channel := doc.Channels["roomEvents"]
+	require.Equal(t, "/rooms/{id}/ws", channel.Address)
+	require.Equal(t, "roomEvents streams room events", channel.Description)
+	require.Contains(t, channel.Parameters, "id")
+
+	user := channel.Messages["dto.User"]
+	require.Equal(t, echo.MIMEApplicationJSON, user.ContentType)
+	require.Equal(t, asyncapi.OpenAPISchemaFormat, user.Payload.SchemaFormat)
+	require.Equal(t, "#/components/schemas/dto.User", user.Payload.Schema.Ref)
+	require.Equal(t, echo.MIMETextPlain, channel.Messages["text"].ContentType)
+
+	require.Equal(t, &asyncapi.Operation{
+		Action:   asyncapi.ActionSend,
+		Channel:  asyncapi.Reference{Ref: "#/channels/roomEvents"},
+		Messages: []asyncapi.Reference{{Ref: "#/channels/roomEvents/messages/dto.User"}},
+	}, doc.Operations["roomEventsSend"])
+	require.Equal(t, asyncapi.ActionReceive, doc.Operations["roomEventsReceive"].Action)
+	require.Contains(t, doc.Components.Schemas, "dto.User")
+	require.Contains(t, spec.Components.Schemas, "dto.Form")
+	require.NotContains(t, doc.Components.Schemas, "dto.Form", "only schemas referenced by channels are copied")
+
+	out := filepath.Join(t.TempDir(), "asyncapi.json")
+	require.NoError(t, SaveAsyncAPI(doc, out))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	var saved map[string]any
+	require.NoError(t, json.Unmarshal(data, &saved))
+	require.Equal(t, asyncapi.Version, saved["asyncapi"])
+}
+
+func TestGenerateAsyncAPIWithoutChannels(t *testing.T) {
+	registry := MustNewRegistry(T{Val: new(dto.User), Type: typing.Named(dtoPkg, "User")})
+	regular := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/users"},
+		nil,
+		parser.Handler{Name: "getUsers", Request: &request.Request{}},
+	)
+
+	spec := &openapi3.T{Info: &openapi3.Info{Title: "api", Version: "1.0.0"}}
+	doc := NewAsyncAPIDocument(spec)
+	require.NoError(t, Generate(GenerateOptions{
+		Spec:                 spec,
+		Registry:             registry,
+		Handlers:             []handlers.Handler{regular},
+		AsyncAPI:             doc,
+		KeepUnusedComponents: true,
+	}))
+
+	require.Contains(t, spec.Components.Schemas, "dto.User")
+	require.Empty(t, doc.Channels)
+	require.Empty(t, doc.Components.Schemas)
+}
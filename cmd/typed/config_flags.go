@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/d1vbyz3r0/typed/internal/generator"
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+// configFlags are flags of commands, which load config. Flags override config keys, when they are set
+type configFlags struct {
+	path               string
+	noCache            bool
+	werror             bool
+	logFormat          string
+	logLevel           string
+	reportPath         string
+	minCoverage        float64
+	outputPath         string
+	specPath           string
+	routesProviderPkg  string
+	routesProviderCtor string
+	concurrency        int
+}
+
+func (f *configFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.path, "config", "typed.yaml", "path to config file")
+	flags.BoolVar(&f.noCache, "no-cache", false, "disable parsing results cache for this run")
+	flags.BoolVar(&f.werror, "Werror", false, "fail generation, if parser reports any diagnostic, overrides werror")
+	flags.StringVar(&f.logFormat, "log-format", "", "log format, text or json, overrides log.format")
+	flags.StringVar(&f.logLevel, "log-level", "", "log level, debug, info, warn or error, overrides log.level")
+	flags.StringVar(&f.reportPath, "report", "", "path of JSON generation report, overrides output.report.path")
+	flags.Float64Var(&f.minCoverage, "min-coverage", 0, "fail generation, if documentation coverage percentage is below it, overrides output.report.min-coverage")
+	flags.StringVar(&f.outputPath, "output", "", "path of generated spec builder source, overrides output.path")
+	flags.StringVar(&f.specPath, "spec-path", "", "path of generated spec, overrides output.spec-path")
+	flags.StringVar(&f.routesProviderPkg, "routes-provider-pkg", "", "package of routes provider constructor, overrides input.routes-provider-pkg")
+	flags.StringVar(&f.routesProviderCtor, "routes-provider-ctor", "", "routes provider constructor, overrides input.routes-provider-ctor")
+	flags.IntVar(&f.concurrency, "concurrency", 0, "maximum concurrent package parsing operations, overrides concurrency")
+}
+
+// load reads config, overrides its keys with flags, validates it and configures logging.
+// Generated program inherits overridden keys
+func (f *configFlags) load() (generator.Config, error) {
+	cfg, err := generator.ReadConfig(f.path)
+	if err != nil {
+		return generator.Config{}, fmt.Errorf("load config: %w", err)
+	}
+
+	if f.noCache {
+		cfg.Cache.NoCache = true
+	}
+
+	if f.werror {
+		cfg.Werror = true
+	}
+
+	if f.logFormat != "" {
+		cfg.Log.Format = f.logFormat
+	}
+
+	if f.logLevel != "" {
+		cfg.Log.Level = f.logLevel
+	}
+
+	if f.reportPath != "" {
+		cfg.Output.Report.Path = f.reportPath
+	}
+
+	if f.minCoverage > 0 {
+		cfg.Output.Report.MinCoverage = f.minCoverage
+	}
+
+	if f.outputPath != "" {
+		cfg.Output.Path = f.outputPath
+	}
+
+	if f.specPath != "" {
+		cfg.Output.SpecPath = f.specPath
+	}
+
+	if f.routesProviderPkg != "" {
+		cfg.Input.RoutesProviderPkg = f.routesProviderPkg
+	}
+
+	if f.routesProviderCtor != "" {
+		cfg.Input.RoutesProviderCtor = f.routesProviderCtor
+	}
+
+	if f.concurrency > 0 {
+		cfg.Concurrency = f.concurrency
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return generator.Config{}, fmt.Errorf("validate config: %w", err)
+	}
+
+	if err := logging.Configure(os.Stderr, cfg.Log.Format, cfg.LogLevel()); err != nil {
+		return generator.Config{}, fmt.Errorf("configure logging: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// newFlagSet creates flag set of command, printing usage line and summary before flags
+func newFlagSet(name, args, summary string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: typed %s %s\n\n%s\n\nFlags:\n", name, args, summary)
+		flags.PrintDefaults()
+	}
+	return flags
+}
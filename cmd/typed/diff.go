@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/d1vbyz3r0/typed"
+)
+
+// runDiffCommand exits with code 1, when breaking changes are found, and with code 2 on errors
+func runDiffCommand(args []string) error {
+	breaking, err := runDiff(args)
+	if err != nil {
+		return exitError{code: 2, err: err}
+	}
+	if breaking {
+		return exitError{code: 1}
+	}
+	return nil
+}
+
+// runDiff compares two specs and prints report of changes. It reports, whether breaking changes were found
+func runDiff(args []string) (bool, error) {
+	flags := newFlagSet("diff", "[-format text|json] old.yaml new.yaml", "Compares two specs and prints added, removed and changed operations and\n"+
+		"schemas. Exits with code 1, when breaking changes are found.")
+	format := flags.String("format", "text", "report format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return false, errors.New("old and new spec paths are required")
+	}
+
+	base, err := typed.LoadSpec(flags.Arg(0))
+	if err != nil {
+		return false, err
+	}
+
+	revision, err := typed.LoadSpec(flags.Arg(1))
+	if err != nil {
+		return false, err
+	}
+
+	diff := typed.DiffSpecs(base, revision)
+	switch *format {
+	case "text":
+		fmt.Println(diff)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			return false, fmt.Errorf("encode report: %w", err)
+		}
+	default:
+		return false, fmt.Errorf("unknown report format: %s", *format)
+	}
+
+	return diff.HasBreaking(), nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/d1vbyz3r0/typed/internal/generator"
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+// runGenerate renders spec builder program. In static mode spec is written in one process
+func runGenerate(args []string) error {
+	flags := newFlagSet("generate", "[flags]", "Renders spec builder program to output.path, run it to write spec.\n"+
+		"With -static, spec is written from go/types in one process.")
+	var cf configFlags
+	cf.register(flags)
+	static := flags.Bool("static", false, "generate spec in one process from go/types, without running spec builder program")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	g, err := generator.New(cfg)
+	if err != nil {
+		return fmt.Errorf("create generator: %w", err)
+	}
+
+	if *static {
+		if err := g.GenerateStatic(); err != nil {
+			return fmt.Errorf("failed to generate spec: %w", err)
+		}
+		return nil
+	}
+
+	if err := g.Generate(); err != nil {
+		return fmt.Errorf("failed to generate spec builder: %w", err)
+	}
+	return nil
+}
+
+// runRun renders spec builder program, builds it in temporary directory and runs it, so spec is written
+func runRun(args []string) error {
+	flags := newFlagSet("run", "[flags]", "Renders spec builder program to output.path, builds it in temporary directory\n"+
+		"and runs it, so spec is written to output.spec-path or output.specs.")
+	var cf configFlags
+	cf.register(flags)
+	static := flags.Bool("static", false, "generate spec in one process from go/types, without running spec builder program")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	g, err := generator.New(cfg)
+	if err != nil {
+		return fmt.Errorf("create generator: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := g.GenerateSpec(ctx, *static); err != nil {
+		return err
+	}
+
+	logging.Info("spec generated", "spec_path", cfg.Output.SpecPath, "specs", len(cfg.Output.Specs))
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/d1vbyz3r0/typed/internal/generator"
+)
+
+// runInit writes config, created from echo handlers and routes providers of module
+func runInit(args []string) error {
+	flags := newFlagSet("init", "[flags]", "Scans packages of module for echo handlers and typed.RoutesProvider\n"+
+		"constructors and writes config for them.")
+	var (
+		configPath = flags.String("config", "typed.yaml", "path of written config file")
+		dir        = flags.String("dir", ".", "module directory to scan, config paths are relative to it")
+		force      = flags.Bool("force", false, "overwrite existing config file")
+		outputPath = flags.String("output", "", "path of generated spec builder source, defaults to gen/spec.go")
+		specPath   = flags.String("spec-path", "", "path of generated spec, defaults to gen/openapi.yaml")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(*configPath); err == nil && !*force {
+		return fmt.Errorf("config %s already exists, use -force to overwrite it", *configPath)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("check config: %w", err)
+	}
+
+	s, err := generator.NewScaffold(*dir)
+	if err != nil {
+		return fmt.Errorf("scan module: %w", err)
+	}
+
+	if *outputPath != "" {
+		s.Config.Output.Path = *outputPath
+	}
+
+	if *specPath != "" {
+		s.Config.Output.SpecPath = *specPath
+	}
+
+	var buf bytes.Buffer
+	if err := s.Write(&buf); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*configPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "config written to %s: %d handler package(s), %d routes provider constructor(s)\n",
+		*configPath, len(s.Config.Input.Handlers), len(s.Ctors))
+	if len(s.Ctors) == 0 {
+		fmt.Fprintln(os.Stderr, "routes provider constructor wasn't found, set input.routes-provider-ctor and input.routes-provider-pkg")
+	}
+	return nil
+}
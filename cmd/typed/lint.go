@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/d1vbyz3r0/typed/internal/generator"
+)
+
+// runLint prints parser diagnostics of configured packages. Exit code is 1, when errors are reported,
+// or any diagnostics with -Werror
+func runLint(args []string) error {
+	flags := newFlagSet("lint", "[flags]", "Parses configured handlers and models packages and prints diagnostics of\n"+
+		"constructs, which can't be represented in spec. Exits with code 1 on errors,\n"+
+		"or on any diagnostic with -Werror.")
+	var cf configFlags
+	cf.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	g, err := generator.New(cfg)
+	if err != nil {
+		return fmt.Errorf("create generator: %w", err)
+	}
+
+	if err := g.Lint(os.Stdout); err != nil {
+		return exitError{code: 1, err: err}
+	}
+	return nil
+}
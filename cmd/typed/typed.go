@@ -1,20 +1,51 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"runtime/debug"
-
-	"github.com/d1vbyz3r0/typed/internal/generator"
-	"github.com/d1vbyz3r0/typed/logging"
+	"strings"
 )
 
-var (
-	configPath = flag.String("config", "", "path to config file")
-	version    = flag.Bool("version", false, "print version and exit")
-)
+// command is a subcommand of typed. Flags of command are parsed by run, so -help prints its usage
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// exitError makes typed exit with code. Error is printed, when it's set
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+var commands = []command{
+	{name: "init", summary: "scaffold config from echo handlers and routes providers of module", run: runInit},
+	{name: "generate", summary: "render spec builder program, or write spec with -static", run: runGenerate},
+	{name: "run", summary: "render, build and run spec builder program, so spec is written", run: runRun},
+	{name: "lint", summary: "print parser diagnostics of handlers without generating spec", run: runLint},
+	{name: "watch", summary: "regenerate spec on changes of handlers and models", run: runWatch},
+	{name: "diff", summary: "compare two specs and report breaking changes", run: runDiffCommand},
+	{name: "version", summary: "print version", run: func([]string) error {
+		fmt.Println(getVersion())
+		return nil
+	}},
+}
 
 func getVersion() (version string) {
 	if b, ok := debug.ReadBuildInfo(); ok && len(b.Main.Version) > 0 {
@@ -25,34 +56,60 @@ func getVersion() (version string) {
 	return
 }
 
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "usage: typed <command> [flags]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, `Run "typed <command> -help" for flags of command. Without command, flags are passed to generate.`)
+}
+
 func main() {
-	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("typed: ")
 
-	if *version {
-		fmt.Println(getVersion())
+	args := os.Args[1:]
+	name := "generate"
+	switch {
+	case len(args) == 0:
+		usage()
+		os.Exit(2)
+	case args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help":
+		usage()
 		return
+	case args[0] == "-version" || args[0] == "--version":
+		name = "version"
+		args = args[1:]
+	case !strings.HasPrefix(args[0], "-"):
+		name = args[0]
+		args = args[1:]
 	}
 
-	if *configPath == "" {
-		log.Fatal("config path not provided")
-	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
 
-	cfg, err := generator.LoadConfig(*configPath)
-	if err != nil {
-		log.Fatalf("load config: %v", err)
-	}
+		err := c.run(args)
+		if err == nil {
+			return
+		}
 
-	if cfg.Debug {
-		logging.SetDefault(logging.NewStdLogger(os.Stderr, logging.LevelDebug))
+		var exitErr exitError
+		if errors.As(err, &exitErr) {
+			if exitErr.err != nil {
+				log.Printf("%s: %v", name, exitErr.err)
+			}
+			os.Exit(exitErr.code)
+		}
+		log.Fatalf("%s: %v", name, err)
 	}
 
-	g, err := generator.New(cfg)
-	if err != nil {
-		log.Fatalf("create generator: %v", err)
-	}
-
-	err = g.Generate()
-	if err != nil {
-		log.Fatalf("failed to generate spec builder: %v", err)
-	}
+	log.Printf("unknown command %q", name)
+	usage()
+	os.Exit(2)
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/d1vbyz3r0/typed/internal/generator"
+	"github.com/d1vbyz3r0/typed/internal/specdiff"
+	"github.com/d1vbyz3r0/typed/internal/watch"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/packages"
+)
+
+// runWatch watches inputs from config and regenerates spec on changes
+func runWatch(args []string) error {
+	flags := newFlagSet("watch", "[flags]", "Watches handlers, models and routes provider directories, config and overlays\n"+
+		"and regenerates spec on changes, printing summary of changed operations and schemas.")
+	var cf configFlags
+	cf.register(flags)
+	var (
+		static   = flags.Bool("static", false, "generate spec in one process from go/types, without running spec builder program")
+		interval = flags.Duration("interval", 500*time.Millisecond, "interval between polling of watched directories")
+		debounce = flags.Duration("debounce", 300*time.Millisecond, "delay without changes before regeneration")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Output.SpecPath == "" {
+		return errors.New("spec-path is required in watch mode")
+	}
+
+	dirs, files := watchedInputs(cfg, cf.path)
+	w, err := watch.New(
+		dirs,
+		watch.WithInterval(*interval),
+		watch.WithDebounce(*debounce),
+		watch.WithIgnore(cfg.Output.Path),
+		watch.WithFiles(files...),
+	)
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	generateSpec(ctx, cfg, *static)
+	fmt.Fprintln(os.Stderr, "watching for changes, press Ctrl+C to stop")
+
+	return w.Run(ctx, func(changed []string) {
+		fmt.Fprintf(os.Stderr, "%d file(s) changed, regenerating\n", len(changed))
+		regenerate(ctx, &cf, w, *static)
+	})
+}
+
+// regenerate reloads config, updates watched inputs with it and regenerates spec. Errors are printed only,
+// so watching continues after fixing them
+func regenerate(ctx context.Context, cf *configFlags, w *watch.Watcher, static bool) {
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+
+	if cfg.Output.SpecPath == "" {
+		fmt.Fprintln(os.Stderr, "spec-path is required in watch mode")
+		return
+	}
+
+	dirs, files := watchedInputs(cfg, cf.path)
+	if err := w.Update(dirs, watch.WithIgnore(cfg.Output.Path), watch.WithFiles(files...)); err != nil {
+		fmt.Fprintf(os.Stderr, "update watched inputs: %v\n", err)
+	}
+
+	generateSpec(ctx, cfg, static)
+}
+
+// watchedInputs returns directories of handlers, models and routes provider packages and paths of config and
+// overlays files, which affect generated spec
+func watchedInputs(cfg generator.Config, configPath string) ([]watch.Dir, []string) {
+	var dirs []watch.Dir
+	for _, h := range cfg.Input.Handlers {
+		dirs = append(dirs, watch.Dir{Path: h.Path, Recursive: h.Recursive})
+	}
+	for _, m := range cfg.Input.Models {
+		dirs = append(dirs, watch.Dir{Path: m.Path, Recursive: m.Recursive})
+	}
+
+	if cfg.Input.RoutesProviderPkg != "" {
+		dir, err := packageDir(cfg.Input.RoutesProviderPkg)
+		if err != nil {
+			logging.Warn("failed to find routes provider package directory, it's not watched", "pkg", cfg.Input.RoutesProviderPkg, "error", err)
+		} else {
+			dirs = append(dirs, watch.Dir{Path: dir})
+		}
+	}
+
+	files := append([]string{configPath}, cfg.Input.Overlays...)
+	for _, s := range cfg.Output.Specs {
+		files = append(files, s.Overlays...)
+	}
+	return dirs, files
+}
+
+// packageDir returns directory of package with pkgPath
+func packageDir(pkgPath string) (string, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles}, pkgPath)
+	if err != nil {
+		return "", fmt.Errorf("load package: %w", err)
+	}
+
+	if len(pkgs) == 0 || len(pkgs[0].GoFiles) == 0 {
+		return "", fmt.Errorf("package %s has no Go files", pkgPath)
+	}
+	return filepath.Dir(pkgs[0].GoFiles[0]), nil
+}
+
+// generateSpec generates spec and prints summary of its changes. Errors are printed only
+func generateSpec(ctx context.Context, cfg generator.Config, static bool) {
+	started := time.Now()
+	prev, err := loadSpec(cfg.Output.SpecPath)
+	if err != nil {
+		logging.Warn("failed to load previous spec, all operations are reported as added", "path", cfg.Output.SpecPath, "error", err)
+	}
+
+	g, err := generator.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create generator: %v\n", err)
+		return
+	}
+
+	if err := g.GenerateSpec(ctx, static); err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "generate spec: %v\n", err)
+		}
+		return
+	}
+
+	next, err := loadSpec(cfg.Output.SpecPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load generated spec: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "spec regenerated in %s\n", time.Since(started).Round(time.Millisecond))
+	fmt.Println(specdiff.Summarize(prev, next))
+}
+
+// loadSpec loads spec from path. Nil spec is returned, if file doesn't exist
+func loadSpec(path string) (*openapi3.T, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return openapi3.NewLoader().LoadFromFile(path)
+}
@@ -95,6 +95,7 @@ func GetPkgPath(t types.Type) (string, error) {
 	}
 }
 
+// GetFuncDocumentation returns doc comment of function without directives, such as //typed:ignore
 func GetFuncDocumentation(funcDecl *ast.FuncDecl) string {
 	if funcDecl.Doc == nil {
 		return ""
@@ -102,13 +103,56 @@ func GetFuncDocumentation(funcDecl *ast.FuncDecl) string {
 
 	var doc strings.Builder
 	for _, comment := range funcDecl.Doc.List {
-		doc.WriteString(strings.TrimPrefix(comment.Text, "// "))
+		if isDirective(comment.Text) {
+			continue
+		}
+		text := strings.TrimPrefix(comment.Text, "//")
+		doc.WriteString(strings.TrimPrefix(text, " "))
 		doc.WriteString("\n")
 	}
 
 	return strings.TrimSpace(doc.String())
 }
 
+// HasDirective reports if doc comment contains directive line, ex: //typed:ignore
+func HasDirective(doc *ast.CommentGroup, directive string) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, comment := range doc.List {
+		if !isDirective(comment.Text) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(comment.Text, "//"), " ")
+		if name == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// isDirective reports if comment is a directive in //tool:name format, like //go:generate
+func isDirective(comment string) bool {
+	text, ok := strings.CutPrefix(comment, "//")
+	if !ok {
+		return false
+	}
+
+	tool, name, ok := strings.Cut(text, ":")
+	if !ok || tool == "" || name == "" {
+		return false
+	}
+
+	isLowerAlnum := func(s string) bool {
+		return !strings.ContainsFunc(s, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+	}
+	return isLowerAlnum(tool) && (name[0] >= 'a' && name[0] <= 'z')
+}
+
 func GetCalledFuncPkg(call *ast.CallExpr) (string, bool) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
@@ -138,3 +182,66 @@ func GetPkgName(pkgPath string) string {
 	parts := strings.Split(pkgPath, "/")
 	return parts[len(parts)-1]
 }
+
+// GetRecvName returns receiver type name of method, prefixed with * for pointer receivers, such as *Server.
+// Type parameters of generic receiver are omitted. Empty string is returned for functions
+func GetRecvName(fn *types.Func) string {
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return ""
+	}
+	return GetRecvTypeName(recv.Type())
+}
+
+// GetRecvTypeName returns name of receiver type, prefixed with * for pointers, such as *Server
+func GetRecvTypeName(t types.Type) string {
+	prefix := ""
+	if ptr, ok := t.(*types.Pointer); ok {
+		t, prefix = ptr.Elem(), "*"
+	}
+
+	switch t := t.(type) {
+	case *types.Named:
+		return prefix + t.Obj().Name()
+	case *types.Alias:
+		return prefix + t.Obj().Name()
+	default:
+		return prefix + t.String()
+	}
+}
+
+// FuncKey returns key of function in format <pkg path>.<func name> or method in format
+// (<pkg path>.<recv type>).<method name>, such as (*github.com/acme/api.Server).List, so methods of different
+// types with same name have different keys
+func FuncKey(pkgPath, recv, name string) string {
+	if recv == "" {
+		return pkgPath + "." + name
+	}
+
+	ptr := ""
+	if strings.HasPrefix(recv, "*") {
+		ptr, recv = "*", recv[1:]
+	}
+	return "(" + ptr + pkgPath + "." + recv + ")." + name
+}
+
+// ClosureNames returns names of function literals, declared directly in function body, in the same format as
+// compiler names them: <func name>.func<N>, where N is a number of literal in source order. Nested literals are
+// not returned
+func ClosureNames(decl *ast.FuncDecl) map[*ast.FuncLit]string {
+	if decl.Body == nil {
+		return nil
+	}
+
+	res := make(map[*ast.FuncLit]string)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		res[lit] = fmt.Sprintf("%s.func%d", decl.Name.Name, len(res)+1)
+		return false
+	})
+	return res
+}
@@ -1,6 +1,9 @@
 package meta
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"go/types"
 	"testing"
 
@@ -143,3 +146,45 @@ func TestGetPkgName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFuncDocumentation(t *testing.T) {
+	cases := []struct {
+		name        string
+		src         string
+		wantDoc     string
+		wantIgnored bool
+	}{
+		{
+			name:    "plain",
+			src:     "// Handler returns user\n//\n// Second line\nfunc Handler() {}",
+			wantDoc: "Handler returns user\n\nSecond line",
+		},
+		{
+			name:        "directive",
+			src:         "// Handler returns user\n//\n//typed:ignore\nfunc Handler() {}",
+			wantDoc:     "Handler returns user",
+			wantIgnored: true,
+		},
+		{
+			name:    "other directive",
+			src:     "//go:noinline\nfunc Handler() {}",
+			wantDoc: "",
+		},
+		{
+			name:    "not a directive",
+			src:     "// typed:ignore is a directive\nfunc Handler() {}",
+			wantDoc: "typed:ignore is a directive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "handler.go", "package p\n"+tc.src, parser.ParseComments)
+			require.NoError(t, err)
+
+			decl := file.Decls[0].(*ast.FuncDecl)
+			require.Equal(t, tc.wantDoc, GetFuncDocumentation(decl))
+			require.Equal(t, tc.wantIgnored, HasDirective(decl.Doc, "typed:ignore"))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package typing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+)
+
+// typeJSON is a JSON representation of Type
+type typeJSON struct {
+	Kind          TypeKind             `json:"kind"`
+	Name          string               `json:"name,omitempty"`
+	Pkg           string               `json:"pkg,omitempty"`
+	Elem          *Type                `json:"elem,omitempty"`
+	Size          int64                `json:"size,omitempty"`
+	Params        []*Type              `json:"params,omitempty"`
+	EnumValues    []enumValueJSON      `json:"enum_values,omitempty"`
+	Impls         []implementationJSON `json:"impls,omitempty"`
+	Discriminator string               `json:"discriminator,omitempty"`
+}
+
+type implementationJSON struct {
+	Type               *Type  `json:"type"`
+	DiscriminatorValue string `json:"discriminator_value,omitempty"`
+}
+
+// enumValueJSON keeps Go type of enum value, since JSON numbers are decoded as float64
+type enumValueJSON struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (t *Type) MarshalJSON() ([]byte, error) {
+	v := typeJSON{
+		Kind:          t.kind,
+		Name:          t.name,
+		Pkg:           t.pkg,
+		Elem:          t.elem,
+		Size:          t.size,
+		Params:        t.params,
+		Discriminator: t.discriminator,
+	}
+
+	for _, val := range t.enumValues {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("marshal enum value %v: %w", val, err)
+		}
+
+		kind := reflect.TypeOf(val).Kind()
+		switch kind {
+		case reflect.String, reflect.Bool, reflect.Int64, reflect.Uint64, reflect.Float64:
+		default:
+			return nil, fmt.Errorf("%w: enum value of kind %s", ErrTypeUnsupported, kind)
+		}
+		v.EnumValues = append(v.EnumValues, enumValueJSON{Kind: kind.String(), Value: raw})
+	}
+
+	for _, impl := range t.impls {
+		v.Impls = append(v.Impls, implementationJSON{Type: impl.Type, DiscriminatorValue: impl.DiscriminatorValue})
+	}
+
+	return json.Marshal(v)
+}
+
+func (t *Type) UnmarshalJSON(data []byte) error {
+	var v typeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*t = Type{
+		kind:          v.Kind,
+		name:          v.Name,
+		pkg:           v.Pkg,
+		elem:          v.Elem,
+		size:          v.Size,
+		params:        v.Params,
+		discriminator: v.Discriminator,
+	}
+
+	for _, val := range v.EnumValues {
+		var (
+			res any
+			err error
+		)
+
+		switch val.Kind {
+		case reflect.String.String():
+			res, err = unmarshalValue[string](val.Value)
+		case reflect.Bool.String():
+			res, err = unmarshalValue[bool](val.Value)
+		case reflect.Int64.String():
+			res, err = unmarshalValue[int64](val.Value)
+		case reflect.Uint64.String():
+			res, err = unmarshalValue[uint64](val.Value)
+		case reflect.Float64.String():
+			res, err = unmarshalValue[float64](val.Value)
+		default:
+			err = fmt.Errorf("%w: enum value of kind %s", ErrTypeUnsupported, val.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("unmarshal enum value: %w", err)
+		}
+		t.enumValues = append(t.enumValues, res)
+	}
+
+	for _, impl := range v.Impls {
+		t.impls = append(t.impls, Implementation{Type: impl.Type, DiscriminatorValue: impl.DiscriminatorValue})
+	}
+
+	return nil
+}
+
+func unmarshalValue[T any](data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// reflectTypes holds reflect types of parameters and inline form fields, which can be encoded by MarshalReflectType
+var reflectTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, t := range []reflect.Type{
+		reflect.TypeFor[string](),
+		IntType,
+		Int64Type,
+		UintType,
+		Float64Type,
+		BoolType,
+		UuidType,
+		TimeType,
+		reflect.TypeFor[*multipart.FileHeader](),
+	} {
+		reflectTypes[t.String()] = t
+	}
+}
+
+// reflectTypeJSON is a JSON representation of reflect.Type. Known types are stored by name,
+// structs built with reflect.StructOf are stored as list of fields
+type reflectTypeJSON struct {
+	Name   string            `json:"name,omitempty"`
+	Fields []structFieldJSON `json:"fields,omitempty"`
+}
+
+type structFieldJSON struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+	Tag  string          `json:"tag,omitempty"`
+}
+
+// MarshalReflectType encodes reflect.Type of parameter or inline form. Supported types are the ones returned by
+// built-in type providers and anonymous structs of them. Types of custom providers are not supported
+func MarshalReflectType(t reflect.Type) ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	if t.Kind() == reflect.Struct && t.Name() == "" {
+		v := reflectTypeJSON{Fields: make([]structFieldJSON, 0, t.NumField())}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			ft, err := MarshalReflectType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			v.Fields = append(v.Fields, structFieldJSON{Name: field.Name, Type: ft, Tag: string(field.Tag)})
+		}
+		return json.Marshal(v)
+	}
+
+	if _, ok := reflectTypes[t.String()]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTypeUnsupported, t)
+	}
+	return json.Marshal(reflectTypeJSON{Name: t.String()})
+}
+
+// UnmarshalReflectType decodes reflect.Type, encoded with MarshalReflectType
+func UnmarshalReflectType(data []byte) (reflect.Type, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	var v reflectTypeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	if v.Name != "" {
+		t, ok := reflectTypes[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypeUnsupported, v.Name)
+		}
+		return t, nil
+	}
+
+	fields := make([]reflect.StructField, 0, len(v.Fields))
+	for _, field := range v.Fields {
+		ft, err := UnmarshalReflectType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fields = append(fields, reflect.StructField{Name: field.Name, Type: ft, Tag: reflect.StructTag(field.Tag)})
+	}
+	return reflect.StructOf(fields), nil
+}
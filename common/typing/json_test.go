@@ -0,0 +1,75 @@
+package typing
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestType_JSON(t *testing.T) {
+	user := Named("github.com/acme/dto", "User")
+	tests := []struct {
+		name string
+		typ  *Type
+	}{
+		{name: "basic", typ: Basic("string")},
+		{name: "named generic", typ: Named("github.com/acme/dto", "Page", user)},
+		{name: "pointer", typ: Pointer(user)},
+		{name: "slice", typ: Slice(user)},
+		{name: "array", typ: Array(Basic("int"), 3)},
+		{name: "map", typ: Map(Basic("string"), Slice(user))},
+		{name: "type param", typ: TypeParam("Req")},
+		{
+			name: "enum",
+			typ: Enum(Named("github.com/acme/dto", "Status"), []any{
+				"active", true, int64(-1), uint64(18446744073709551615), 1.5,
+			}),
+		},
+		{
+			name: "interface",
+			typ: Interface(Named("github.com/acme/dto", "Event"), "type",
+				Implementation{Type: Named("github.com/acme/dto", "Created"), DiscriminatorValue: "created"},
+				Implementation{Type: Named("github.com/acme/dto", "Deleted")},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.typ)
+			require.NoError(t, err)
+
+			got := new(Type)
+			require.NoError(t, json.Unmarshal(data, got))
+			require.Equal(t, tt.typ, got)
+		})
+	}
+}
+
+func TestType_JSONUnsupportedEnumValue(t *testing.T) {
+	_, err := json.Marshal(Enum(Basic("int"), []any{1}))
+	require.ErrorIs(t, err, ErrTypeUnsupported)
+}
+
+func TestReflectType_JSON(t *testing.T) {
+	form := reflect.StructOf([]reflect.StructField{
+		{Name: "Avatar", Type: reflect.TypeFor[*multipart.FileHeader](), Tag: `form:"avatar"`},
+		{Name: "Id", Type: UuidType, Tag: `form:"id"`},
+		{Name: "Name", Type: reflect.TypeFor[string](), Tag: `form:"name"`},
+	})
+
+	for _, typ := range []reflect.Type{nil, IntType, TimeType, form} {
+		data, err := MarshalReflectType(typ)
+		require.NoError(t, err)
+
+		got, err := UnmarshalReflectType(data)
+		require.NoError(t, err)
+		require.Equal(t, typ, got)
+	}
+
+	_, err := MarshalReflectType(reflect.TypeFor[complex64]())
+	require.ErrorIs(t, err, ErrTypeUnsupported)
+}
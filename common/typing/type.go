@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"go/types"
+	"slices"
 	"strings"
 )
 
@@ -30,8 +31,18 @@ const (
 	TypeKindArray
 	TypeKindMap
 	TypeKindEnum
+	TypeKindInterface
+	// TypeKindTypeParam is a type parameter of generic function, it's substituted with type argument of instance
+	TypeKindTypeParam
 )
 
+// Implementation describes named type, implementing TypeKindInterface type.
+// DiscriminatorValue is a value of discriminator property for this type, it can be empty.
+type Implementation struct {
+	Type               *Type
+	DiscriminatorValue string
+}
+
 type Type struct {
 	kind TypeKind
 	// name is a type name as it was used with type XXX ... declaration.
@@ -49,6 +60,10 @@ type Type struct {
 	params []*Type
 	// enumValues holds possible enum values for TypeKindEnum
 	enumValues []any
+	// impls holds types implementing TypeKindInterface
+	impls []Implementation
+	// discriminator holds name of property, which tells implementations apart for TypeKindInterface
+	discriminator string
 }
 
 func fillType(t types.Type, _type *Type) error {
@@ -98,6 +113,11 @@ func fillType(t types.Type, _type *Type) error {
 		_type.name = tt.Name()
 		return nil
 
+	case *types.TypeParam:
+		_type.kind = TypeKindTypeParam
+		_type.name = tt.Obj().Name()
+		return nil
+
 	case *types.Named:
 		obj := tt.Obj()
 		pkgPath := ""
@@ -105,6 +125,19 @@ func fillType(t types.Type, _type *Type) error {
 			pkgPath = pkg.Path()
 		}
 
+		if iface, ok := tt.Underlying().(*types.Interface); ok && !iface.Empty() {
+			if pkgPath == "" {
+				return fmt.Errorf("%w: %s (only interfaces declared in packages supported)", ErrTypeUnsupported, tt)
+			}
+
+			// implementations are unknown at this point, they are resolved across all loaded packages later
+			_type.kind = TypeKindInterface
+			_type.name = obj.Name()
+			_type.pkg = pkgPath
+			_type.elem = Named(pkgPath, obj.Name())
+			return nil
+		}
+
 		_type.kind = TypeKindNamed
 		_type.name = obj.Name()
 		_type.pkg = pkgPath
@@ -157,6 +190,16 @@ func (t *Type) EnumValues() []any {
 	return t.enumValues
 }
 
+// Implementations returns types implementing interface if current type is TypeKindInterface, otherwise nil
+func (t *Type) Implementations() []Implementation {
+	return t.impls
+}
+
+// Discriminator returns discriminator property name if current type is TypeKindInterface, otherwise empty string
+func (t *Type) Discriminator() string {
+	return t.discriminator
+}
+
 // KeyType returns type descriptor for key if current type is map, otherwise nil
 func (t *Type) KeyType() *Type {
 	if t.kind != TypeKindMap {
@@ -183,11 +226,58 @@ func (t *Type) ElemType() *Type {
 	}
 }
 
+// Size returns array length if current type is array, otherwise 0
+func (t *Type) Size() int64 {
+	return t.size
+}
+
 // Params returns slice of params, if type is generic, otherwise slice will be nil
 func (t *Type) Params() []*Type {
 	return t.params
 }
 
+// ContainsTypeParams reports if type or any of its elems and params is a type parameter
+func (t *Type) ContainsTypeParams() bool {
+	if t == nil {
+		return false
+	}
+
+	switch t.kind {
+	case TypeKindTypeParam:
+		return true
+	case TypeKindPointer, TypeKindArray, TypeKindSlice, TypeKindEnum:
+		return t.elem.ContainsTypeParams()
+	case TypeKindNamed, TypeKindMap:
+		return slices.ContainsFunc(t.params, (*Type).ContainsTypeParams)
+	}
+	return false
+}
+
+// Substitute returns copy of type with type parameters replaced by args, keyed by type parameter name.
+// Type parameters without args are kept as is
+func (t *Type) Substitute(args map[string]*Type) *Type {
+	if t == nil || !t.ContainsTypeParams() {
+		return t
+	}
+
+	if t.kind == TypeKindTypeParam {
+		if arg, ok := args[t.name]; ok {
+			return arg
+		}
+		return t
+	}
+
+	res := *t
+	res.elem = t.elem.Substitute(args)
+	if t.params != nil {
+		res.params = make([]*Type, len(t.params))
+		for i, param := range t.params {
+			res.params[i] = param.Substitute(args)
+		}
+	}
+	return &res
+}
+
 // String formats type using Namer
 func (t *Type) String() string {
 	return t.format(Namer)
@@ -207,7 +297,7 @@ func (t *Type) format(namer NamerFunc) string {
 	case TypeKindMap:
 		return fmt.Sprintf("map[%s]%s", t.params[0].format(namer), t.params[1].format(namer))
 
-	case TypeKindBasic:
+	case TypeKindBasic, TypeKindTypeParam:
 		return t.name
 
 	case TypeKindNamed:
@@ -223,7 +313,7 @@ func (t *Type) format(namer NamerFunc) string {
 		}
 		return res
 
-	case TypeKindEnum:
+	case TypeKindEnum, TypeKindInterface:
 		return t.elem.format(namer)
 	}
 
@@ -235,6 +325,15 @@ func ToString(t *Type, namer NamerFunc) string {
 	return t.format(namer)
 }
 
+// FormatTypeArgs formats type arguments of generic instance as [T1,T2] using Namer. It returns empty string for no args
+func FormatTypeArgs(args []*Type) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return "[" + strings.Join(forEach(args, (*Type).String), ",") + "]"
+}
+
 func forEach(s []*Type, fn func(t *Type) string) []string {
 	res := make([]string, 0, len(s))
 	for _, v := range s {
@@ -61,6 +61,14 @@ func Pointer(elem *Type) *Type {
 	}
 }
 
+// TypeParam creates type parameter descriptor with provided name
+func TypeParam(name string) *Type {
+	return &Type{
+		kind: TypeKindTypeParam,
+		name: name,
+	}
+}
+
 // Enum creates enum for provided type descriptor
 func Enum(elem *Type, values []any) *Type {
 	return &Type{
@@ -72,6 +80,19 @@ func Enum(elem *Type, values []any) *Type {
 	}
 }
 
+// Interface creates interface descriptor for provided named type with implementations.
+// discriminator can be empty, if implementations can't be told apart by property value
+func Interface(elem *Type, discriminator string, impls ...Implementation) *Type {
+	return &Type{
+		kind:          TypeKindInterface,
+		name:          elem.name,
+		pkg:           elem.pkg,
+		elem:          elem,
+		impls:         impls,
+		discriminator: discriminator,
+	}
+}
+
 // TypeTreeToString traverses provided type and builds a chain of constructors ready to use for templates in string format.
 // pkg specifes package prefix for generated calls
 func TypeTreeToString(pkg string, t *Type, namer NamerFunc) string {
@@ -106,6 +127,9 @@ func TypeTreeToString(pkg string, t *Type, namer NamerFunc) string {
 		case TypeKindBasic:
 			return fmt.Sprintf(`%s.Basic("%s")`, pkg, t.name)
 
+		case TypeKindTypeParam:
+			return fmt.Sprintf(`%s.TypeParam("%s")`, pkg, t.name)
+
 		case TypeKindNamed:
 			tpkg, tname := namer(t)
 			if t.IsGeneric() {
@@ -124,6 +148,17 @@ func TypeTreeToString(pkg string, t *Type, namer NamerFunc) string {
 			}
 			return fmt.Sprintf(`%s.Enum(%s, []any{%s})`, pkg, elem, strings.Join(enumVals, ", "))
 
+		case TypeKindInterface:
+			elem := walkFn(t.elem)
+			args := []string{elem, fmt.Sprintf("%q", t.discriminator)}
+			for _, impl := range t.impls {
+				args = append(args, fmt.Sprintf(
+					"%s.Implementation{Type: %s, DiscriminatorValue: %q}",
+					pkg, walkFn(impl.Type), impl.DiscriminatorValue,
+				))
+			}
+			return fmt.Sprintf(`%s.Interface(%s)`, pkg, strings.Join(args, ", "))
+
 		default:
 			return "UnsupportedType"
 		}
@@ -161,7 +196,7 @@ func Traverse(t *Type, fn func(n *Type)) error {
 		Traverse(t.params[1], fn)
 		return nil
 
-	case TypeKindBasic:
+	case TypeKindBasic, TypeKindTypeParam:
 		fn(t)
 		return nil
 
@@ -177,6 +212,13 @@ func Traverse(t *Type, fn func(n *Type)) error {
 		Traverse(t.elem, fn)
 		return nil
 
+	case TypeKindInterface:
+		fn(t)
+		for _, impl := range t.impls {
+			Traverse(impl.Type, fn)
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("unsupported type kind: %v", t.kind)
 	}
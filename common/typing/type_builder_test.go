@@ -150,6 +150,19 @@ func TestEnumType(t *testing.T) {
 	require.ElementsMatch(t, []any{"admin", "user", "guest"}, typ.enumValues, "unexpected values")
 }
 
+func TestInterfaceType(t *testing.T) {
+	base := Named("github.com/acme/event", "Event")
+	impl := Implementation{Type: Named("github.com/acme/event", "Created"), DiscriminatorValue: "created"}
+	typ := Interface(base, "type", impl)
+	require.NotNil(t, typ)
+
+	require.Equal(t, TypeKindInterface, typ.kind, "unexpected kind")
+	require.Equal(t, base, typ.elem, "unexpected elem")
+	require.Equal(t, "type", typ.Discriminator(), "unexpected discriminator")
+	require.Equal(t, []Implementation{impl}, typ.Implementations(), "unexpected implementations")
+	require.Equal(t, "github.com/acme/event.Event", typ.String())
+}
+
 func TestTypeTreeToString(t *testing.T) {
 	cases := []struct {
 		name  string
@@ -176,6 +189,15 @@ func TestTypeTreeToString(t *testing.T) {
 			_type: Map(Basic("string"), Basic("int")),
 			want:  `t.Map(t.Basic("string"), t.Basic("int"))`,
 		},
+		{
+			name: "interface with implementations",
+			_type: Interface(
+				Named("github.com/example/foo", "Event"),
+				"type",
+				Implementation{Type: Named("github.com/example/foo", "Created"), DiscriminatorValue: "created"},
+			),
+			want: `t.Interface(t.Named("github.com/example/foo", "Event"), "type", t.Implementation{Type: t.Named("github.com/example/foo", "Created"), DiscriminatorValue: "created"})`,
+		},
 		{
 			name:  "pointer to basic type",
 			_type: Pointer(Basic("int")),
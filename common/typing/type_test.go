@@ -147,6 +147,28 @@ func TestInteracesProcessing(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "named non-empty interface",
+			makeType: func(t *testing.T) types.Type {
+				pkg := types.NewPackage("github.com/example/foo", "foo")
+				results := types.NewTuple(types.NewParam(0, nil, "", types.Typ[types.String]))
+				signature := types.NewSignatureType(nil, nil, nil, nil, results, false)
+				method := types.NewFunc(0, pkg, "Type", signature)
+				iface := types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+				obj := types.NewTypeName(token.NoPos, pkg, "Event", nil)
+				return types.NewNamed(obj, iface, nil)
+			},
+			want:    Interface(Named("github.com/example/foo", "Event"), ""),
+			wantErr: false,
+		},
+		{
+			name: "error interface",
+			makeType: func(t *testing.T) types.Type {
+				return types.Universe.Lookup("error").Type()
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "slice of empty interfaces",
 			makeType: func(t *testing.T) types.Type {
@@ -494,3 +516,67 @@ func TestTypeString(t *testing.T) {
 		})
 	}
 }
+
+func TestFillType_TypeParam(t *testing.T) {
+	pkg := types.NewPackage("github.com/example/foo", "foo")
+
+	// func Handle[Req any]() echo.HandlerFunc
+	obj := types.NewTypeName(token.NoPos, pkg, "Req", nil)
+	tParam := types.NewTypeParam(obj, types.Universe.Lookup("any").Type())
+
+	var got Type
+	err := fillType(types.NewSlice(tParam), &got)
+	require.NoError(t, err)
+
+	assert.Equal(t, TypeKindSlice, got.kind)
+	assert.Equal(t, TypeKindTypeParam, got.elem.kind)
+	assert.Equal(t, "Req", got.elem.name)
+	assert.True(t, got.ContainsTypeParams())
+	assert.Equal(t, "[]Req", got.String())
+}
+
+func TestSubstitute(t *testing.T) {
+	args := map[string]*Type{
+		"Req":  Named("github.com/example/foo", "CreateUser"),
+		"Resp": Basic("string"),
+	}
+
+	cases := []struct {
+		name  string
+		_type *Type
+		want  *Type
+	}{
+		{
+			name:  "type param",
+			_type: TypeParam("Req"),
+			want:  Named("github.com/example/foo", "CreateUser"),
+		},
+		{
+			name:  "pointer to slice of type params",
+			_type: Pointer(Slice(TypeParam("Req"))),
+			want:  Pointer(Slice(Named("github.com/example/foo", "CreateUser"))),
+		},
+		{
+			name:  "generic named type and map",
+			_type: Named("github.com/example/foo", "Page", Map(Basic("string"), TypeParam("Resp"))),
+			want:  Named("github.com/example/foo", "Page", Map(Basic("string"), Basic("string"))),
+		},
+		{
+			name:  "type param without arg",
+			_type: TypeParam("Other"),
+			want:  TypeParam("Other"),
+		},
+		{
+			name:  "type without type params",
+			_type: Named("github.com/example/foo", "Named"),
+			want:  Named("github.com/example/foo", "Named"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc._type.Substitute(args)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package typed
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+// invalidComponentKeyChars matches characters not allowed by the OpenAPI component key regex ^[a-zA-Z0-9.\-_]+$
+var invalidComponentKeyChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+// RenameRule rewrites generated component names. Pattern is matched against the generated name
+// and Replacement may reference capture groups, as in regexp.Regexp.ReplaceAllString.
+type RenameRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+func NewRenameRule(pattern string, replacement string) (RenameRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return RenameRule{}, fmt.Errorf("compile rename pattern %q: %w", pattern, err)
+	}
+
+	return RenameRule{
+		Pattern:     re,
+		Replacement: replacement,
+	}, nil
+}
+
+func MustNewRenameRule(pattern string, replacement string) RenameRule {
+	r, err := NewRenameRule(pattern, replacement)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// SanitizeComponentName replaces all characters, that are not allowed in component keys, with underscore
+func SanitizeComponentName(name string) string {
+	return invalidComponentKeyChars.ReplaceAllString(name, "_")
+}
+
+// namedComponent is a named struct type, identified by key.
+// Keys are reflect.Type for runtime types and type strings for go/types types.
+type namedComponent struct {
+	key  any
+	pkg  string
+	name string
+}
+
+type anonStruct struct {
+	key    any
+	parent any
+	field  string
+}
+
+// ComponentNamer generates stable component names for reflect types.
+//
+// Named types are prefixed with the shortest package path suffix, which makes the name unique
+// among all types reachable from the registry (dto.User, a.dto.User and b.dto.User for conflicts).
+// Generic instantiations are spelled out (dto.Page[dto.User] -> dto.PageOfUser) and anonymous
+// structs are named after their parent field, or after the hint provided with Hint.
+type ComponentNamer struct {
+	rules     []RenameRule
+	names     map[any]string
+	taken     map[string]any
+	hints     map[any]string
+	anonCount int
+}
+
+// NewComponentNamer creates namer and assigns names to all types reachable from registry values.
+// Registry is walked in sorted order, so names are the same between runs.
+func NewComponentNamer(registry *Registry, rules ...RenameRule) *ComponentNamer {
+	n := &ComponentNamer{
+		rules: rules,
+		names: make(map[any]string),
+		taken: make(map[string]any),
+		hints: make(map[any]string),
+	}
+
+	if registry == nil {
+		return n
+	}
+
+	var (
+		named []namedComponent
+		anons []anonStruct
+	)
+	seen := make(map[reflect.Type]struct{})
+	for v := range registry.Values() {
+		collectComponentTypes(reflect.TypeOf(v), seen, &named, &anons)
+	}
+
+	n.assignAll(named, anons)
+	return n
+}
+
+// assignAll assigns names to all provided components at once.
+// All conflicting types must be known at once to pick the shortest unique package suffix for each of them.
+func (n *ComponentNamer) assignAll(named []namedComponent, anons []anonStruct) {
+	groups := make(map[string][]namedComponent)
+	for _, c := range named {
+		base := componentBaseName(c.name)
+		groups[base] = append(groups[base], c)
+	}
+
+	bases := make([]string, 0, len(groups))
+	for base := range groups {
+		bases = append(bases, base)
+	}
+	slices.Sort(bases)
+
+	for _, base := range bases {
+		group := groups[base]
+		pkgs := make([]string, len(group))
+		for i, c := range group {
+			pkgs[i] = c.pkg
+		}
+
+		qualifiers := uniquePkgSuffixes(pkgs)
+		for i, c := range group {
+			n.assign(c.key, qualifyName(qualifiers[i], base))
+		}
+	}
+
+	// anonymous structs are collected in depth-first order, so parent always has name before its fields
+	for _, anon := range anons {
+		if _, ok := n.names[anon.key]; ok {
+			continue
+		}
+
+		parent, ok := n.names[anon.parent]
+		if !ok {
+			continue
+		}
+		n.assign(anon.key, parent+exportName(anon.field))
+	}
+}
+
+// NewTypeNameGenerator returns openapi3gen.TypeNameGenerator backed by ComponentNamer without rename rules.
+func NewTypeNameGenerator(registry *Registry) openapi3gen.TypeNameGenerator {
+	return NewComponentNamer(registry).TypeName
+}
+
+// Hint sets name for anonymous struct, which can't be named after parent field, such as inline forms.
+// Hint has no effect if type already has name.
+func (n *ComponentNamer) Hint(t reflect.Type, name string) {
+	t = typing.DerefReflectPtr(t)
+	if _, ok := n.names[t]; ok {
+		return
+	}
+	n.hints[t] = name
+}
+
+// syntheticKey identifies schema, which has no Go type
+type syntheticKey string
+
+// SyntheticName returns unique component name for schema without Go type, such as inline response object.
+// key identifies schema, the same name is returned for the same key.
+func (n *ComponentNamer) SyntheticName(key string, name string) string {
+	if res, ok := n.names[syntheticKey(key)]; ok {
+		return res
+	}
+	return n.assign(syntheticKey(key), name)
+}
+
+// TypeName implements openapi3gen.TypeNameGenerator
+func (n *ComponentNamer) TypeName(t reflect.Type) string {
+	t = typing.DerefReflectPtr(t)
+	return n.resolve(t, t.PkgPath(), t.Name())
+}
+
+// resolve returns name of type identified by key, assigning it if type wasn't named yet
+func (n *ComponentNamer) resolve(key any, pkg string, name string) string {
+	if res, ok := n.names[key]; ok {
+		return res
+	}
+
+	if name == "" {
+		if hint, ok := n.hints[key]; ok {
+			return n.assign(key, hint)
+		}
+
+		n.anonCount++
+		logging.Debug("anonymous struct has no name hint, using counter", "type", key)
+		return n.assign(key, fmt.Sprintf("AnonStruct%d", n.anonCount))
+	}
+
+	// type wasn't reachable from registry, so pick shortest suffix, not used by other types yet
+	logging.Debug("type not found in registry, resolving name on demand", logging.Pkg(pkg), "name", name)
+	base := componentBaseName(name)
+	segments := pkgSegments(pkg)
+	for i := 1; i <= len(segments); i++ {
+		res := qualifyName(strings.Join(segments[len(segments)-i:], "."), base)
+		if _, ok := n.taken[n.rename(res)]; !ok {
+			return n.assign(key, res)
+		}
+	}
+
+	return n.assign(key, qualifyName(strings.Join(segments, "."), base))
+}
+
+// assign applies rename rules to name and saves it for t.
+// If name is already taken by other type, numeric suffix is appended.
+func (n *ComponentNamer) assign(key any, name string) string {
+	name = n.rename(name)
+	unique := name
+	for i := 2; ; i++ {
+		prev, ok := n.taken[unique]
+		if !ok || prev == key {
+			break
+		}
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+
+	n.names[key] = unique
+	n.taken[unique] = key
+	return unique
+}
+
+func (n *ComponentNamer) rename(name string) string {
+	for _, rule := range n.rules {
+		name = rule.Pattern.ReplaceAllString(name, rule.Replacement)
+	}
+	return SanitizeComponentName(name)
+}
+
+func collectComponentTypes(
+	t reflect.Type,
+	seen map[reflect.Type]struct{},
+	named *[]namedComponent,
+	anons *[]anonStruct,
+) {
+	t = unwrapReflectType(t)
+	if t.Kind() != reflect.Struct || t == typing.TimeType {
+		return
+	}
+
+	if _, ok := seen[t]; ok {
+		return
+	}
+	seen[t] = struct{}{}
+
+	if t.Name() != "" {
+		*named = append(*named, namedComponent{
+			key:  t,
+			pkg:  t.PkgPath(),
+			name: t.Name(),
+		})
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		ft := unwrapReflectType(f.Type)
+		if ft.Kind() == reflect.Struct && ft.Name() == "" {
+			*anons = append(*anons, anonStruct{
+				key:    ft,
+				parent: t,
+				field:  f.Name,
+			})
+		}
+
+		collectComponentTypes(f.Type, seen, named, anons)
+	}
+}
+
+// unwrapReflectType returns first type, which is not pointer, slice, array or map.
+// For maps value type is returned.
+func unwrapReflectType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// uniquePkgSuffixes returns shortest dot-joined suffix of each package path, which isn't shared with other paths
+func uniquePkgSuffixes(pkgs []string) []string {
+	res := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		segments := pkgSegments(pkg)
+		for k := 1; k <= len(segments); k++ {
+			suffix := strings.Join(segments[len(segments)-k:], ".")
+			res[i] = suffix
+			if !sharesPkgSuffix(pkgs, i, k) {
+				break
+			}
+		}
+	}
+	return res
+}
+
+func sharesPkgSuffix(pkgs []string, idx int, k int) bool {
+	own := pkgSegments(pkgs[idx])
+	suffix := own[len(own)-k:]
+	for j, other := range pkgs {
+		if j == idx || other == pkgs[idx] {
+			continue
+		}
+
+		segments := pkgSegments(other)
+		if len(segments) >= k && slices.Equal(segments[len(segments)-k:], suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func pkgSegments(pkg string) []string {
+	if pkg == "" {
+		return nil
+	}
+	return strings.Split(pkg, "/")
+}
+
+func qualifyName(qualifier string, name string) string {
+	if qualifier == "" {
+		return name
+	}
+	return qualifier + "." + name
+}
+
+// componentBaseName spells out generic instantiation arguments: Page[github.com/acme/dto.User] -> PageOfUser
+func componentBaseName(name string) string {
+	open := strings.IndexByte(name, '[')
+	if open == -1 || !strings.HasSuffix(name, "]") {
+		return name
+	}
+
+	args := splitTypeArgs(name[open+1 : len(name)-1])
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, typeArgName(arg))
+	}
+
+	return name[:open] + "Of" + strings.Join(parts, "And")
+}
+
+func typeArgName(s string) string {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "*"):
+		return typeArgName(s[1:])
+
+	case strings.HasPrefix(s, "[]"):
+		return typeArgName(s[2:]) + "List"
+
+	case strings.HasPrefix(s, "["):
+		end := strings.IndexByte(s, ']')
+		return typeArgName(s[end+1:]) + "List"
+
+	case strings.HasPrefix(s, "map["):
+		end := closingBracket(s, len("map"))
+		return typeArgName(s[len("map["):end]) + "To" + typeArgName(s[end+1:]) + "Map"
+
+	case s == "interface {}" || s == "any":
+		return "Any"
+	}
+
+	head := s
+	if i := strings.IndexByte(s, '['); i != -1 {
+		head = s[:i]
+	}
+
+	if i := strings.LastIndexByte(head, '/'); i != -1 {
+		s = s[i+1:]
+		head = head[i+1:]
+	}
+
+	// last package path segment can contain dots: gopkg.in/yaml.v3.Node
+	if i := strings.LastIndexByte(head, '.'); i != -1 {
+		s = s[i+1:]
+	}
+
+	return exportName(componentBaseName(s))
+}
+
+// splitTypeArgs splits comma separated type arguments list, ignoring commas in nested brackets
+func splitTypeArgs(s string) []string {
+	var (
+		res   []string
+		depth int
+		start int
+	)
+
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(res, s[start:])
+}
+
+// closingBracket returns index of bracket, closing the one at open
+func closingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+func exportName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,161 @@
+package typed
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	adto "github.com/d1vbyz3r0/typed/testdata/naming/a/dto"
+	bdto "github.com/d1vbyz3r0/typed/testdata/naming/b/dto"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	namingPkgA = "github.com/d1vbyz3r0/typed/testdata/naming/a/dto"
+	namingPkgB = "github.com/d1vbyz3r0/typed/testdata/naming/b/dto"
+)
+
+func TestComponentNamer(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(adto.User), Type: typing.Named(namingPkgA, "User")},
+		T{Val: new(bdto.User), Type: typing.Named(namingPkgB, "User")},
+		T{Val: new(bdto.Group), Type: typing.Named(namingPkgB, "Group")},
+		T{
+			Val:  new(adto.Page[bdto.User]),
+			Type: typing.Named(namingPkgA, "Page", typing.Named(namingPkgB, "User")),
+		},
+		T{
+			Val:  new(adto.Pair[string, []*bdto.Group]),
+			Type: typing.Named(namingPkgA, "Pair", typing.Basic("string"), typing.Slice(typing.Pointer(typing.Named(namingPkgB, "Group")))),
+		},
+	)
+
+	userA := reflect.TypeFor[adto.User]()
+	tests := []struct {
+		name  string
+		t     reflect.Type
+		rules []RenameRule
+		want  string
+	}{
+		{
+			name: "conflicting names use shortest unique package suffix",
+			t:    userA,
+			want: "a.dto.User",
+		},
+		{
+			name: "conflicting names are resolved for pointers too",
+			t:    reflect.TypeFor[*bdto.User](),
+			want: "b.dto.User",
+		},
+		{
+			name: "unique name is prefixed with package name",
+			t:    reflect.TypeFor[bdto.Group](),
+			want: "dto.Group",
+		},
+		{
+			name: "generic instantiation",
+			t:    reflect.TypeFor[adto.Page[bdto.User]](),
+			want: "dto.PageOfUser",
+		},
+		{
+			name: "generic instantiation with multiple args",
+			t:    reflect.TypeFor[adto.Pair[string, []*bdto.Group]](),
+			want: "dto.PairOfStringAndGroupList",
+		},
+		{
+			name: "anonymous struct is named after parent field",
+			t:    userA.Field(1).Type,
+			want: "a.dto.UserAddress",
+		},
+		{
+			name: "nested anonymous struct is named after anonymous parent field",
+			t:    userA.Field(1).Type.Field(1).Type,
+			want: "a.dto.UserAddressStreet",
+		},
+		{
+			name: "rename rules are applied",
+			t:    reflect.TypeFor[bdto.Group](),
+			rules: []RenameRule{
+				MustNewRenameRule(`^dto\.(.*)$`, "Api${1}"),
+			},
+			want: "ApiGroup",
+		},
+		{
+			name: "renamed names are sanitized",
+			t:    reflect.TypeFor[bdto.Group](),
+			rules: []RenameRule{
+				MustNewRenameRule(`^dto\.`, "my dto/"),
+			},
+			want: "my_dto_Group",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n := NewComponentNamer(registry, tc.rules...)
+			require.Equal(t, tc.want, n.TypeName(tc.t))
+		})
+	}
+}
+
+func TestComponentNamer_Hint(t *testing.T) {
+	n := NewComponentNamer(MustNewRegistry(T{Val: new(string), Type: typing.Basic("string")}))
+	form := reflect.StructOf([]reflect.StructField{
+		{Name: "Name", Type: reflect.TypeFor[string](), Tag: `form:"name"`},
+	})
+
+	n.Hint(form, "UploadForm")
+	require.Equal(t, "UploadForm", n.TypeName(form))
+
+	n.Hint(form, "OtherForm")
+	require.Equal(t, "UploadForm", n.TypeName(form), "hint should not rename already named type")
+
+	anon := reflect.StructOf([]reflect.StructField{
+		{Name: "ID", Type: reflect.TypeFor[int]()},
+	})
+	require.Equal(t, "AnonStruct1", n.TypeName(anon))
+}
+
+func TestComponentNamer_NewSchemaRefForValue(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(adto.User), Type: typing.Named(namingPkgA, "User")},
+		T{Val: new(bdto.User), Type: typing.Named(namingPkgB, "User")},
+		T{
+			Val:  new(adto.Page[bdto.User]),
+			Type: typing.Named(namingPkgA, "Page", typing.Named(namingPkgB, "User")),
+		},
+	)
+
+	schemas := make(openapi3.Schemas)
+	require.NoError(t, GenerateRefs(NewGenerator(registry), schemas, registry))
+	require.Contains(t, schemas, "a.dto.User")
+	require.Contains(t, schemas, "b.dto.User")
+	require.Contains(t, schemas, "dto.PageOfUser")
+	require.Contains(t, schemas, "a.dto.UserAddress")
+
+	for name := range schemas {
+		require.Regexp(t, `^[a-zA-Z0-9\.\-_]+$`, name)
+	}
+}
+
+func TestComponentBaseName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "User", want: "User"},
+		{name: "Page[github.com/acme/dto.User]", want: "PageOfUser"},
+		{name: "Page[map[string]github.com/acme/dto.User]", want: "PageOfStringToUserMap"},
+		{name: "Page[github.com/acme/dto.Pair[int,interface {}]]", want: "PageOfPairOfIntAndAny"},
+		{name: "Page[[2]*github.com/acme/dto.User]", want: "PageOfUserList"},
+		{name: "Page[gopkg.in/yaml.v3.Node]", want: "PageOfNode"},
+		{name: "Page[gopkg.in/yaml.v3.Pair[gopkg.in/yaml.v3.Node]]", want: "PageOfPairOfNode"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, componentBaseName(tc.name))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package typed
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/d1vbyz3r0/typed/common/typing"
 	"github.com/d1vbyz3r0/typed/logging"
@@ -92,7 +93,7 @@ func NewEnumsCustomizer(registry *Registry) openapi3gen.SchemaCustomizerFn {
 			return nil
 		}
 
-		logging.Debug("enum values found in registry", "pkg", pkgPath, "typename", typeName)
+		logging.Debug("enum values found in registry", logging.Pkg(pkgPath), "typename", typeName)
 
 		schema.Enum = make([]any, len(vals))
 		copy(schema.Enum, vals)
@@ -100,6 +101,45 @@ func NewEnumsCustomizer(registry *Registry) openapi3gen.SchemaCustomizerFn {
 	}
 }
 
+// NewPolymorphismCustomizer describes registered interfaces as oneOf of their implementations.
+// Discriminator is added, if interface was registered with discriminator property name.
+func NewPolymorphismCustomizer(registry *Registry, namer *ComponentNamer) openapi3gen.SchemaCustomizerFn {
+	return func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		if t.Kind() != reflect.Interface || t.PkgPath() == "" {
+			return nil
+		}
+
+		impls, discriminator, ok := registry.LookupImplementations(t.PkgPath(), t.Name())
+		if !ok {
+			return nil
+		}
+
+		mapping := make(openapi3.StringMap[openapi3.MappingRef], len(impls))
+		for _, impl := range impls {
+			val, ok := registry.LookupValue(impl.Type)
+			if !ok {
+				logging.Debug("interface implementation not found in registry, skipping", "interface", t, "type", impl.Type)
+				continue
+			}
+
+			ref := "#/components/schemas/" + namer.TypeName(reflect.TypeOf(val))
+			schema.OneOf = append(schema.OneOf, openapi3.NewSchemaRef(ref, nil))
+			if impl.DiscriminatorValue != "" {
+				mapping[impl.DiscriminatorValue] = openapi3.MappingRef{Ref: ref}
+			}
+		}
+
+		if discriminator != "" && len(schema.OneOf) > 0 {
+			schema.Discriminator = &openapi3.Discriminator{PropertyName: discriminator}
+			if len(mapping) > 0 {
+				schema.Discriminator.Mapping = mapping
+			}
+		}
+
+		return nil
+	}
+}
+
 func excludeNonBodyFieldsFromGeneration(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
 	if tag == "" {
 		return nil
@@ -107,7 +147,7 @@ func excludeNonBodyFieldsFromGeneration(name string, t reflect.Type, tag reflect
 
 	for _, tagName := range nonBodyTags {
 		if v, ok := tag.Lookup(tagName); ok && v != "-" {
-			logging.Debug("removed field from final schema since it's not in body", "pkg", t.PkgPath(), "name", t.Name(), "tag", tag)
+			logging.Debug("removed field from final schema since it's not in body", logging.Pkg(t.PkgPath()), "name", t.Name(), "tag", tag)
 			return new(openapi3gen.ExcludeSchemaSentinel)
 		}
 	}
@@ -120,8 +160,23 @@ func makeFieldsRequired(name string, t reflect.Type, tag reflect.StructTag, sche
 		return nil
 	}
 
+	schema.Required = append(schema.Required, requiredFieldNames(t)...)
+	return nil
+}
+
+// requiredFieldNames returns names of non-nullable body fields.
+// Fields of embedded structs are promoted, unless struct is embedded by pointer.
+func requiredFieldNames(t reflect.Type) []string {
+	var res []string
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
+		if isInlinedEmbeddedField(f) {
+			if f.Type.Kind() == reflect.Struct {
+				res = append(res, requiredFieldNames(f.Type)...)
+			}
+			continue
+		}
+
 		if !f.IsExported() {
 			continue
 		}
@@ -136,23 +191,17 @@ func makeFieldsRequired(name string, t reflect.Type, tag reflect.StructTag, sche
 			fieldType.Kind() == reflect.Map {
 			continue
 		}
-		schema.Required = append(schema.Required, getFieldNameByTag(t.Field(i)))
+		res = append(res, getFieldNameByTag(f))
 	}
-
-	return nil
+	return res
 }
 
 func getFieldNameByTag(field reflect.StructField) string {
-	if v := field.Tag.Get("json"); v != "" && v != "-" {
-		return v
-	}
-
-	if v := field.Tag.Get("form"); v != "" && v != "-" {
-		return v
-	}
-
-	if v := field.Tag.Get("xml"); v != "" && v != "-" {
-		return v
+	for _, tag := range []string{"json", "form", "xml"} {
+		// tag options, such as omitempty, are not a part of name
+		if v, _, _ := strings.Cut(field.Tag.Get(tag), ","); v != "" && v != "-" {
+			return v
+		}
 	}
 
 	return field.Name
@@ -0,0 +1,92 @@
+package typed
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/testdata/embedded"
+	"github.com/d1vbyz3r0/typed/testdata/polymorph"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const polymorphPkg = "github.com/d1vbyz3r0/typed/testdata/polymorph"
+
+func TestPolymorphismCustomizer(t *testing.T) {
+	event := typing.Interface(
+		typing.Named(polymorphPkg, "Event"),
+		"type",
+		typing.Implementation{Type: typing.Named(polymorphPkg, "Created"), DiscriminatorValue: "created"},
+		typing.Implementation{Type: typing.Named(polymorphPkg, "Deleted"), DiscriminatorValue: "deleted"},
+		typing.Implementation{Type: typing.Named(polymorphPkg, "Unknown")},
+	)
+
+	registry := MustNewRegistry(
+		T{Val: new(polymorph.Event), Type: event},
+		T{Val: new(polymorph.Created), Type: typing.Named(polymorphPkg, "Created")},
+		T{Val: new(polymorph.Deleted), Type: typing.Named(polymorphPkg, "Deleted")},
+		T{Val: new(polymorph.Unknown), Type: typing.Named(polymorphPkg, "Unknown")},
+		T{Val: new(polymorph.Envelope), Type: typing.Named(polymorphPkg, "Envelope")},
+	)
+
+	schemas := make(openapi3.Schemas)
+	require.NoError(t, GenerateRefs(NewGenerator(registry), schemas, registry))
+
+	envelope, ok := schemas["polymorph.Envelope"]
+	require.True(t, ok)
+
+	eventSchema := envelope.Value.Properties["event"].Value
+	require.Len(t, eventSchema.OneOf, 3)
+	require.Equal(t, "#/components/schemas/polymorph.Created", eventSchema.OneOf[0].Ref)
+	require.Equal(t, "#/components/schemas/polymorph.Deleted", eventSchema.OneOf[1].Ref)
+	require.Equal(t, "#/components/schemas/polymorph.Unknown", eventSchema.OneOf[2].Ref)
+
+	require.NotNil(t, eventSchema.Discriminator)
+	require.Equal(t, "type", eventSchema.Discriminator.PropertyName)
+	require.Equal(t, openapi3.StringMap[openapi3.MappingRef]{
+		"created": {Ref: "#/components/schemas/polymorph.Created"},
+		"deleted": {Ref: "#/components/schemas/polymorph.Deleted"},
+	}, eventSchema.Discriminator.Mapping)
+
+	for _, name := range []string{"polymorph.Created", "polymorph.Deleted", "polymorph.Unknown"} {
+		require.Contains(t, schemas, name)
+	}
+}
+
+func TestRequiredFieldNames(t *testing.T) {
+	type options struct {
+		ID    int    `json:"id,omitempty"`
+		Name  string `json:"name"`
+		Email string `form:"email"`
+		Note  *string
+	}
+
+	tests := []struct {
+		name string
+		t    reflect.Type
+		want []string
+	}{
+		{
+			name: "tag options are not a part of names",
+			t:    reflect.TypeFor[options](),
+			want: []string{"id", "name", "email"},
+		},
+		{
+			name: "fields of embedded struct are promoted",
+			t:    reflect.TypeFor[embedded.User](),
+			want: []string{"id", "created_at", "Audit", "name"},
+		},
+		{
+			name: "fields of struct embedded by pointer are not promoted",
+			t:    reflect.TypeFor[embedded.Pointer](),
+			want: []string{"name"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, requiredFieldNames(tc.t))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package typed
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+// newEmbeddedAllOfCustomizer renders structs with embedded named structs as allOf composition:
+// allOf: [$ref: Base, {own properties}]. Embedded struct is composed only if it's inlined by encoding/json rules
+// (json tag has no name) and embedded by value, pointers are still flattened, since their fields may be omitted.
+// generator is called lazily to create components for embedded structs, so it can reference generator being created.
+func newEmbeddedAllOfCustomizer(generator func() *openapi3gen.Generator, namer *ComponentNamer) openapi3gen.SchemaCustomizerFn {
+	inProgress := make(map[reflect.Type]struct{})
+	return func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		if t.Kind() != reflect.Struct || t == typing.TimeType {
+			return nil
+		}
+
+		embedded := composedEmbeddedStructs(t)
+		if len(embedded) == 0 {
+			return nil
+		}
+
+		own := make(map[string]struct{})
+		for _, n := range ownPropertyNames(t) {
+			own[n] = struct{}{}
+		}
+		// openapi3gen doesn't inline embedded structs with tag options only (`json:",omitempty"`),
+		// so they are rendered as property, named after the field
+		for _, f := range embedded {
+			delete(own, f.Name)
+		}
+
+		layers := make([]*openapi3.SchemaRef, 0, len(embedded)+1)
+		for _, f := range embedded {
+			if _, ok := inProgress[f.Type]; !ok {
+				// embedded struct is flattened by openapi3gen, so its component must be generated explicitly
+				inProgress[f.Type] = struct{}{}
+				_, err := generator().GenerateSchemaRef(f.Type)
+				delete(inProgress, f.Type)
+				if err != nil {
+					return err
+				}
+			}
+
+			layers = append(layers, openapi3.NewSchemaRef("#/components/schemas/"+namer.TypeName(f.Type), nil))
+			for _, p := range structPropertyNames(f.Type) {
+				if _, ok := own[p]; !ok {
+					delete(schema.Properties, p)
+				}
+			}
+			delete(schema.Properties, f.Name)
+		}
+
+		ownLayer := &openapi3.Schema{
+			Type:       &openapi3.Types{openapi3.TypeObject},
+			Properties: schema.Properties,
+		}
+		for _, r := range schema.Required {
+			if _, ok := ownLayer.Properties[r]; ok && !slices.Contains(ownLayer.Required, r) {
+				ownLayer.Required = append(ownLayer.Required, r)
+			}
+		}
+
+		if len(ownLayer.Properties) > 0 {
+			layers = append(layers, openapi3.NewSchemaRef("", ownLayer))
+		}
+
+		*schema = openapi3.Schema{
+			Nullable: schema.Nullable,
+			AllOf:    layers,
+			// openapi3gen exports only schemas with properties as components, so empty map is kept.
+			// It's omitted on serialization.
+			Properties: make(openapi3.Schemas),
+		}
+		return nil
+	}
+}
+
+// composedEmbeddedStructs returns embedded named structs, which are inlined by encoding/json and embedded by value
+func composedEmbeddedStructs(t reflect.Type) []reflect.StructField {
+	var res []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous || !f.IsExported() {
+			continue
+		}
+
+		if f.Type.Kind() != reflect.Struct || f.Type.Name() == "" || f.Type == typing.TimeType {
+			continue
+		}
+
+		if jsonTagName(f.Tag) != "" {
+			continue
+		}
+
+		res = append(res, f)
+	}
+	return res
+}
+
+// ownPropertyNames returns property names of struct fields, which are not promoted from embedded structs
+func ownPropertyNames(t reflect.Type) []string {
+	var res []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isInlinedEmbeddedField(f) {
+			continue
+		}
+
+		if n, ok := fieldPropertyName(f); ok {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// structPropertyNames returns property names of struct fields, including fields promoted from embedded structs
+func structPropertyNames(t reflect.Type) []string {
+	t = typing.DerefReflectPtr(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	res := ownPropertyNames(t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isInlinedEmbeddedField(f) {
+			res = append(res, structPropertyNames(f.Type)...)
+		}
+	}
+	return res
+}
+
+// isInlinedEmbeddedField reports if openapi3gen flattens embedded field into parent struct
+func isInlinedEmbeddedField(f reflect.StructField) bool {
+	if !f.Anonymous {
+		return false
+	}
+
+	v, ok := f.Tag.Lookup("json")
+	return (!ok || v == "") && typing.DerefReflectPtr(f.Type).Kind() == reflect.Struct
+}
+
+// fieldPropertyName mirrors openapi3gen field naming for field, ok is false if field isn't included into schema
+func fieldPropertyName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() || f.Type.Kind() == reflect.Func || f.Type.Kind() == reflect.Chan {
+		return "", false
+	}
+
+	jsonTag, hasJSON := f.Tag.Lookup("json")
+	if jsonTag == "-" {
+		return "", false
+	}
+
+	name := f.Name
+	if n := jsonTagName(f.Tag); n != "" {
+		name = n
+	}
+
+	if !hasJSON || jsonTag == "" {
+		if v, ok := f.Tag.Lookup("yaml"); ok && v != "-" {
+			name = v
+		}
+	}
+
+	return FieldNameGenerator(f, name), true
+}
+
+func jsonTagName(tag reflect.StructTag) string {
+	name, _, _ := strings.Cut(tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,116 @@
+package typed
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/d1vbyz3r0/typed/testdata/embedded"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+const embeddedPkg = "github.com/d1vbyz3r0/typed/testdata/embedded"
+
+var embeddedModels = []*typing.Type{
+	typing.Named(embeddedPkg, "User"),
+	typing.Named(embeddedPkg, "Shadowed"),
+	typing.Named(embeddedPkg, "Tagged"),
+	typing.Named(embeddedPkg, "Pointer"),
+}
+
+func newEmbeddedRegistry() *Registry {
+	return MustNewRegistry(
+		T{Val: new(embedded.User), Type: typing.Named(embeddedPkg, "User")},
+		T{Val: new(embedded.Shadowed), Type: typing.Named(embeddedPkg, "Shadowed")},
+		T{Val: new(embedded.Tagged), Type: typing.Named(embeddedPkg, "Tagged")},
+		T{Val: new(embedded.Pointer), Type: typing.Named(embeddedPkg, "Pointer")},
+	)
+}
+
+func TestEmbeddedAllOf(t *testing.T) {
+	registry := newEmbeddedRegistry()
+	schemas := make(openapi3.Schemas)
+	require.NoError(t, GenerateRefs(NewGenerator(registry, WithEmbeddedAllOf()), schemas, registry))
+
+	base, ok := schemas["embedded.Base"]
+	require.True(t, ok, "embedded struct must be exported as component")
+	require.ElementsMatch(t, []string{"id", "created_at"}, base.Value.Required)
+	require.Contains(t, schemas, "embedded.Audit")
+
+	user := schemas["embedded.User"].Value
+	require.Empty(t, user.Properties)
+	require.Len(t, user.AllOf, 3)
+	require.Equal(t, "#/components/schemas/embedded.Base", user.AllOf[0].Ref)
+	require.Equal(t, "#/components/schemas/embedded.Audit", user.AllOf[1].Ref)
+	own := user.AllOf[2].Value
+	require.Len(t, own.Properties, 1)
+	require.Contains(t, own.Properties, "name")
+	require.Equal(t, []string{"name"}, own.Required)
+
+	shadowed := schemas["embedded.Shadowed"].Value
+	require.Len(t, shadowed.AllOf, 2)
+	require.Equal(t, "#/components/schemas/embedded.Base", shadowed.AllOf[0].Ref)
+	require.Contains(t, shadowed.AllOf[1].Value.Properties, "id", "own field shadows embedded one")
+	require.Equal(t, []string{"id"}, shadowed.AllOf[1].Value.Required)
+
+	tagged := schemas["embedded.Tagged"].Value
+	require.Empty(t, tagged.AllOf)
+	require.Contains(t, tagged.Properties, "base")
+
+	pointer := schemas["embedded.Pointer"].Value
+	require.Empty(t, pointer.AllOf)
+	require.Contains(t, pointer.Properties, "id")
+	require.Contains(t, pointer.Properties, "name")
+	require.Equal(t, []string{"name"}, pointer.Required)
+}
+
+func TestEmbeddedFlattenedByDefault(t *testing.T) {
+	registry := newEmbeddedRegistry()
+	schemas := make(openapi3.Schemas)
+	require.NoError(t, GenerateRefs(NewGenerator(registry), schemas, registry))
+
+	user := schemas["embedded.User"].Value
+	require.Empty(t, user.AllOf)
+	require.Contains(t, user.Properties, "id")
+	require.Contains(t, user.Properties, "created_at")
+	require.ElementsMatch(t, []string{"id", "created_at", "Audit", "name"}, user.Required, "promoted fields must be required, but not inlined embedded field itself")
+}
+
+func TestStaticEmbeddedMatchesReflection(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "embedded")
+
+	testCases := []struct {
+		name       string
+		opts       []GeneratorOpt
+		staticOpts []StaticModelSourceOpt
+	}{
+		{name: "flattened"},
+		{name: "all of", opts: []GeneratorOpt{WithEmbeddedAllOf()}, staticOpts: []StaticModelSourceOpt{WithStaticEmbeddedAllOf()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := newEmbeddedRegistry()
+			want := make(openapi3.Schemas)
+			require.NoError(t, GenerateRefs(NewGenerator(registry, tc.opts...), want, registry))
+
+			source, err := NewStaticModelSource([]*packages.Package{pkg}, embeddedModels, NewComponentNamer(nil), tc.staticOpts...)
+			require.NoError(t, err)
+			got := make(openapi3.Schemas)
+			require.NoError(t, source.GenerateRefs(got))
+
+			require.ElementsMatch(t, slices.Collect(maps.Keys(want)), slices.Collect(maps.Keys(got)))
+			for name, schema := range want {
+				wantJSON, err := schema.MarshalJSON()
+				require.NoError(t, err)
+				gotJSON, err := got[name].MarshalJSON()
+				require.NoError(t, err)
+				require.JSONEq(t, string(wantJSON), string(gotJSON), name)
+			}
+		})
+	}
+}
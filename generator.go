@@ -7,15 +7,57 @@ import (
 	"github.com/getkin/kin-openapi/openapi3gen"
 )
 
+type GeneratorOpt func(opts *generatorOpts)
+
+type generatorOpts struct {
+	namer         *ComponentNamer
+	embeddedAllOf bool
+}
+
+// WithComponentNamer sets namer used for component schema names.
+// By default, namer without rename rules is created from registry.
+func WithComponentNamer(namer *ComponentNamer) GeneratorOpt {
+	return func(opts *generatorOpts) {
+		opts.namer = namer
+	}
+}
+
+// WithEmbeddedAllOf enables allOf composition for structs with embedded named structs.
+// Embedded struct is exported as a separate component and referenced instead of being flattened into parent schema.
+func WithEmbeddedAllOf() GeneratorOpt {
+	return func(opts *generatorOpts) {
+		opts.embeddedAllOf = true
+	}
+}
+
 // NewGenerator creates the default OpenAPI schema generator.
-func NewGenerator(registry *Registry) *openapi3gen.Generator {
+func NewGenerator(registry *Registry, opts ...GeneratorOpt) *openapi3gen.Generator {
+	genOpts := new(generatorOpts)
+	for _, opt := range opts {
+		opt(genOpts)
+	}
+
+	if genOpts.namer == nil {
+		genOpts.namer = NewComponentNamer(registry)
+	}
+
 	enumsCustomizer := NewEnumsCustomizer(registry)
-	return openapi3gen.NewGenerator(
+	polymorphismCustomizer := NewPolymorphismCustomizer(registry, genOpts.namer)
+
+	var (
+		g                  *openapi3gen.Generator
+		embeddedCustomizer openapi3gen.SchemaCustomizerFn
+	)
+	if genOpts.embeddedAllOf {
+		embeddedCustomizer = newEmbeddedAllOfCustomizer(func() *openapi3gen.Generator { return g }, genOpts.namer)
+	}
+
+	g = openapi3gen.NewGenerator(
 		openapi3gen.UseAllExportedFields(),
 		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
 			ExportComponentSchemas: true,
 			ExportTopLevelSchema:   true,
-			ExportGenerics:         false,
+			ExportGenerics:         true,
 		}),
 		openapi3gen.SchemaCustomizer(func(
 			name string,
@@ -27,9 +69,20 @@ func NewGenerator(registry *Registry) *openapi3gen.Generator {
 			if err := enumsCustomizer(name, t, tag, schema); err != nil {
 				return err
 			}
-			return Customizer(name, t, tag, schema)
+			if err := polymorphismCustomizer(name, t, tag, schema); err != nil {
+				return err
+			}
+			if err := Customizer(name, t, tag, schema); err != nil {
+				return err
+			}
+			if embeddedCustomizer != nil {
+				// must be called after Customizer, since required fields are split between allOf layers
+				return embeddedCustomizer(name, t, tag, schema)
+			}
+			return nil
 		}),
-		openapi3gen.CreateTypeNameGenerator(NewTypeNameGenerator(registry)),
+		openapi3gen.CreateTypeNameGenerator(genOpts.namer.TypeName),
 		openapi3gen.CreateFieldNameGenerator(FieldNameGenerator),
 	)
+	return g
 }
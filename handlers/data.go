@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/routes"
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+// Data is parsed handlers metadata. Generator serializes it into generated program,
+// so handlers are matched at runtime without loading and parsing packages. See Finder.Load
+type Data struct {
+	Handlers []parser.Handler `json:"handlers"`
+	// Routes are keys of handlers of statically discovered routes, keyed by method and path
+	Routes map[string]string `json:"routes,omitempty"`
+}
+
+// NewData collects handlers of parsing results
+func NewData(results []parser.Result) Data {
+	var data Data
+	for _, res := range results {
+		data.Handlers = append(data.Handlers, res.Handlers...)
+	}
+	return data
+}
+
+// TrimPaths makes file names of diagnostics relative to root, so serialized data doesn't depend on location of
+// sources. Only base names are kept for files outside root
+func (d *Data) TrimPaths(root string) {
+	for i, h := range d.Handlers {
+		if len(h.Diagnostics) == 0 {
+			continue
+		}
+
+		// diagnostics are shared with parsing results
+		diags := slices.Clone(h.Diagnostics)
+		for j := range diags {
+			diags[j].Pos.Filename = trimPath(root, diags[j].Pos.Filename)
+		}
+		d.Handlers[i].Diagnostics = diags
+	}
+}
+
+func trimPath(root, path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// AddRoutes saves keys of handlers of statically discovered routes
+func (d *Data) AddRoutes(rs ...routes.Route) {
+	if d.Routes == nil {
+		d.Routes = make(map[string]string, len(rs))
+	}
+
+	for _, r := range rs {
+		d.Routes[routeKey(r.Method, r.Path)] = r.Key()
+	}
+}
+
+// Load fills finder with handlers metadata, serialized from Data. It replaces Find, so packages are not loaded,
+// neither now, nor when routes are matched
+func (f *Finder) Load(data []byte) error {
+	var d Data
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("unmarshal handlers data: %w", err)
+	}
+
+	for _, h := range d.Handlers {
+		f.handlers[h.Key()] = h
+	}
+
+	for k, v := range d.Routes {
+		f.routes[k] = v
+	}
+	f.loaded = true
+
+	logging.Debug("loaded handlers data", "handlers", len(d.Handlers), "routes", len(d.Routes))
+	return nil
+}
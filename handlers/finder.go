@@ -7,12 +7,14 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
-	"sync"
 
+	"github.com/d1vbyz3r0/typed/common/meta"
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parsecache"
 	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/routes"
 	"github.com/d1vbyz3r0/typed/logging"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -30,6 +32,11 @@ type EchoRoute struct {
 type Finder struct {
 	parser   *parser.Parser
 	handlers map[string]parser.Handler
+	// routes are keys of handlers of statically discovered routes, keyed by method and path
+	routes map[string]string
+	// loaded is set, when finder is filled by Load. Such finder never loads packages, so closures,
+	// inlined into other packages, are matched by static routes only
+	loaded bool
 }
 
 func NewFinder() (*Finder, error) {
@@ -41,6 +48,7 @@ func NewFinder() (*Finder, error) {
 	return &Finder{
 		parser:   p,
 		handlers: make(map[string]parser.Handler),
+		routes:   make(map[string]string),
 	}, nil
 }
 
@@ -50,14 +58,6 @@ func (f *Finder) Find(patterns []SearchPattern, opts ...FinderOpt) error {
 		opt(findOpts)
 	}
 
-	cfg := &packages.Config{
-		Mode: packages.NeedTypes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedFiles |
-			packages.NeedName,
-	}
-
 	sp, err := f.buildSearchPatterns(patterns)
 	if err != nil {
 		return fmt.Errorf("build search patterns: %w", err)
@@ -65,62 +65,57 @@ func (f *Finder) Find(patterns []SearchPattern, opts ...FinderOpt) error {
 
 	logging.Debug("loaded search patterns", "patterns", sp)
 
-	pkgs, err := packages.Load(cfg, sp...)
+	loader := parsecache.NewLoader(f.parser, openCache(findOpts.cacheDir), findOpts.concurrency)
+	loaded, err := loader.Load(sp, parsecache.ParseOpts(adapterFuncs(findOpts.adapters)...)...)
 	if err != nil {
 		return fmt.Errorf("load packages: %w", err)
 	}
 
-	if packages.PrintErrors(pkgs) > 0 {
-		return fmt.Errorf("failed to load packages")
-	}
-
-	fillCache(pkgs)
+	fillCache(loaded.Packages)
 
-	var (
-		mtx sync.Mutex
-		eg  errgroup.Group
-	)
-
-	eg.SetLimit(findOpts.concurrency)
-
-	for _, pkg := range pkgs {
-		eg.Go(func() error {
-			res, err := f.parser.Parse(
-				pkg,
-				parser.ParseInlineForms(),
-				parser.ParseInlinePathParams(),
-				parser.ParseInlineQueryParams(),
-				parser.ParseInlineHeaders(),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to parse pkg %s: %w", pkg.PkgPath, err)
-			}
+	results := loaded.Results
+	if findOpts.routesProviderPkg != "" {
+		// generic wrappers are usually instantiated in routes provider package
+		instances := f.findStaticRoutes(findOpts.routesProviderPkg, findOpts.adapters)
+		results = append(results, parser.Result{PkgPath: findOpts.routesProviderPkg, Instances: instances})
+	}
 
-			for _, h := range res.Handlers {
-				key := h.Pkg + "." + h.Name
-				mtx.Lock()
-				if _, ok := f.handlers[key]; !ok {
-					f.handlers[key] = h
-					logging.Debug("saved handler to map", "pkg", h.Pkg, "name", h.Name)
-				}
-				mtx.Unlock()
+	parser.InstantiateGenerics(results)
+	for _, res := range results {
+		for _, h := range res.Handlers {
+			key := h.Key()
+			if _, ok := f.handlers[key]; !ok {
+				f.handlers[key] = h
+				logging.Debug("saved handler to map", logging.Pkg(h.Pkg), "name", h.Name, "type_args", typing.FormatTypeArgs(h.TypeArgs))
 			}
-			return nil
-		})
+		}
 	}
 
-	return eg.Wait()
+	return nil
 }
 
 func (f *Finder) Match(routes []EchoRoute) []Handler {
 	res := make([]Handler, 0, len(routes))
 	for _, route := range routes {
-		handlerName := f.getHandlerName(route.Route)
-		handlerPkg := funcPackagePath(route.HandlerFunc)
-		key := handlerPkg + "." + handlerName
-		h, ok := f.handlers[key]
+		recv, handlerName := f.getHandlerName(route.Route)
+		handlerPkg := funcPackagePath(route.HandlerFunc, !f.loaded)
+		h, ok := f.lookup(handlerPkg, recv, handlerName)
+
+		if staticKey, found := f.routes[routeKey(route.Route.Method, route.Route.Path)]; found {
+			staticHandler, parsed := f.handlers[staticKey]
+			switch {
+			case !parsed:
+				logging.Debug("static route handler not found in handlers", logging.Handler(staticKey))
+			case !ok:
+				logging.Debug("runtime match failed, using static route handler", "path", route.Route.Path, logging.Handler(staticKey))
+				h, ok = staticHandler, true
+			case staticKey != h.Key():
+				logging.Warn("runtime and static route handlers differ, using runtime", "path", route.Route.Path, "runtime", h.Key(), "static", staticKey)
+			}
+		}
+
 		if !ok {
-			logging.Warn("matched handler not found, skipping", "pkg", handlerPkg, "handler", handlerName)
+			logging.Warn("matched handler not found, skipping", logging.Pkg(handlerPkg), logging.Handler(handlerName))
 			continue
 		}
 		res = append(res, NewHandler(route.Route, route.Middlewares, h))
@@ -129,24 +124,167 @@ func (f *Finder) Match(routes []EchoRoute) []Handler {
 	return res
 }
 
-func (f *Finder) getHandlerName(route echo.Route) string {
+// lookup returns handler by its package, receiver and name, derived from runtime name.
+// Receiver of closures, inlined into other functions, can't be derived, so handler is used only if its name is unique in package
+func (f *Finder) lookup(pkg, recv, name string) (parser.Handler, bool) {
+	key := meta.FuncKey(pkg, recv, name)
+	if h, ok := f.handlers[key]; ok {
+		return h, true
+	}
+
+	if h, ok := f.lookupInstance(key); ok || recv != "" {
+		return h, ok
+	}
+
+	var (
+		res   parser.Handler
+		found int
+	)
+
+	for _, h := range f.handlers {
+		if h.Pkg == pkg && h.Name == name && h.Recv != "" {
+			res = h
+			found++
+		}
+	}
+
+	switch found {
+	case 0:
+		return parser.Handler{}, false
+	case 1:
+		logging.Debug("matched method handler by name", logging.Handler(res.Key()))
+		return res, true
+	default:
+		logging.Warn("several methods have handler name, can't match it without static routes", logging.Pkg(pkg), logging.Handler(name), "methods", found)
+		return parser.Handler{}, false
+	}
+}
+
+// lookupInstance returns instance of generic handler by its key without type arguments.
+// Runtime names of generic handler instances don't contain type arguments, so instance is used only if it's single one.
+func (f *Finder) lookupInstance(key string) (parser.Handler, bool) {
+	var (
+		res   parser.Handler
+		found int
+	)
+
+	for k, h := range f.handlers {
+		if strings.HasPrefix(k, key+"[") {
+			res = h
+			found++
+		}
+	}
+
+	switch found {
+	case 0:
+		return parser.Handler{}, false
+	case 1:
+		logging.Debug("matched single instance of generic handler", logging.Handler(key), "instance", res.Key())
+		return res, true
+	default:
+		logging.Warn("generic handler has several instances, can't match it without static routes", logging.Handler(key), "instances", found)
+		return parser.Handler{}, false
+	}
+}
+
+// findStaticRoutes extracts routes from routes provider package source code and returns instances of generic handlers,
+// found in it. Static routes are optional, so errors are logged only.
+func (f *Finder) findStaticRoutes(pkgPath string, adapters []Adapter) []parser.Instance {
+	cfg := &packages.Config{
+		Mode: packages.NeedTypes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo |
+			packages.NeedFiles |
+			packages.NeedName,
+	}
+
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		logging.Warn("failed to load routes provider package, static routes matching disabled", logging.Pkg(pkgPath), "error", err)
+		return nil
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		logging.Warn("routes provider package has errors, static routes matching disabled", logging.Pkg(pkgPath))
+		return nil
+	}
+
+	var instances []parser.Instance
+	for _, pkg := range pkgs {
+		for _, r := range routes.Extract(pkg, routes.WithAdapters(adapters...)) {
+			f.routes[routeKey(r.Method, r.Path)] = r.Key()
+		}
+		instances = append(instances, parser.FindInstances(pkg)...)
+	}
+
+	logging.Debug("found static routes", logging.Pkg(pkgPath), "count", len(f.routes))
+	return instances
+}
+
+// openCache opens parsing results cache in dir. Cache is disabled, if dir is empty or can't be created
+func openCache(dir string) *parsecache.Cache {
+	if dir == "" {
+		return nil
+	}
+
+	c, err := parsecache.New(dir)
+	if err != nil {
+		logging.Warn("failed to open cache, parsing results won't be cached", "dir", dir, "error", err)
+		return nil
+	}
+	return c
+}
+
+func adapterFuncs(adapters []Adapter) []string {
+	res := make([]string, 0, len(adapters))
+	for _, adapter := range adapters {
+		res = append(res, adapter.Func)
+	}
+	return res
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// getHandlerName returns receiver type and name of handler by route name. Receiver is returned for method values
+// and closures of methods, it's empty, when it can't be derived from name
+func (f *Finder) getHandlerName(route echo.Route) (recv string, name string) {
 	// Example route name: "xxx/internal/api.(*Server).mapUsers.LoginUserHandler.func1"
-	parts := strings.Split(route.Name, ".")
+	// Generic instances have type arguments replaced with "[...]": "xxx/internal/api.Handle[...].func1"
+	// Package path is trimmed first, as it can contain dots: "github.com/acme/api.(*Server).List-fm"
+	fullName := strings.ReplaceAll(route.Name, "[...]", "")
+	if pkg := packagePathFromFuncName(fullName); pkg != "" {
+		fullName = strings.TrimPrefix(fullName, pkg+".")
+	}
+	parts := strings.Split(fullName, ".")
 
 	// Get the package and handler name
 	// For wrapper handlers (with .funcN suffix), we need to take the part before .funcN
-	handlerName := parts[len(parts)-1]
-	if strings.HasPrefix(handlerName, "func") {
-		handlerName = parts[len(parts)-2]
+	idx := len(parts) - 1
+	if strings.HasPrefix(parts[idx], "func") && idx > 0 {
+		idx--
+	}
+	name = parts[idx]
+
+	// struct methods seems to have format <func-name>-fm, value receivers are not parenthesized: api.Server.List-fm
+	methodValue := strings.HasSuffix(name, "-fm")
+	if i := strings.Index(name, "-"); i != -1 {
+		name = name[:i]
 	}
 
-	// struct methods seems to have format <func-name>-fm
-	idx := strings.Index(handlerName, "-")
-	if idx != -1 {
-		handlerName = handlerName[:idx]
+	if idx == 0 {
+		return "", name
 	}
 
-	return handlerName
+	prev := parts[idx-1]
+	switch {
+	case strings.HasPrefix(prev, "(") && strings.HasSuffix(prev, ")"):
+		recv = strings.TrimSuffix(strings.TrimPrefix(prev, "("), ")")
+	case methodValue:
+		recv = prev
+	}
+	return recv, name
 }
 
 func (f *Finder) buildSearchPatterns(patterns []SearchPattern) ([]string, error) {
@@ -155,7 +293,7 @@ func (f *Finder) buildSearchPatterns(patterns []SearchPattern) ([]string, error)
 		return nil, fmt.Errorf("get current working directory: %v", err)
 	}
 
-	logging.Debug("detected current working directory", cwd)
+	logging.Debug("detected current working directory", "cwd", cwd)
 
 	res := make([]string, 0, len(patterns))
 	for _, pattern := range patterns {
@@ -172,7 +310,9 @@ func (f *Finder) buildSearchPatterns(patterns []SearchPattern) ([]string, error)
 	return res, nil
 }
 
-func funcPackagePath(fn any) string {
+// funcPackagePath returns package path of function. Package of closure is resolved by its source file,
+// which is looked up in loaded packages, and if loadPackages is set, by loading package of file directory
+func funcPackagePath(fn any, loadPackages bool) string {
 	v := reflect.ValueOf(fn)
 	if v.Kind() != reflect.Func {
 		panic(fmt.Sprintf("not a function: %s", v.Kind()))
@@ -188,8 +328,8 @@ func funcPackagePath(fn any) string {
 	if strings.Contains(name, ".func") ||
 		strings.Contains(name, ").") && strings.Contains(name, ".func") {
 		logging.Debug("using fallback to packagePathFromFileLine since handler is closure", "name", name)
-		if pkg := packagePathFromFileLine(fun, pc); pkg != "" {
-			logging.Debug("resolved original closure package path", "pkg", pkg)
+		if pkg := packagePathFromFileLine(fun, pc, loadPackages); pkg != "" {
+			logging.Debug("resolved original closure package path", logging.Pkg(pkg))
 			return pkg
 		}
 	}
@@ -197,16 +337,20 @@ func funcPackagePath(fn any) string {
 	return packagePathFromFuncName(name)
 }
 
-func packagePathFromFileLine(fun *runtime.Func, pc uintptr) string {
+func packagePathFromFileLine(fun *runtime.Func, pc uintptr, loadPackages bool) string {
 	file, _ := fun.FileLine(pc)
 	absFile, _ := filepath.Abs(file)
 
 	pkg, ok := lookupPkgByFile(absFile)
 	if ok {
-		logging.Debug("package cache hit", "file", absFile, "pkg", pkg.PkgPath)
+		logging.Debug("package cache hit", "file", absFile, logging.Pkg(pkg.PkgPath))
 		return pkg.PkgPath
 	}
 
+	if !loadPackages {
+		return ""
+	}
+
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedFiles,
 		Dir:  filepath.Dir(file),
@@ -225,7 +369,7 @@ func packagePathFromFileLine(fun *runtime.Func, pc uintptr) string {
 		for _, f := range pkg.GoFiles {
 			absGoFile, _ := filepath.Abs(f)
 			if sameFile(absFile, absGoFile) {
-				logging.Debug("package cache miss, adding", "file", absFile, "pkg", pkg.PkgPath)
+				logging.Debug("package cache miss, adding", "file", absFile, logging.Pkg(pkg.PkgPath))
 				putToCache(absGoFile, pkg)
 				return pkg.PkgPath
 			}
@@ -1,7 +1,16 @@
 package handlers
 
+import "github.com/d1vbyz3r0/typed/internal/parser/routes"
+
+// Adapter describes function, adapting handler with custom context to echo.HandlerFunc,
+// such as func Wrap(h func(c *AppContext) error) echo.HandlerFunc
+type Adapter = routes.Adapter
+
 type finderOpts struct {
-	concurrency int
+	concurrency       int
+	routesProviderPkg string
+	adapters          []Adapter
+	cacheDir          string
 }
 
 const defaultConcurrency = 5
@@ -22,3 +31,27 @@ func WithConcurrency(concurrency int) FinderOpt {
 		opts.concurrency = concurrency
 	}
 }
+
+// WithRoutesProviderPkg enables static route discovery in routes provider package.
+// Static routes are used to cross-check runtime matching and as fallback, when runtime matching fails.
+func WithRoutesProviderPkg(pkg string) FinderOpt {
+	return func(opts *finderOpts) {
+		opts.routesProviderPkg = pkg
+	}
+}
+
+// WithAdapters enables discovery of handlers with custom context, adapted to echo.HandlerFunc by adapters.
+// Adapted handlers are matched through static routes only, so routes provider package is required.
+func WithAdapters(adapters ...Adapter) FinderOpt {
+	return func(opts *finderOpts) {
+		opts.adapters = append(opts.adapters, adapters...)
+	}
+}
+
+// WithCacheDir enables cache of parsing results in dir. Packages are loaded with types and parsed only if their
+// content was changed since previous run. Cache is disabled, if dir is empty
+func WithCacheDir(dir string) FinderOpt {
+	return func(opts *finderOpts) {
+		opts.cacheDir = dir
+	}
+}
@@ -1,9 +1,20 @@
 package handlers
 
 import (
+	"encoding/json"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+
 	"testing"
 
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/d1vbyz3r0/typed/testdata/parser/receivers"
+	"github.com/d1vbyz3r0/typed/testdata/static"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,12 +65,30 @@ func Test_funcPackagePath(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := funcPackagePath(tc._func)
+			got := funcPackagePath(tc._func, true)
 			require.Equal(t, tc.want, got)
 		})
 	}
 }
 
+func Test_funcPackagePathClosureWithoutLoading(t *testing.T) {
+	mtx.Lock()
+	clear(cache)
+	mtx.Unlock()
+
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/parser/receivers"
+	file := filepath.Join(testsuite.FixturePath(t, "parser/receivers"), "handlers.go")
+	h := receivers.Health()
+
+	require.Equal(t, pkg, funcPackagePath(h, false))
+	_, ok := lookupPkgByFile(file)
+	require.False(t, ok, "package of closure is not loaded")
+
+	require.Equal(t, pkg, funcPackagePath(h, true))
+	_, ok = lookupPkgByFile(file)
+	require.True(t, ok)
+}
+
 func TestFinder_Find(t *testing.T) {
 	f, err := NewFinder()
 	require.NoError(t, err)
@@ -85,3 +114,302 @@ func TestFinder_Find(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "OtherHandler is other handler", wrapper.Doc)
 }
+
+func TestFinder_FindCached(t *testing.T) {
+	cacheDir := t.TempDir()
+	patterns := []SearchPattern{{Path: testsuite.FixturePath(t, "handlers")}}
+
+	find := func() map[string]parser.Handler {
+		f, err := NewFinder()
+		require.NoError(t, err)
+		require.NoError(t, f.Find(patterns, WithCacheDir(cacheDir)))
+		return f.handlers
+	}
+
+	parsed := find()
+	require.NotEmpty(t, parsed)
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	require.Equal(t, parsed, find())
+}
+
+func TestFinder_MatchStaticFallback(t *testing.T) {
+	f, err := NewFinder()
+	require.NoError(t, err)
+
+	err = f.Find(
+		[]SearchPattern{{Path: testsuite.FixturePath(t, "static")}},
+		WithRoutesProviderPkg("github.com/d1vbyz3r0/typed/testdata/static"),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		route   echo.Route
+		handler echo.HandlerFunc
+		want    []string
+	}{
+		{
+			name:    "runtime name can't be resolved",
+			route:   echo.Route{Method: http.MethodGet, Path: "/api/v1/users/:id", Name: "main.glob..func1"},
+			handler: func(c echo.Context) error { return nil },
+			want:    []string{"GetUser"},
+		},
+		{
+			name:    "handler factory",
+			route:   echo.Route{Method: http.MethodDelete, Path: "/api/v1/users/:id", Name: "github.com/d1vbyz3r0/typed/testdata/static.DeleteUser.func1"},
+			handler: func(c echo.Context) error { return nil },
+			want:    []string{"DeleteUser"},
+		},
+		{
+			name:    "runtime match is kept, when static handler differs",
+			route:   echo.Route{Method: http.MethodGet, Path: "/version", Name: "github.com/d1vbyz3r0/typed/testdata/static.Health"},
+			handler: static.Health,
+			want:    []string{"Health"},
+		},
+		{
+			name:    "unknown route",
+			route:   echo.Route{Method: http.MethodGet, Path: "/unknown", Name: "main.glob..func1"},
+			handler: func(c echo.Context) error { return nil },
+			want:    []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matched := f.Match([]EchoRoute{{Route: tc.route, HandlerFunc: tc.handler}})
+			got := make([]string, 0, len(matched))
+			for _, h := range matched {
+				got = append(got, h.HandlerName())
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFinder_MatchInlineHandler(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/routes"
+
+	f, err := NewFinder()
+	require.NoError(t, err)
+
+	err = f.Find([]SearchPattern{{Path: testsuite.FixturePath(t, "routes")}}, WithRoutesProviderPkg(pkg))
+	require.NoError(t, err)
+
+	matched := f.Match([]EchoRoute{{
+		Route:       echo.Route{Method: http.MethodGet, Path: "/healthz", Name: pkg + ".(*Server).MapRoutes.func1"},
+		HandlerFunc: func(c echo.Context) error { return nil },
+	}})
+	require.Len(t, matched, 1)
+	require.Equal(t, "MapRoutes.func1", matched[0].HandlerName())
+	require.Equal(t, "(*"+pkg+".Server).MapRoutes.func1", matched[0].handler.Key())
+}
+
+func TestFinder_MatchGenericWrappers(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/parser/wrappers"
+
+	f, err := NewFinder()
+	require.NoError(t, err)
+
+	err = f.Find(
+		[]SearchPattern{{Path: testsuite.FixturePath(t, "parser/wrappers")}},
+		WithRoutesProviderPkg(pkg),
+	)
+	require.NoError(t, err)
+	require.Len(t, f.handlers, 2)
+
+	routes := []EchoRoute{
+		{
+			Route:       echo.Route{Method: http.MethodPost, Path: "/users", Name: pkg + ".Handle[...].func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+		{
+			Route:       echo.Route{Method: http.MethodGet, Path: "/users", Name: pkg + ".Handle[...].func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+	}
+
+	matched := f.Match(routes)
+	got := make([]string, 0, len(matched))
+	for _, h := range matched {
+		got = append(got, h.HandlerName())
+	}
+	require.Equal(t, []string{"HandleCreateUserRequestUser", "HandleListUsersRequestUser"}, got)
+	require.Equal(t, pkg+".ListUsersRequest", matched[1].Request().ModelType.String())
+}
+
+func TestFinder_MatchAdaptedHandlers(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/parser/adapters"
+
+	f, err := NewFinder()
+	require.NoError(t, err)
+
+	err = f.Find(
+		[]SearchPattern{{Path: testsuite.FixturePath(t, "parser/adapters")}},
+		WithRoutesProviderPkg(pkg),
+		WithAdapters(Adapter{Func: pkg + ".Wrap"}, Adapter{Func: pkg + ".Named", HandlerArg: 1}),
+	)
+	require.NoError(t, err)
+	require.Len(t, f.handlers, 2)
+
+	routes := []EchoRoute{
+		{
+			Route:       echo.Route{Method: http.MethodGet, Path: "/me", Name: pkg + ".Wrap.func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+		{
+			Route:       echo.Route{Method: http.MethodPost, Path: "/users", Name: pkg + ".Named.func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+	}
+
+	matched := f.Match(routes)
+	got := make([]string, 0, len(matched))
+	for _, h := range matched {
+		got = append(got, h.HandlerName())
+	}
+	require.Equal(t, []string{"GetUser", "CreateUser"}, got)
+}
+
+func TestFinder_MatchMethodsWithSameName(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/parser/receivers"
+
+	e := echo.New()
+	users, orders := &receivers.UserHandler{}, receivers.OrderHandler{}
+	methodValues := []EchoRoute{
+		{Route: *e.GET("/users", users.List), HandlerFunc: users.List},
+		{Route: *e.GET("/orders", orders.List), HandlerFunc: orders.List},
+	}
+
+	tests := []struct {
+		name              string
+		routesProviderPkg string
+		routes            []EchoRoute
+	}{
+		{
+			name:   "method values",
+			routes: methodValues,
+		},
+		{
+			name:              "receivers can't be derived from runtime names",
+			routesProviderPkg: pkg,
+			routes: []EchoRoute{
+				{
+					Route:       echo.Route{Method: http.MethodGet, Path: "/users", Name: "main.glob..func1"},
+					HandlerFunc: func(c echo.Context) error { return nil },
+				},
+				{
+					Route:       echo.Route{Method: http.MethodGet, Path: "/orders", Name: pkg + ".Register.List.func1"},
+					HandlerFunc: func(c echo.Context) error { return nil },
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewFinder()
+			require.NoError(t, err)
+
+			var opts []FinderOpt
+			if tc.routesProviderPkg != "" {
+				opts = append(opts, WithRoutesProviderPkg(tc.routesProviderPkg))
+			}
+			require.NoError(t, f.Find([]SearchPattern{{Path: testsuite.FixturePath(t, "parser/receivers")}}, opts...))
+			require.Len(t, f.handlers, 3)
+
+			matched := f.Match(tc.routes)
+			require.Len(t, matched, 2)
+			require.Equal(t, "List returns users", matched[0].Description())
+			require.Equal(t, "List returns orders", matched[1].Description())
+		})
+	}
+}
+
+func TestFinder_Load(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/parser/wrappers"
+
+	found, err := NewFinder()
+	require.NoError(t, err)
+
+	err = found.Find(
+		[]SearchPattern{{Path: testsuite.FixturePath(t, "parser/wrappers")}},
+		WithRoutesProviderPkg(pkg),
+	)
+	require.NoError(t, err)
+
+	data := Data{Routes: found.routes}
+	for _, h := range found.handlers {
+		data.Handlers = append(data.Handlers, h)
+	}
+	b, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	loaded, err := NewFinder()
+	require.NoError(t, err)
+	require.NoError(t, loaded.Load(b))
+	require.Equal(t, found.routes, loaded.routes)
+
+	routes := []EchoRoute{
+		{
+			Route:       echo.Route{Method: http.MethodPost, Path: "/users", Name: pkg + ".Handle[...].func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+		{
+			Route:       echo.Route{Method: http.MethodGet, Path: "/users", Name: pkg + ".Handle[...].func1"},
+			HandlerFunc: func(c echo.Context) error { return nil },
+		},
+	}
+
+	matched := loaded.Match(routes)
+	require.Len(t, matched, 2)
+	require.Equal(t, found.Match(routes), matched)
+
+	require.Error(t, loaded.Load([]byte("{")))
+}
+
+func TestData_TrimPaths(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	diags := []diag.Diagnostic{
+		{Pos: token.Position{Filename: filepath.Join(root, "api", "users.go"), Line: 12}},
+		{Pos: token.Position{Filename: filepath.Join(t.TempDir(), "lib", "auth.go"), Line: 3}},
+	}
+	results := []parser.Result{{Handlers: []parser.Handler{{Name: "GetUser", Diagnostics: diags}}}}
+
+	data := NewData(results)
+	data.TrimPaths(root)
+
+	require.Equal(t, "api/users.go", data.Handlers[0].Diagnostics[0].Pos.Filename)
+	require.Equal(t, "auth.go", data.Handlers[0].Diagnostics[1].Pos.Filename, "only base name is kept outside root")
+	require.Equal(t, 12, data.Handlers[0].Diagnostics[0].Pos.Line)
+	require.True(t, filepath.IsAbs(results[0].Handlers[0].Diagnostics[0].Pos.Filename), "parsing results are not changed")
+}
+
+func TestFinder_getHandlerName(t *testing.T) {
+	f, err := NewFinder()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		wantRecv string
+		want     string
+	}{
+		{name: "github.com/acme/api.(*Server).mapUsers.LoginUserHandler.func1", want: "LoginUserHandler"},
+		{name: "github.com/acme/api.(*Handler).GetUser-fm", wantRecv: "*Handler", want: "GetUser"},
+		{name: "github.com/acme/api.Handler.GetUser-fm", wantRecv: "Handler", want: "GetUser"},
+		{name: "github.com/acme/api.(*Handler).Create.func1", wantRecv: "*Handler", want: "Create"},
+		{name: "github.com/acme/api.Handle[...].func1", want: "Handle"},
+		{name: "main.Health", want: "Health"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recv, name := f.getHandlerName(echo.Route{Name: tc.name})
+			require.Equal(t, tc.wantRecv, recv)
+			require.Equal(t, tc.want, name)
+		})
+	}
+}
@@ -8,10 +8,12 @@ import (
 
 	"github.com/d1vbyz3r0/typed/common/typing"
 	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/internal/parser/request"
 	"github.com/d1vbyz3r0/typed/internal/parser/request/path"
 	"github.com/d1vbyz3r0/typed/internal/parser/request/query"
 	"github.com/d1vbyz3r0/typed/internal/parser/response"
+	"github.com/d1vbyz3r0/typed/internal/parser/websocket"
 	"github.com/labstack/echo/v4"
 )
 
@@ -25,15 +27,20 @@ type Handler struct {
 	pathParams  []path.Param
 }
 
-func NewHandler(route echo.Route, middlewares []echo.MiddlewareFunc, handler parser.Handler) Handler {
-	segments := strings.Split(route.Path, "/")
+// OpenAPIPath converts echo route path to OpenAPI path: /users/:id -> /users/{id}
+func OpenAPIPath(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
 	for i, segment := range segments {
 		if strings.HasPrefix(segment, ":") {
 			trimmed := strings.TrimPrefix(segment, ":")
 			segments[i] = "{" + trimmed + "}"
 		}
 	}
-	p := strings.Join(segments, "/")
+	return strings.Join(segments, "/")
+}
+
+func NewHandler(route echo.Route, middlewares []echo.MiddlewareFunc, handler parser.Handler) Handler {
+	p := OpenAPIPath(route.Path)
 
 	params := make(map[string]path.Param, len(handler.Request.PathParams))
 	parts := strings.Split(route.Path, "/")
@@ -50,8 +57,9 @@ func NewHandler(route echo.Route, middlewares []echo.MiddlewareFunc, handler par
 
 	for _, param := range handler.Request.PathParams {
 		params[param.Name] = path.Param{
-			Name: param.Name,
-			Type: param.Type,
+			Name:     param.Name,
+			Type:     param.Type,
+			Inferred: param.Inferred,
 		}
 	}
 	pathParams := maps.Values(params)
@@ -81,8 +89,40 @@ func (h Handler) Method() string {
 	return h.route.Method
 }
 
+// HandlerName returns handler function name. Names of generic handler instances are suffixed with type argument names,
+// so Handle[CreateUserReq, UserResp] is named HandleCreateUserReqUserResp
 func (h Handler) HandlerName() string {
-	return h.handler.Name
+	name := h.handler.Name
+	for _, arg := range h.handler.TypeArgs {
+		for arg.ElemType() != nil {
+			arg = arg.ElemType()
+		}
+
+		if n := arg.Name(); n != "" {
+			name += strings.ToUpper(n[:1]) + n[1:]
+		}
+	}
+	return name
+}
+
+// Pkg returns import path of handler package
+func (h Handler) Pkg() string {
+	return h.handler.Pkg
+}
+
+// Ignored reports if handler is hidden from spec by //typed:ignore directive
+func (h Handler) Ignored() bool {
+	return h.handler.Ignored
+}
+
+// RouteName returns name of echo route, it's a handler function name, unless it's set explicitly
+func (h Handler) RouteName() string {
+	return h.route.Name
+}
+
+// Diagnostics returns constructs of handler, skipped by parser
+func (h Handler) Diagnostics() []diag.Diagnostic {
+	return h.handler.Diagnostics
 }
 
 func (h Handler) Description() string {
@@ -104,3 +144,8 @@ func (h Handler) BindModel() *typing.Type {
 func (h Handler) Middlewares() []echo.MiddlewareFunc {
 	return h.middlewares
 }
+
+// WebSocket returns messages of websocket handler, it's nil for regular handlers
+func (h Handler) WebSocket() *websocket.Messages {
+	return h.handler.WebSocket
+}
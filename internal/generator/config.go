@@ -6,11 +6,27 @@ import (
 	"go/token"
 	"os"
 	"regexp"
+	"slices"
 
+	"github.com/d1vbyz3r0/typed/logging"
 	"gopkg.in/yaml.v3"
 )
 
 func LoadConfig(path string) (Config, error) {
+	cfg, err := ReadConfig(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("validate config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// ReadConfig reads config without validation, so its keys can be overridden before Validate is called
+func ReadConfig(path string) (Config, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return Config{}, fmt.Errorf("open config: %w", err)
@@ -22,10 +38,6 @@ func LoadConfig(path string) (Config, error) {
 		return Config{}, fmt.Errorf("unmarshal config: %w", err)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return Config{}, fmt.Errorf("validate config: %w", err)
-	}
-
 	return cfg, nil
 }
 
@@ -33,8 +45,55 @@ type Config struct {
 	ProcessingHooks []string     `yaml:"processing-hooks"`
 	Input           InputConfig  `yaml:"input"`
 	Output          OutputConfig `yaml:"output"`
-	Debug           bool         `yaml:"debug"`
-	Concurrency     int          `yaml:"concurrency"`
+	// Debug sets debug log level, when log.level isn't set
+	Debug       bool        `yaml:"debug"`
+	Log         LogConfig   `yaml:"log"`
+	Concurrency int         `yaml:"concurrency"`
+	Cache       CacheConfig `yaml:"cache"`
+	// Werror fails generation, when parser reports any diagnostic, see diag.Code
+	Werror bool `yaml:"werror"`
+}
+
+// LogConfig configures logging of generator and generated program
+type LogConfig struct {
+	// Format is text or json. Defaults to text
+	Format string `yaml:"format"`
+	// Level is debug, info, warn or error. Defaults to warn
+	Level string `yaml:"level"`
+}
+
+func (c LogConfig) Validate() error {
+	if c.Format != "" {
+		if _, err := logging.ParseFormat(c.Format); err != nil {
+			return err
+		}
+	}
+
+	if c.Level != "" {
+		if _, err := logging.ParseLevel(c.Level); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LogLevel returns configured log level. Debug level is returned, when level isn't set and debug is enabled
+func (c Config) LogLevel() string {
+	if c.Log.Level == "" && c.Debug {
+		return "debug"
+	}
+	return c.Log.Level
+}
+
+// CacheConfig configures cache of parsing results, shared by generator and generated program
+type CacheConfig struct {
+	// Dir is a cache directory. User cache directory is used, if it's empty
+	Dir string `yaml:"dir"`
+	// Disabled disables cache for generator and generated program
+	Disabled bool `yaml:"disabled"`
+	// NoCache disables cache for generator run only, generated program still uses it. It's set by --no-cache flag
+	NoCache bool `yaml:"-"`
 }
 
 func (c Config) Validate() error {
@@ -46,6 +105,30 @@ func (c Config) Validate() error {
 		return errors.New("routes-provider-pkg is required")
 	}
 
+	for i, a := range c.Input.Adapters {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("validate adapter(n=%d,func=%s) config: %w", i, a.Func, err)
+		}
+	}
+
+	if len(c.Input.Adapters) > 0 && c.Input.RoutesProviderPkg == "" {
+		return errors.New("routes-provider-pkg is required to match adapted handlers")
+	}
+
+	if err := c.Input.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Input.Routes.Validate(); err != nil {
+		return fmt.Errorf("validate routes: %w", err)
+	}
+
+	for i, o := range c.Input.Overlays {
+		if o == "" {
+			return fmt.Errorf("overlay(n=%d) path is required", i)
+		}
+	}
+
 	for i, h := range c.Input.Handlers {
 		if err := h.Validate(); err != nil {
 			return fmt.Errorf("validate handler(n=%d,path=%s) config: %w", i, h.Path, err)
@@ -62,22 +145,227 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid output config: %w", err)
 	}
 
+	if err := c.Log.Validate(); err != nil {
+		return fmt.Errorf("validate log: %w", err)
+	}
+
 	return nil
 }
 
 type InputConfig struct {
-	ApiPrefix          *string          `yaml:"api-prefix,omitempty"`
-	Title              string           `yaml:"title"`
-	Version            string           `yaml:"version"`
-	Servers            []Server         `yaml:"servers"`
+	ApiPrefix *string `yaml:"api-prefix,omitempty"`
+	// Metadata is rendered into info, servers, tags, external docs and security of generated spec
+	Metadata           `yaml:",inline"`
 	RoutesProviderCtor string           `yaml:"routes-provider-ctor"`
 	RoutesProviderPkg  string           `yaml:"routes-provider-pkg"`
 	Handlers           []HandlersConfig `yaml:"handlers"`
 	Models             []ModelsConfig   `yaml:"models"`
+	// Adapters are functions, converting handlers with custom context to echo.HandlerFunc
+	Adapters []AdapterConfig `yaml:"adapters"`
+	// Discriminator is a property name used to tell apart implementations of interface types.
+	// When it's empty, discriminator is only added if implementations declare Type() string method returning constant.
+	Discriminator string `yaml:"discriminator"`
+	// Overlays are paths of OpenAPI Overlay or partial OpenAPI documents, merged into generated spec in order
+	Overlays []string `yaml:"overlays"`
+	// Routes select routes added to spec, ex: to skip health checks, pprof and metrics endpoints
+	Routes RoutesConfig `yaml:"routes"`
+}
+
+// Metadata describes API in info, servers, tags, external docs and security of spec
+type Metadata struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+	// Description is a description of API, CommonMark syntax may be used
+	Description    string   `yaml:"description"`
+	TermsOfService string   `yaml:"terms-of-service"`
+	Contact        *Contact `yaml:"contact"`
+	License        *License `yaml:"license"`
+	Servers        []Server `yaml:"servers"`
+	// Tags are top-level tags, describing tags of operations
+	Tags         []Tag         `yaml:"tags"`
+	ExternalDocs *ExternalDocs `yaml:"external-docs"`
+	// Security is a list of global security requirements, each maps security scheme names to required scopes
+	Security []map[string][]string `yaml:"security"`
+}
+
+// Merge returns metadata with fields overridden by set fields of override
+func (m Metadata) Merge(override Metadata) Metadata {
+	if override.Title != "" {
+		m.Title = override.Title
+	}
+	if override.Version != "" {
+		m.Version = override.Version
+	}
+	if override.Description != "" {
+		m.Description = override.Description
+	}
+	if override.TermsOfService != "" {
+		m.TermsOfService = override.TermsOfService
+	}
+	if override.Contact != nil {
+		m.Contact = override.Contact
+	}
+	if override.License != nil {
+		m.License = override.License
+	}
+	if len(override.Servers) > 0 {
+		m.Servers = override.Servers
+	}
+	if len(override.Tags) > 0 {
+		m.Tags = override.Tags
+	}
+	if override.ExternalDocs != nil {
+		m.ExternalDocs = override.ExternalDocs
+	}
+	if len(override.Security) > 0 {
+		m.Security = override.Security
+	}
+	return m
+}
+
+type Contact struct {
+	Name  string `yaml:"name"`
+	URL   string `yaml:"url"`
+	Email string `yaml:"email"`
+}
+
+type License struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+func (l License) Validate() error {
+	if l.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
 }
 
 type Server struct {
-	Url string `yaml:"url"`
+	Url         string `yaml:"url"`
+	Description string `yaml:"description"`
+	// Variables substitute {name} placeholders of Url
+	Variables map[string]ServerVariable `yaml:"variables"`
+}
+
+func (s Server) Validate() error {
+	if s.Url == "" {
+		return errors.New("url is required")
+	}
+
+	for name, v := range s.Variables {
+		if v.Default == "" {
+			return fmt.Errorf("variable %s: default is required", name)
+		}
+		if len(v.Enum) > 0 && !slices.Contains(v.Enum, v.Default) {
+			return fmt.Errorf("variable %s: default %q isn't in enum", name, v.Default)
+		}
+	}
+
+	return nil
+}
+
+type ServerVariable struct {
+	Default     string   `yaml:"default"`
+	Enum        []string `yaml:"enum"`
+	Description string   `yaml:"description"`
+}
+
+type Tag struct {
+	Name         string        `yaml:"name"`
+	Description  string        `yaml:"description"`
+	ExternalDocs *ExternalDocs `yaml:"external-docs"`
+}
+
+func (t Tag) Validate() error {
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if t.ExternalDocs != nil {
+		if err := t.ExternalDocs.Validate(); err != nil {
+			return fmt.Errorf("validate external-docs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type ExternalDocs struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+func (d ExternalDocs) Validate() error {
+	if d.URL == "" {
+		return errors.New("url is required")
+	}
+	return nil
+}
+
+func (m Metadata) Validate() error {
+	if m.License != nil {
+		if err := m.License.Validate(); err != nil {
+			return fmt.Errorf("validate license: %w", err)
+		}
+	}
+
+	for i, s := range m.Servers {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("validate server(n=%d,url=%s) config: %w", i, s.Url, err)
+		}
+	}
+
+	tags := make(map[string]bool, len(m.Tags))
+	for i, t := range m.Tags {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("validate tag(n=%d,name=%s) config: %w", i, t.Name, err)
+		}
+		if tags[t.Name] {
+			return fmt.Errorf("duplicate tag %s", t.Name)
+		}
+		tags[t.Name] = true
+	}
+
+	if m.ExternalDocs != nil {
+		if err := m.ExternalDocs.Validate(); err != nil {
+			return fmt.Errorf("validate external-docs: %w", err)
+		}
+	}
+
+	for i, req := range m.Security {
+		if len(req) == 0 {
+			// empty requirement makes security optional
+			continue
+		}
+		for scheme := range req {
+			if scheme == "" {
+				return fmt.Errorf("security(n=%d): scheme name is required", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AdapterConfig describes function adapting handler with custom context, ex: func Wrap(h func(c *AppContext) error) echo.HandlerFunc
+type AdapterConfig struct {
+	// Func is a full function name, ex: github.com/acme/app/web.Wrap
+	Func string `yaml:"func"`
+	// HandlerArg is an index of adapted handler in adapter arguments
+	HandlerArg int `yaml:"handler-arg"`
+}
+
+func (c AdapterConfig) Validate() error {
+	if c.Func == "" {
+		return errors.New("func is required")
+	}
+
+	if c.HandlerArg < 0 {
+		return fmt.Errorf("invalid handler-arg %d", c.HandlerArg)
+	}
+
+	return nil
 }
 
 type ModelsConfig struct {
@@ -145,9 +433,183 @@ func (c HandlersConfig) Validate() error {
 }
 
 type OutputConfig struct {
-	Path        string `yaml:"path"`
-	SpecPath    string `yaml:"spec-path"`
-	PackageName string `yaml:"package"`
+	Path           string               `yaml:"path"`
+	SpecPath       string               `yaml:"spec-path"`
+	PackageName    string               `yaml:"package"`
+	ComponentNames ComponentNamesConfig `yaml:"component-names"`
+	// EmbeddedAllOf renders structs with embedded named structs as allOf of embedded components and own properties,
+	// instead of flattening promoted fields
+	EmbeddedAllOf bool `yaml:"embedded-all-of"`
+	// AsyncAPIPath is a path of AsyncAPI document, describing websocket handlers. Document isn't generated if it's empty
+	AsyncAPIPath string `yaml:"asyncapi-path"`
+	// Specs are additional documents, built from operations of generated spec, matching route filters
+	Specs      []SpecConfig     `yaml:"specs"`
+	Components ComponentsConfig `yaml:"components"`
+	Report     ReportConfig     `yaml:"report"`
+}
+
+// ReportConfig configures generation report with routes matching results and documentation coverage
+type ReportConfig struct {
+	// Path is a path of JSON report. Report isn't saved, if it's empty
+	Path string `yaml:"path"`
+	// MinCoverage fails generation, if documentation coverage percentage is below it
+	MinCoverage float64 `yaml:"min-coverage"`
+}
+
+// Enabled reports if report has to be collected
+func (c ReportConfig) Enabled() bool {
+	return c.Path != "" || c.MinCoverage > 0
+}
+
+func (c ReportConfig) Validate() error {
+	if c.MinCoverage < 0 || c.MinCoverage > 100 {
+		return fmt.Errorf("min-coverage %v isn't in range [0, 100]", c.MinCoverage)
+	}
+	return nil
+}
+
+// ComponentsConfig configures pruning of component schemas. Only schemas reachable from operations
+// through references are kept by default
+type ComponentsConfig struct {
+	// AlwaysInclude are regexes of component names, kept with schemas they reference, ex: ^dto\.Event$
+	AlwaysInclude []string `yaml:"always-include"`
+	// KeepUnused keeps all generated component schemas, including unreferenced ones
+	KeepUnused bool `yaml:"keep-unused"`
+}
+
+func (c ComponentsConfig) Validate() error {
+	for i, expr := range c.AlwaysInclude {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("compile always-include[%d] %q: %w", i, expr, err)
+		}
+	}
+	return nil
+}
+
+// SpecConfig describes additional document, ex: public or admin API. Document has operations of generated spec,
+// selected by route filters, and component schemas, referenced by them
+type SpecConfig struct {
+	// Name identifies document in logs
+	Name     string `yaml:"name"`
+	SpecPath string `yaml:"spec-path"`
+	// Metadata overrides metadata of input config
+	Metadata `yaml:",inline"`
+	Routes   RoutesConfig `yaml:"routes"`
+	// Overlays are merged into document in order, input overlays are applied to generated spec only
+	Overlays []string `yaml:"overlays"`
+}
+
+func (c SpecConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if c.SpecPath == "" {
+		return errors.New("spec-path is required")
+	}
+
+	if err := c.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Routes.Validate(); err != nil {
+		return fmt.Errorf("validate routes: %w", err)
+	}
+
+	for i, o := range c.Overlays {
+		if o == "" {
+			return fmt.Errorf("overlay(n=%d) path is required", i)
+		}
+	}
+
+	return nil
+}
+
+// RoutesConfig selects operations: operation is selected, if it matches any include filter or include filters are
+// empty, and doesn't match exclude filters
+type RoutesConfig struct {
+	Include []RouteFilter `yaml:"include"`
+	Exclude []RouteFilter `yaml:"exclude"`
+}
+
+func (c RoutesConfig) Validate() error {
+	for i, f := range c.Include {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("validate include filter[%d]: %w", i, err)
+		}
+	}
+
+	for i, f := range c.Exclude {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("validate exclude filter[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// RouteFilter matches operations, all set fields must match. It's converted to typed.RouteFilter,
+// so fields must be kept in sync
+type RouteFilter struct {
+	// Path is a regex matching operation path, ex: ^/debug/pprof
+	Path string `yaml:"path"`
+	// PathPrefix matches operation path by segments, ex: /api/admin matches /api/admin/users, but not /api/administrators
+	PathPrefix string   `yaml:"path-prefix"`
+	Methods    []string `yaml:"methods"`
+	// Tags match operations with any of tags
+	Tags []string `yaml:"tags"`
+	// Middleware is a regex matching full function name of any route middleware, ex: echo-jwt
+	Middleware string `yaml:"middleware"`
+	// Handler is a regex matching handler name
+	Handler string `yaml:"handler"`
+	// HandlerPkg is a regex matching handler package import path
+	HandlerPkg string `yaml:"handler-pkg"`
+	// Name is a regex matching echo route name
+	Name string `yaml:"name"`
+}
+
+func (f RouteFilter) Validate() error {
+	if f.Path == "" && f.PathPrefix == "" && len(f.Methods) == 0 && len(f.Tags) == 0 && f.Middleware == "" &&
+		f.Handler == "" && f.HandlerPkg == "" && f.Name == "" {
+		return errors.New("empty route filter")
+	}
+
+	for _, re := range []struct{ field, expr string }{
+		{field: "path", expr: f.Path},
+		{field: "middleware", expr: f.Middleware},
+		{field: "handler", expr: f.Handler},
+		{field: "handler-pkg", expr: f.HandlerPkg},
+		{field: "name", expr: f.Name},
+	} {
+		if _, err := regexp.Compile(re.expr); err != nil {
+			return fmt.Errorf("compile %s %q: %w", re.field, re.expr, err)
+		}
+	}
+
+	return nil
+}
+
+type ComponentNamesConfig struct {
+	Rename []RenameRule `yaml:"rename"`
+}
+
+// RenameRule rewrites generated component names matching Match regex with Replace.
+// Replace may reference capture groups, ex: $1
+type RenameRule struct {
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+}
+
+func (r RenameRule) Validate() error {
+	if r.Match == "" {
+		return errors.New("match is required")
+	}
+
+	if _, err := regexp.Compile(r.Match); err != nil {
+		return fmt.Errorf("compile match %q: %w", r.Match, err)
+	}
+
+	return nil
 }
 
 func (c OutputConfig) Validate() error {
@@ -159,8 +621,33 @@ func (c OutputConfig) Validate() error {
 		return fmt.Errorf("invalid package name %q", c.Package())
 	}
 
-	if c.IsMain() && c.SpecPath == "" {
-		return errors.New("spec-path is required")
+	if c.IsMain() && c.SpecPath == "" && len(c.Specs) == 0 {
+		return errors.New("spec-path or specs are required")
+	}
+
+	names := make(map[string]bool, len(c.Specs))
+	for i, spec := range c.Specs {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("validate spec(n=%d,name=%s) config: %w", i, spec.Name, err)
+		}
+		if names[spec.Name] {
+			return fmt.Errorf("duplicate spec %s", spec.Name)
+		}
+		names[spec.Name] = true
+	}
+
+	if err := c.Components.Validate(); err != nil {
+		return fmt.Errorf("validate components: %w", err)
+	}
+
+	if err := c.Report.Validate(); err != nil {
+		return fmt.Errorf("validate report: %w", err)
+	}
+
+	for i, rule := range c.ComponentNames.Rename {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("validate component-names rename rule[%d]: %w", i, err)
+		}
 	}
 
 	return nil
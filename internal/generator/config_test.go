@@ -31,7 +31,7 @@ func TestConfigValidateOutputPackage(t *testing.T) {
 				},
 				Output: OutputConfig{Path: "gen/spec.go"},
 			},
-			wantErr: "invalid output config: spec-path is required",
+			wantErr: "invalid output config: spec-path or specs are required",
 		},
 		{
 			name: "non-main package does not require main inputs",
@@ -72,6 +72,218 @@ func TestConfigValidateOutputPackage(t *testing.T) {
 			},
 			wantErr: `invalid output config: invalid package name "_"`,
 		},
+		{
+			name: "adapter requires func",
+			cfg: Config{
+				Input: InputConfig{
+					Adapters: []AdapterConfig{{HandlerArg: 1}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "validate adapter(n=0,func=) config: func is required",
+		},
+		{
+			name: "adapters require routes provider package",
+			cfg: Config{
+				Input: InputConfig{
+					Adapters: []AdapterConfig{{Func: "example.com/project/web.Wrap"}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "routes-provider-pkg is required to match adapted handlers",
+		},
+		{
+			name: "empty overlay path",
+			cfg: Config{
+				Input: InputConfig{
+					Overlays: []string{"api/docs.yaml", ""},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "overlay(n=1) path is required",
+		},
+		{
+			name: "license requires name",
+			cfg: Config{
+				Input: InputConfig{
+					Metadata: Metadata{License: &License{URL: "https://opensource.org/licenses/MIT"}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "validate license: name is required",
+		},
+		{
+			name: "server variable default must be in enum",
+			cfg: Config{
+				Input: InputConfig{
+					Metadata: Metadata{Servers: []Server{{
+						Url:       "https://{env}.example.com",
+						Variables: map[string]ServerVariable{"env": {Default: "dev", Enum: []string{"prod", "stage"}}},
+					}}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: `validate server(n=0,url=https://{env}.example.com) config: variable env: default "dev" isn't in enum`,
+		},
+		{
+			name: "duplicate tag",
+			cfg: Config{
+				Input: InputConfig{
+					Metadata: Metadata{Tags: []Tag{{Name: "users"}, {Name: "users"}}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "duplicate tag users",
+		},
+		{
+			name: "tag external docs require url",
+			cfg: Config{
+				Input: InputConfig{
+					Metadata: Metadata{Tags: []Tag{{Name: "users", ExternalDocs: &ExternalDocs{Description: "Users guide"}}}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "validate tag(n=0,name=users) config: validate external-docs: url is required",
+		},
+		{
+			name: "invalid input route filter",
+			cfg: Config{
+				Input: InputConfig{
+					Routes: RoutesConfig{Exclude: []RouteFilter{{Path: "^/debug/pprof"}, {Name: "["}}},
+				},
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+				},
+			},
+			wantErr: "validate routes: validate exclude filter[1]: compile name \"[\": error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name: "invalid always included component regex",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Components:  ComponentsConfig{AlwaysInclude: []string{"^dto\\.Event$", "+"}},
+				},
+			},
+			wantErr: "invalid output config: validate components: compile always-include[1] \"+\": error parsing regexp: missing argument to repetition operator: `+`",
+		},
+		{
+			name: "min coverage out of range",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Report:      ReportConfig{MinCoverage: 120},
+				},
+			},
+			wantErr: "invalid output config: validate report: min-coverage 120 isn't in range [0, 100]",
+		},
+		{
+			name: "specs replace spec path",
+			cfg: Config{
+				Input: InputConfig{
+					RoutesProviderCtor: "NewServer",
+					RoutesProviderPkg:  "example.com/project/server",
+				},
+				Output: OutputConfig{
+					Path:  "gen/spec.go",
+					Specs: []SpecConfig{{Name: "public", SpecPath: "gen/public.yaml"}},
+				},
+			},
+		},
+		{
+			name: "duplicate spec",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Specs: []SpecConfig{
+						{Name: "public", SpecPath: "gen/public.yaml"},
+						{Name: "public", SpecPath: "gen/admin.yaml"},
+					},
+				},
+			},
+			wantErr: "invalid output config: duplicate spec public",
+		},
+		{
+			name: "spec requires path",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Specs:       []SpecConfig{{Name: "public"}},
+				},
+			},
+			wantErr: "invalid output config: validate spec(n=0,name=public) config: spec-path is required",
+		},
+		{
+			name: "empty route filter",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Specs: []SpecConfig{{
+						Name:     "public",
+						SpecPath: "gen/public.yaml",
+						Routes:   RoutesConfig{Exclude: []RouteFilter{{}}},
+					}},
+				},
+			},
+			wantErr: "invalid output config: validate spec(n=0,name=public) config: validate routes: validate exclude filter[0]: empty route filter",
+		},
+		{
+			name: "invalid route filter regex",
+			cfg: Config{
+				Output: OutputConfig{
+					Path:        "gen/spec.go",
+					PackageName: "spec",
+					Specs: []SpecConfig{{
+						Name:     "public",
+						SpecPath: "gen/public.yaml",
+						Routes:   RoutesConfig{Include: []RouteFilter{{HandlerPkg: "("}}},
+					}},
+				},
+			},
+			wantErr: "invalid output config: validate spec(n=0,name=public) config: validate routes: validate include filter[0]: compile handler-pkg \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "json log format",
+			cfg: Config{
+				Output: OutputConfig{Path: "gen/spec.go", PackageName: "spec"},
+				Log:    LogConfig{Format: "json", Level: "info"},
+			},
+		},
+		{
+			name: "unknown log level",
+			cfg: Config{
+				Output: OutputConfig{Path: "gen/spec.go", PackageName: "spec"},
+				Log:    LogConfig{Level: "verbose"},
+			},
+			wantErr: "validate log: unknown log level \"verbose\", expected debug, info, warn or error",
+		},
 	}
 
 	for _, tt := range tests {
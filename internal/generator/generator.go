@@ -2,18 +2,22 @@ package generator
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
-	"sync"
 	"text/template"
 
 	"github.com/d1vbyz3r0/typed/common/meta"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parsecache"
 	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/parser/routes"
 	"github.com/d1vbyz3r0/typed/logging"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/imports"
 
@@ -27,24 +31,52 @@ type TemplateArgs struct {
 	ApiPrefix              *string
 	Types                  []*typing.Type
 	Imports                []*importMapping
-	Title                  string
-	Version                string
-	Servers                []Server
 	HandlersPkgs           []HandlersConfig
 	RoutesProviderCtorName string
 	RoutesProviderPkgAlias string
+	RoutesProviderPkg      string
 	PackageName            string
 	IsMain                 bool
 	SpecPath               string
 	HandlerProcessingHooks []string
 	Concurrency            int
 	AliasNamer             typing.NamerFunc
-	Debug                  bool
+	LogFormat              string
+	LogLevel               string
+	RenameRules            []RenameRule
+	EmbeddedAllOf          bool
+	AsyncAPIPath           string
+	Adapters               []AdapterConfig
+	CacheDir               string
+	CacheDisabled          bool
+	// HandlersData is a Go string literal with serialized handlers.Data
+	HandlersData string
+	Overlays     []string
+	// Spec is a Go string literal with serialized spec, created from input config
+	Spec          string
+	SpecOutputs   []SpecOutputArgs
+	IncludeRoutes []RouteFilter
+	ExcludeRoutes []RouteFilter
+	Components    ComponentsConfig
+	Report        ReportConfig
+}
+
+// SpecOutputArgs describes typed.SpecOutput of generated program
+type SpecOutputArgs struct {
+	Name string
+	Path string
+	// Spec is a Go string literal with serialized spec, created from metadata of spec config
+	Spec     string
+	Include  []RouteFilter
+	Exclude  []RouteFilter
+	Overlays []string
 }
 
 type Generator struct {
 	cfg    Config
 	parser *parser.Parser
+	// cache holds parsing results of packages. It's nil if cache is disabled
+	cache *parsecache.Cache
 }
 
 func New(cfg Config) (*Generator, error) {
@@ -58,70 +90,280 @@ func New(cfg Config) (*Generator, error) {
 		parser: p,
 	}
 
+	if !cfg.Cache.Disabled && !cfg.Cache.NoCache {
+		g.cache, err = parsecache.New(cfg.Cache.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("open cache: %w", err)
+		}
+	}
+
 	return g, nil
 }
 
 func (g *Generator) Generate() error {
-	cfg := &packages.Config{
-		Mode: packages.NeedTypes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedName,
+	gen, err := g.generateResults()
+	if err != nil {
+		return err
 	}
 
+	if err := reportDiagnostics(os.Stderr, gen.Results, g.cfg.Werror); err != nil {
+		return err
+	}
+
+	_imports, _types, err := g.processParserResults(gen.Results)
+	if err != nil {
+		return fmt.Errorf("process parser results: %w", err)
+	}
+
+	return g.execTemplate(_imports, _types, g.handlersData(gen))
+}
+
+// generation is a result of generator stage. It's cached by content key of all packages
+type generation struct {
+	Results []parser.Result `json:"results"`
+	// Routes are keys of handlers of statically discovered routes, see handlers.Data
+	Routes map[string]string `json:"routes,omitempty"`
+}
+
+// generateResults parses configured packages, resolves interfaces and discovers static routes. Final results are cached
+// by content key of all packages, so nothing is loaded with types, when packages are unchanged
+func (g *Generator) generateResults() (generation, error) {
 	patterns, err := g.buildLoadPatterns()
 	if err != nil {
-		return fmt.Errorf("build load patterns: %w", err)
+		return generation{}, fmt.Errorf("build load patterns: %w", err)
 	}
 
 	logging.Debug("built packages load patterns", "patterns", patterns)
 
-	pkgs, err := packages.Load(cfg, patterns...)
+	loader := g.loader()
+	plan, err := loader.Plan(patterns, g.parseOpts()...)
 	if err != nil {
-		return fmt.Errorf("load packages: %w", err)
+		return generation{}, fmt.Errorf("compute packages key: %w", err)
 	}
 
-	if packages.PrintErrors(pkgs) > 0 {
-		return fmt.Errorf("failed to load one or more packages")
+	var key string
+	if g.cache != nil {
+		var routesKey string
+		if g.cfg.Input.RoutesProviderPkg != "" {
+			routesKey, err = parsecache.PackagesKey([]string{g.cfg.Input.RoutesProviderPkg}, "routes-provider")
+			if err != nil {
+				return generation{}, fmt.Errorf("compute routes provider package key: %w", err)
+			}
+		}
+
+		key = parsecache.Key("generate", plan.Key, routesKey, g.cfg.Input.Discriminator)
+		var gen generation
+		if g.cache.Get(key, &gen) {
+			logging.Debug("using cached generation results", "key", key)
+			return gen, nil
+		}
 	}
 
-	if g.cfg.Concurrency <= 0 {
-		g.cfg.Concurrency = len(pkgs)
+	// interfaces are resolved with types of all packages. Packages, loaded for the key, are not loaded again
+	loaded, err := loader.LoadTypedPlan(plan)
+	if err != nil {
+		return generation{}, err
 	}
 
-	var (
-		mtx     sync.Mutex
-		eg      errgroup.Group
-		results []parser.Result
-	)
+	results, providerPkgs, err := g.instantiateGenerics(loaded.Packages, loaded.Results)
+	if err != nil {
+		return generation{}, err
+	}
 
-	eg.SetLimit(g.cfg.Concurrency)
+	gen := generation{
+		Results: append(results, resolveInterfaces(loaded.Packages, results, g.cfg.Input.Discriminator)),
+	}
 
-	for _, pkg := range pkgs {
-		eg.Go(func() error {
-			// TODO: determine if should parse all models and enums based on current package path and filters
-			res, err := g.parser.Parse(pkg, parser.ParseAllModels(), parser.ParseEnums())
-			if err != nil {
-				return fmt.Errorf("parse pkg %s: %w", pkg.PkgPath, err)
-			}
+	var data handlers.Data
+	for _, pkg := range providerPkgs {
+		data.AddRoutes(routes.Extract(pkg, routes.WithAdapters(g.adapters()...))...)
+	}
+	gen.Routes = data.Routes
 
-			mtx.Lock()
-			results = append(results, res)
-			mtx.Unlock()
-			return nil
-		})
+	if key != "" {
+		if err := g.cache.Put(key, gen); err != nil {
+			logging.Debug("failed to save generation results to cache", "error", err)
+		}
 	}
 
-	if err := eg.Wait(); err != nil {
+	return gen, nil
+}
+
+// Lint parses configured handlers and models packages and prints parser diagnostics to w, without generating spec.
+// Error is returned, when diagnostics of error severity are reported, or any diagnostics with werror
+func (g *Generator) Lint(w io.Writer) error {
+	pkgs, results, err := g.loadAndParse(nil)
+	if err != nil {
 		return err
 	}
 
-	_imports, _types, err := g.processParserResults(results)
+	results = append(results, resolveInterfaces(pkgs, results, g.cfg.Input.Discriminator))
+	if err := reportDiagnostics(w, results, g.cfg.Werror); err != nil {
+		return err
+	}
+
+	var errs int
+	for _, res := range results {
+		for _, d := range res.Diagnostics {
+			if d.Severity == diag.SeverityError {
+				errs++
+			}
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("parser reported %d errors", errs)
+	}
+	return nil
+}
+
+// reportDiagnostics prints parser diagnostics of all results to w. With werror, any diagnostic fails generation
+func reportDiagnostics(w io.Writer, results []parser.Result, werror bool) error {
+	var diags []diag.Diagnostic
+	for _, res := range results {
+		diags = append(diags, res.Diagnostics...)
+	}
+	if len(diags) == 0 {
+		return nil
+	}
+
+	wd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("process parser results: %w", err)
+		logging.Debug("failed to get working directory, diagnostics are printed with absolute paths", "error", err)
 	}
 
-	return g.execTemplate(_imports, _types)
+	if err := diag.Print(w, wd, diags); err != nil {
+		return fmt.Errorf("print diagnostics: %w", err)
+	}
+
+	if werror {
+		return fmt.Errorf("parser reported %d diagnostics, treated as errors with werror", len(diags))
+	}
+	return nil
+}
+
+// handlersData serializes handlers metadata for generated program. Empty string is returned, if metadata can't be
+// serialized, so generated program parses handlers at runtime
+func (g *Generator) handlersData(gen generation) string {
+	data := handlers.NewData(gen.Results)
+	data.Routes = gen.Routes
+	data.TrimPaths(sourcesRoot())
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		logging.Warn("failed to serialize handlers data, handlers will be parsed by generated program", "error", err)
+		return ""
+	}
+
+	return goStringLiteral(string(b))
+}
+
+// sourcesRoot returns root of module in working directory, or working directory itself, if it's not in module.
+// Paths in generated files are relative to it
+func sourcesRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		logging.Debug("failed to get working directory", "error", err)
+		return ""
+	}
+
+	root, _, err := findModule(wd)
+	if err != nil {
+		logging.Debug("module of working directory not found", "dir", wd, "error", err)
+		return wd
+	}
+	return root
+}
+
+// loadAndParse loads configured handlers and models packages with extra patterns, parses them and instantiates
+// generic wrapper handlers. Packages are loaded with types, parsing results of unchanged packages are read from cache
+func (g *Generator) loadAndParse(extraPatterns []string) ([]*packages.Package, []parser.Result, error) {
+	patterns, err := g.buildLoadPatterns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("build load patterns: %w", err)
+	}
+	patterns = append(patterns, extraPatterns...)
+
+	logging.Debug("built packages load patterns", "patterns", patterns)
+
+	loaded, err := g.loader().LoadTyped(patterns, g.parseOpts()...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, _, err := g.instantiateGenerics(loaded.Packages, loaded.Results)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loaded.Packages, results, nil
+}
+
+func (g *Generator) loader() *parsecache.Loader {
+	return parsecache.NewLoader(g.parser, g.cache, g.cfg.Concurrency)
+}
+
+func (g *Generator) parseOpts() []parser.ParseOpt {
+	return parsecache.ParseOpts(g.adapterFuncs()...)
+}
+
+// instantiateGenerics instantiates generic wrapper handlers with instances, found in loaded packages and
+// routes provider package. Routes provider package is returned too, it's loaded separately, if it's not among pkgs
+func (g *Generator) instantiateGenerics(
+	pkgs []*packages.Package,
+	results []parser.Result,
+) ([]parser.Result, []*packages.Package, error) {
+	if g.cfg.Input.RoutesProviderPkg == "" {
+		parser.InstantiateGenerics(results)
+		return results, nil, nil
+	}
+
+	var providerPkgs []*packages.Package
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == g.cfg.Input.RoutesProviderPkg {
+			providerPkgs = append(providerPkgs, pkg)
+		}
+	}
+
+	if len(providerPkgs) == 0 {
+		// routes provider is usually main package, which can't be parsed for models,
+		// but generic wrappers are instantiated there, when routes are registered
+		cfg := &packages.Config{
+			Mode: packages.NeedTypes |
+				packages.NeedSyntax |
+				packages.NeedTypesInfo |
+				packages.NeedName,
+		}
+
+		var (
+			instances []parser.Instance
+			err       error
+		)
+		providerPkgs, instances, err = loadInstances(cfg, g.cfg.Input.RoutesProviderPkg)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, parser.Result{PkgPath: g.cfg.Input.RoutesProviderPkg, Instances: instances})
+	}
+
+	parser.InstantiateGenerics(results)
+	return results, providerPkgs, nil
+}
+
+// loadInstances loads package and returns it with instances of generic wrapper handlers, found in it
+func loadInstances(cfg *packages.Config, pkgPath string) ([]*packages.Package, []parser.Instance, error) {
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load package %s: %w", pkgPath, err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, nil, fmt.Errorf("failed to load package %s", pkgPath)
+	}
+
+	var res []parser.Instance
+	for _, pkg := range pkgs {
+		res = append(res, parser.FindInstances(pkg)...)
+	}
+	return pkgs, res, nil
 }
 
 func (g *Generator) processParserResults(results []parser.Result) ([]*importMapping, []*typing.Type, error) {
@@ -138,12 +380,10 @@ func (g *Generator) processParserResults(results []parser.Result) ([]*importMapp
 	initialImports := initialMapping()
 	if g.cfg.Output.IsMain() {
 		processImport("github.com/d1vbyz3r0/typed/handlers", initialImports)
-		processImport("log/slog", initialImports)
 		processImport("os", initialImports)
+		processImport("flag", initialImports)
+		processImport("github.com/d1vbyz3r0/typed/logging", initialImports)
 		processImport(g.cfg.Input.RoutesProviderPkg, initialImports)
-		if g.cfg.Debug {
-			processImport("github.com/d1vbyz3r0/typed/logging", initialImports)
-		}
 	}
 
 	_imports, err := createImportMappings(results, initialImports)
@@ -164,7 +404,7 @@ func (g *Generator) processParserResults(results []parser.Result) ([]*importMapp
 	return _imports, _types, nil
 }
 
-func (g *Generator) execTemplate(_imports []*importMapping, _types []*typing.Type) error {
+func (g *Generator) execTemplate(_imports []*importMapping, _types []*typing.Type, handlersData string) error {
 	resolveAlias := aliasNamer(_imports)
 	tmpl := template.Must(template.
 		New("spec").
@@ -172,6 +412,8 @@ func (g *Generator) execTemplate(_imports []*importMapping, _types []*typing.Typ
 			"typeToString":     typing.ToString,
 			"typeTreeToString": typing.TypeTreeToString,
 			"lastSegment":      meta.GetPkgName,
+			"deref":            func(s *string) string { return *s },
+			"routeFilter":      routeFilterLiteral,
 			"resolveAlias": func(t *typing.Type) string {
 				pkg, _ := resolveAlias(t)
 				return pkg
@@ -189,24 +431,59 @@ func (g *Generator) execTemplate(_imports []*importMapping, _types []*typing.Typ
 		}
 	}
 
+	spec, err := specData(g.cfg.Input.Metadata)
+	if err != nil {
+		return err
+	}
+
+	outputs := make([]SpecOutputArgs, 0, len(g.cfg.Output.Specs))
+	for _, s := range g.cfg.Output.Specs {
+		data, err := specData(g.cfg.Input.Metadata.Merge(s.Metadata))
+		if err != nil {
+			return fmt.Errorf("spec %s: %w", s.Name, err)
+		}
+
+		outputs = append(outputs, SpecOutputArgs{
+			Name:     s.Name,
+			Path:     s.SpecPath,
+			Spec:     data,
+			Include:  s.Routes.Include,
+			Exclude:  s.Routes.Exclude,
+			Overlays: s.Overlays,
+		})
+	}
+
 	var result bytes.Buffer
-	err := tmpl.Execute(&result, TemplateArgs{
+	err = tmpl.Execute(&result, TemplateArgs{
 		ApiPrefix:              g.cfg.Input.ApiPrefix,
 		Types:                  _types,
 		Imports:                _imports,
-		Title:                  g.cfg.Input.Title,
-		Version:                g.cfg.Input.Version,
-		Servers:                g.cfg.Input.Servers,
 		HandlersPkgs:           g.cfg.Input.Handlers,
 		RoutesProviderCtorName: g.cfg.Input.RoutesProviderCtor,
 		RoutesProviderPkgAlias: routesProviderPkgAlias,
+		RoutesProviderPkg:      g.cfg.Input.RoutesProviderPkg,
 		PackageName:            g.cfg.Output.Package(),
 		IsMain:                 g.cfg.Output.IsMain(),
 		SpecPath:               g.cfg.Output.SpecPath,
 		HandlerProcessingHooks: g.cfg.ProcessingHooks,
 		Concurrency:            g.cfg.Concurrency,
 		AliasNamer:             resolveAlias,
-		Debug:                  g.cfg.Debug,
+		LogFormat:              g.cfg.Log.Format,
+		LogLevel:               g.cfg.LogLevel(),
+		RenameRules:            g.cfg.Output.ComponentNames.Rename,
+		EmbeddedAllOf:          g.cfg.Output.EmbeddedAllOf,
+		AsyncAPIPath:           g.cfg.Output.AsyncAPIPath,
+		Adapters:               g.cfg.Input.Adapters,
+		CacheDir:               g.cfg.Cache.Dir,
+		CacheDisabled:          g.cfg.Cache.Disabled,
+		HandlersData:           handlersData,
+		Overlays:               g.cfg.Input.Overlays,
+		Spec:                   spec,
+		SpecOutputs:            outputs,
+		IncludeRoutes:          g.cfg.Input.Routes.Include,
+		ExcludeRoutes:          g.cfg.Input.Routes.Exclude,
+		Components:             g.cfg.Output.Components,
+		Report:                 g.cfg.Output.Report,
 	})
 	if err != nil {
 		return fmt.Errorf("execute template: %w", err)
@@ -1,13 +1,24 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
+	"go/token"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/d1vbyz3r0/typed"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,19 +35,556 @@ func TestGenerator_execTemplateNonMainPackage(t *testing.T) {
 	imports, err := createImportMappings(nil, initialMapping())
 	require.NoError(t, err)
 
-	require.NoError(t, g.execTemplate(imports, nil))
+	require.NoError(t, g.execTemplate(imports, nil, ""))
 
 	src, err := os.ReadFile(outputPath)
 	require.NoError(t, err)
 	generated := string(src)
 	require.True(t, strings.HasPrefix(generated, "// Code generated by typed. DO NOT EDIT\npackage generated\n"))
 	require.Contains(t, generated, "var registry = typed.MustNewRegistry(")
-	require.Contains(t, generated, "var spec = &openapi3.T{")
+	require.Contains(t, generated, "var spec = typed.MustParseSpec([]byte(`{")
 	require.NotContains(t, generated, "func main()")
 	require.NotContains(t, generated, "CollectRoutes")
 	require.NotContains(t, generated, "SaveSpec")
 }
 
+func TestGenerator_execTemplateAsyncAPI(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+			},
+			Output: OutputConfig{
+				Path:         outputPath,
+				SpecPath:     "openapi.yaml",
+				AsyncAPIPath: "asyncapi.yaml",
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Contains(t, generated, "asyncDoc := typed.NewAsyncAPIDocument(spec)")
+	require.Regexp(t, `AsyncAPI:\s+asyncDoc,`, generated)
+	require.Contains(t, generated, `typed.SaveAsyncAPI(asyncDoc, "asyncapi.yaml")`)
+	require.NotContains(t, generated, "typed.Report")
+}
+
+func TestGenerator_execTemplateComponents(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+			},
+			Output: OutputConfig{
+				Path:     outputPath,
+				SpecPath: "openapi.yaml",
+				Components: ComponentsConfig{
+					AlwaysInclude: []string{`^dto\.Event$`},
+					KeepUnused:    true,
+				},
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Regexp(t, `KeepUnusedComponents:\s+true,`, generated)
+	require.Regexp(t, `AlwaysIncludeComponents:\s+\[\]string\{"\^dto\\\\\.Event\$"\},`, generated)
+}
+
+func TestGenerator_execTemplateReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+			},
+			Output: OutputConfig{
+				Path:     outputPath,
+				SpecPath: "openapi.yaml",
+				Report:   ReportConfig{Path: "report.json", MinCoverage: 80.5},
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Contains(t, generated, "report := &typed.Report{}")
+	require.Regexp(t, `Report:\s+report,`, generated)
+	require.Regexp(t, `(?s)typed\.SaveSpec\(.*typed\.SaveReport\(report, "report.json"\).*report\.CheckCoverage\(80\.5\)`, generated)
+}
+
+func TestGenerator_execTemplateLogging(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantFormat string
+		wantLevel  string
+	}{
+		{name: "defaults"},
+		{name: "debug", cfg: Config{Debug: true}, wantLevel: "debug"},
+		{name: "log config", cfg: Config{Debug: true, Log: LogConfig{Format: "json", Level: "info"}}, wantFormat: "json", wantLevel: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "spec.go")
+			g := &Generator{cfg: tt.cfg}
+			g.cfg.Input = InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+			}
+			g.cfg.Output = OutputConfig{Path: outputPath, SpecPath: "openapi.yaml"}
+
+			initial := initialMapping()
+			processImport(g.cfg.Input.RoutesProviderPkg, initial)
+			imports, err := createImportMappings(nil, initial)
+			require.NoError(t, err)
+
+			require.NoError(t, g.execTemplate(imports, nil, ""))
+
+			src, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			generated := string(src)
+			require.Contains(t, generated, `flag.String("log-format", `+strconv.Quote(tt.wantFormat)+`, "log format, text or json")`)
+			require.Contains(t, generated, `flag.String("log-level", `+strconv.Quote(tt.wantLevel)+`, "log level, debug, info, warn or error")`)
+			require.Contains(t, generated, "logging.Configure(os.Stderr, *logFormat, *logLevel)")
+		})
+	}
+}
+
+func TestGenerator_execTemplateOverlays(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+				Overlays:           []string{"api/docs.yaml", "api/servers.yaml"},
+			},
+			Output: OutputConfig{
+				Path:     outputPath,
+				SpecPath: "openapi.yaml",
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Contains(t, generated, "var overlays = []string{\n\t\"api/docs.yaml\",\n\t\"api/servers.yaml\",\n}")
+	require.Regexp(t, `(?s)typed\.ApplyOverlays\(spec, overlays\.\.\.\).*typed\.SaveSpec`, generated)
+}
+
+func TestGenerator_execTemplateSpec(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				Metadata: Metadata{
+					Title:          "Users \"API\"",
+					Version:        "1.0.0",
+					Description:    "Manages `users`.\nSee docs",
+					TermsOfService: "https://example.com/terms",
+					Contact:        &Contact{Name: "API team", Email: "api@example.com"},
+					License:        &License{Name: "MIT", URL: "https://opensource.org/licenses/MIT"},
+					Servers: []Server{{
+						Url:         "https://{env}.example.com",
+						Description: "Main server",
+						Variables:   map[string]ServerVariable{"env": {Default: "prod", Enum: []string{"prod", "stage"}}},
+					}},
+					Tags:         []Tag{{Name: "users", Description: "Users management", ExternalDocs: &ExternalDocs{URL: "https://example.com/users"}}},
+					ExternalDocs: &ExternalDocs{URL: "https://example.com/docs", Description: "Guide"},
+					Security:     []map[string][]string{{"bearer": nil}, {"oauth": {"users:read"}}},
+				},
+				ApiPrefix:          typed.MakePointer(`/api/"v1"`),
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+			},
+			Output: OutputConfig{
+				Path:     outputPath,
+				SpecPath: `docs/"openapi".yaml`,
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Regexp(t, `APIPrefix:\s+typed\.MakePointer\("/api/\\"v1\\""\),`, generated)
+	require.Contains(t, generated, `typed.SaveSpec(spec, "docs/\"openapi\".yaml")`)
+
+	// description contains backquote, so spec is embedded as interpreted string literal
+	literal := regexp.MustCompile(`var spec = typed\.MustParseSpec\(\[\]byte\((".*")\)\)`).FindStringSubmatch(generated)
+	require.Len(t, literal, 2)
+	data, err := strconv.Unquote(literal[1])
+	require.NoError(t, err)
+
+	spec, err := typed.ParseSpec([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, g.cfg.Input.Title, spec.Info.Title)
+	require.Equal(t, g.cfg.Input.Description, spec.Info.Description)
+	require.Equal(t, "https://example.com/terms", spec.Info.TermsOfService)
+	require.Equal(t, "api@example.com", spec.Info.Contact.Email)
+	require.Equal(t, "MIT", spec.Info.License.Name)
+	require.Len(t, spec.Servers, 1)
+	require.Equal(t, "Main server", spec.Servers[0].Description)
+	require.Equal(t, []string{"prod", "stage"}, spec.Servers[0].Variables["env"].Enum)
+	require.Equal(t, "https://example.com/users", spec.Tags.Get("users").ExternalDocs.URL)
+	require.Equal(t, "Guide", spec.ExternalDocs.Description)
+	require.Equal(t, openapi3.SecurityRequirements{
+		{"bearer": {}},
+		{"oauth": {"users:read"}},
+	}, spec.Security)
+}
+
+func TestGenerator_execTemplateSpecOutputs(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				Metadata:           Metadata{Title: "app", Version: "1.0.0"},
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+				Routes: RoutesConfig{
+					Exclude: []RouteFilter{{Path: "^/debug/pprof"}, {Handler: "^Health$", Name: "health"}},
+				},
+			},
+			Output: OutputConfig{
+				Path: outputPath,
+				Specs: []SpecConfig{{
+					Name:     "admin",
+					SpecPath: "admin.yaml",
+					Metadata: Metadata{Title: "admin"},
+					Routes: RoutesConfig{
+						Include: []RouteFilter{{PathPrefix: "/api/admin", Methods: []string{"GET", "POST"}}},
+						Exclude: []RouteFilter{{Middleware: `echo-jwt\.`, HandlerPkg: "internal$"}},
+					},
+					Overlays: []string{"admin.overlay.yaml"},
+				}},
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Contains(t, generated, "var specOutputs = []*typed.SpecOutput{")
+	require.Contains(t, generated, `{PathPrefix: "/api/admin", Methods: []string{"GET", "POST"}},`)
+	require.Contains(t, generated, `{Middleware: "echo-jwt\\.", HandlerPkg: "internal$"},`)
+	require.Contains(t, generated, "ExcludeRoutes: []typed.RouteFilter{\n\t\t\t{Path: \"^/debug/pprof\"},\n\t\t\t{Handler: \"^Health$\", Name: \"health\"},\n\t\t},")
+	require.NotContains(t, generated, "IncludeRoutes")
+	require.Regexp(t, `Overlays:\s+\[\]string\{"admin.overlay.yaml"\},`, generated)
+	require.Regexp(t, `Outputs:\s+specOutputs,`, generated)
+	require.Contains(t, generated, "typed.SaveSpecOutputs(specOutputs...)")
+	require.NotContains(t, generated, "typed.SaveSpec(spec,")
+	require.Contains(t, generated, `"title": "admin",`)
+	require.Contains(t, generated, `"version": "1.0.0"`)
+}
+
+func TestGoStringLiteral(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: `{"title": "a \"b\""}`, want: "`{\"title\": \"a \\\"b\\\"\"}`"},
+		{in: "{\n  \"a\": 1\n}", want: "`{\n  \"a\": 1\n}`"},
+		{in: "{\"a\": \"`b`\"}", want: `"{\"a\": \"` + "`b`" + `\"}"`},
+		{in: "a\rb", want: `"a\rb"`},
+		{in: "\ufeffa", want: `"\ufeffa"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := goStringLiteral(tt.in)
+			require.Equal(t, tt.want, got)
+
+			unquoted, err := strconv.Unquote(got)
+			require.NoError(t, err)
+			require.Equal(t, tt.in, unquoted)
+		})
+	}
+}
+
+func TestReportDiagnostics(t *testing.T) {
+	results := []parser.Result{
+		{PkgPath: "example.com/app"},
+		{
+			PkgPath: "example.com/api",
+			Diagnostics: []diag.Diagnostic{{
+				Pos:      token.Position{Filename: "users.go", Line: 42, Column: 9},
+				Handler:  "GetUser",
+				Severity: diag.SeverityError,
+				Code:     diag.NonConstantStatusCode,
+				Message:  "response skipped, unresolved status code",
+			}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		results []parser.Result
+		werror  bool
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:    "no diagnostics",
+			results: results[:1],
+			werror:  true,
+		},
+		{
+			name:    "diagnostics",
+			results: results,
+			wantOut: "users.go:42:9: error: GetUser: response skipped, unresolved status code (TYP001)\n",
+		},
+		{
+			name:    "werror",
+			results: results,
+			werror:  true,
+			wantOut: "users.go:42:9: error: GetUser: response skipped, unresolved status code (TYP001)\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := reportDiagnostics(&buf, tt.results, tt.werror)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantOut, buf.String())
+		})
+	}
+}
+
+func TestGenerator_Lint(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		wantOut string
+		wantErr string
+	}{
+		{name: "clean", fixture: "static"},
+		{
+			name:    "errors",
+			fixture: "parser/detection",
+			wantOut: "detection.go:85:9: error: Skipped: response skipped",
+			wantErr: "parser reported 1 errors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testsuite.FixturePath(t, tt.fixture)
+			g, err := New(Config{
+				Input: InputConfig{Handlers: []HandlersConfig{{Path: fixture}}},
+				Cache: CacheConfig{Disabled: true},
+			})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = g.Lint(&buf)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				require.Empty(t, buf.String())
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+			require.Contains(t, buf.String(), tt.wantOut)
+		})
+	}
+}
+
+func TestGenerator_execTemplateAdapters(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+	g := &Generator{
+		cfg: Config{
+			Input: InputConfig{
+				RoutesProviderCtor: "NewServer",
+				RoutesProviderPkg:  "example.com/project/server",
+				Adapters: []AdapterConfig{
+					{Func: "example.com/project/web.Wrap"},
+					{Func: "example.com/project/web.Named", HandlerArg: 1},
+				},
+			},
+			Output: OutputConfig{
+				Path:     outputPath,
+				SpecPath: "openapi.yaml",
+			},
+		},
+	}
+
+	initial := initialMapping()
+	processImport(g.cfg.Input.RoutesProviderPkg, initial)
+	imports, err := createImportMappings(nil, initial)
+	require.NoError(t, err)
+
+	require.NoError(t, g.execTemplate(imports, nil, ""))
+
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	generated := string(src)
+	require.Contains(t, generated, "Adapters: []handlers.Adapter{")
+	require.Contains(t, generated, `{Func: "example.com/project/web.Wrap", HandlerArg: 0},`)
+	require.Contains(t, generated, `{Func: "example.com/project/web.Named", HandlerArg: 1},`)
+}
+
+func TestGenerator_execTemplateCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		cache       CacheConfig
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "default dir",
+			contains: []string{"cacheDir := typed.DefaultCacheDir()", "CacheDir:", `flag.Bool("no-cache"`},
+		},
+		{
+			name:     "configured dir",
+			cache:    CacheConfig{Dir: ".cache/typed"},
+			contains: []string{`cacheDir := ".cache/typed"`, "CacheDir:"},
+		},
+		{
+			name:        "disabled",
+			cache:       CacheConfig{Disabled: true},
+			notContains: []string{"cacheDir", "no-cache"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "spec.go")
+			g := &Generator{
+				cfg: Config{
+					Input: InputConfig{
+						RoutesProviderCtor: "NewServer",
+						RoutesProviderPkg:  "example.com/project/server",
+					},
+					Output: OutputConfig{
+						Path:     outputPath,
+						SpecPath: "openapi.yaml",
+					},
+					Cache: tt.cache,
+				},
+			}
+
+			initial := initialMapping()
+			processImport(g.cfg.Input.RoutesProviderPkg, initial)
+			imports, err := createImportMappings(nil, initial)
+			require.NoError(t, err)
+
+			require.NoError(t, g.execTemplate(imports, nil, ""))
+
+			src, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				require.Contains(t, string(src), s)
+			}
+			for _, s := range tt.notContains {
+				require.NotContains(t, string(src), s)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateHandlersData(t *testing.T) {
+	const pkg = "github.com/d1vbyz3r0/typed/testdata/static"
+	fixture := testsuite.FixturePath(t, "static")
+	outputPath := filepath.Join(t.TempDir(), "spec.go")
+
+	g, err := New(Config{
+		Input: InputConfig{
+			RoutesProviderCtor: "NewServer",
+			RoutesProviderPkg:  pkg,
+			Handlers:           []HandlersConfig{{Path: fixture}},
+			Models:             []ModelsConfig{{Path: fixture}},
+		},
+		Output: OutputConfig{
+			Path:     outputPath,
+			SpecPath: "openapi.yaml",
+		},
+		Cache: CacheConfig{Dir: t.TempDir()},
+	})
+	require.NoError(t, err)
+
+	gen, err := g.generateResults()
+	require.NoError(t, err)
+	require.Equal(t, "("+pkg+".UsersHandler).GetUser", gen.Routes["GET /api/v1/users/:id"])
+
+	literal := g.handlersData(gen)
+	require.True(t, strings.HasPrefix(literal, "`"))
+	raw, err := strconv.Unquote(literal)
+	require.NoError(t, err)
+
+	var data handlers.Data
+	require.NoError(t, json.Unmarshal([]byte(raw), &data))
+	require.Equal(t, gen.Routes, data.Routes)
+	require.Len(t, data.Handlers, 5)
+	require.NotContains(t, raw, fixture, "positions of diagnostics don't depend on location of sources")
+
+	require.NoError(t, g.Generate())
+	src, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "var handlersData = []byte(`{\"handlers\":")
+	require.Contains(t, string(src), "HandlersData:")
+}
+
 func TestGenerator_filterModels(t *testing.T) {
 	root := makeTestModule(t)
 	tests := []struct {
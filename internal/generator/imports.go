@@ -43,11 +43,9 @@ func createImportMappings(
 				}
 			}
 
-			for _, responses := range handler.Responses {
-				for _, resp := range responses {
-					if err := typing.Traverse(resp.ModelType, processType); err != nil {
-						return nil, fmt.Errorf("traverse %s: %w", resp.ModelType, err)
-					}
+			for _, model := range handler.Models() {
+				if err := typing.Traverse(model, processType); err != nil {
+					return nil, fmt.Errorf("traverse %s: %w", model, err)
 				}
 			}
 		}
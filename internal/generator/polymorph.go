@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/polymorph"
+	"github.com/d1vbyz3r0/typed/logging"
+	"golang.org/x/tools/go/packages"
+)
+
+// resolveInterfaces finds implementations for all interfaces used in results.
+// Interfaces declared in model packages without implementations are removed from additional models,
+// since they can't be described with schema. Resolved interfaces and their implementations are returned as separate result
+// with diagnostics of resolver.
+func resolveInterfaces(pkgs []*packages.Package, results []parser.Result, discriminator string) parser.Result {
+	resolver := polymorph.NewResolver(pkgs, discriminator)
+	resolved := make(map[string]*typing.Type)
+	resolve := func(t *typing.Type) bool {
+		if t.Kind() != typing.TypeKindInterface {
+			return true
+		}
+
+		k := typing.ToString(t, typing.DefaultNamer)
+		if _, ok := resolved[k]; ok {
+			return true
+		}
+
+		res, ok := resolver.Resolve(t)
+		if !ok {
+			return false
+		}
+
+		resolved[k] = res
+		return true
+	}
+
+	for i := range results {
+		for _, h := range results[i].Handlers {
+			if h.Request != nil {
+				_ = typing.Traverse(h.Request.ModelType, func(n *typing.Type) { resolve(n) })
+			}
+
+			for _, model := range h.Models() {
+				_ = typing.Traverse(model, func(n *typing.Type) { resolve(n) })
+			}
+		}
+
+		results[i].AdditionalModels = slices.DeleteFunc(results[i].AdditionalModels, func(t *typing.Type) bool {
+			if t == nil || t.Kind() != typing.TypeKindInterface {
+				return false
+			}
+
+			if !resolve(t) {
+				logging.Debug("removing interface without implementations from models", "interface", t)
+				return true
+			}
+			return false
+		})
+	}
+
+	res := parser.Result{Diagnostics: resolver.Diagnostics()}
+	for _, k := range slices.Sorted(maps.Keys(resolved)) {
+		res.AdditionalModels = append(res.AdditionalModels, resolved[k])
+	}
+	return res
+}
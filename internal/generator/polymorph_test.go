@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func Test_resolveInterfaces(t *testing.T) {
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/polymorph"
+	pkg := testsuite.LoadFixturePackage(t, "polymorph")
+
+	results := []parser.Result{
+		{
+			PkgPath: pkgPath,
+			AdditionalModels: []*typing.Type{
+				typing.Named(pkgPath, "Envelope"),
+				typing.Interface(typing.Named(pkgPath, "Event"), ""),
+				typing.Interface(typing.Named(pkgPath, "Shape"), ""),
+			},
+		},
+	}
+
+	res := resolveInterfaces([]*packages.Package{pkg}, results, "")
+
+	require.Equal(t, []string{
+		pkgPath + ".Envelope",
+		pkgPath + ".Event",
+	}, modelKeys(results[0].AdditionalModels), "interface without implementations should be removed")
+
+	require.Len(t, res.AdditionalModels, 1)
+	event := res.AdditionalModels[0]
+	require.Equal(t, typing.TypeKindInterface, event.Kind())
+	require.Equal(t, "type", event.Discriminator())
+	require.Len(t, event.Implementations(), 3)
+
+	types, err := collectTypes(append(results, res))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		pkgPath + ".Created",
+		pkgPath + ".Deleted",
+		pkgPath + ".Envelope",
+		pkgPath + ".Event",
+		pkgPath + ".Unknown",
+	}, modelKeys(types))
+
+	for _, typ := range types {
+		if typ.Name() == "Event" {
+			require.Len(t, typ.Implementations(), 3, "resolved interface should replace unresolved one")
+		}
+	}
+}
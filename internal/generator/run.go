@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// GenerateSpec runs both generation stages: it renders spec builder program, builds it in temporary directory and
+// runs it, so spec is written to spec-path. In static mode spec is generated in one process, see GenerateStatic
+func (g *Generator) GenerateSpec(ctx context.Context, static bool) error {
+	if static {
+		return g.GenerateStatic()
+	}
+
+	if !g.cfg.Output.IsMain() {
+		return errors.New("spec builder program can't be run, when output package is not main")
+	}
+
+	if err := g.Generate(); err != nil {
+		return fmt.Errorf("generate spec builder: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "typed-")
+	if err != nil {
+		return fmt.Errorf("create build directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bin := filepath.Join(dir, "spec-builder")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+
+	build := exec.CommandContext(ctx, "go", "build", "-o", bin, g.cfg.Output.Path)
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("build spec builder: %w", err)
+	}
+
+	var args []string
+	if g.cfg.Cache.NoCache && !g.cfg.Cache.Disabled {
+		args = append(args, "-no-cache")
+	}
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run spec builder: %w", err)
+	}
+
+	return nil
+}
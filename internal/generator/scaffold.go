@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/logging"
+	"golang.org/x/tools/go/packages"
+)
+
+// RoutesProviderCtor is a function without parameters, returning typed.RoutesProvider implementation
+type RoutesProviderCtor struct {
+	Pkg  string
+	Name string
+}
+
+// Scaffold is a starting config of module, created from its source code
+type Scaffold struct {
+	Config Config
+	// Ctors are found routes provider constructors, the first one is used in config
+	Ctors []RoutesProviderCtor
+}
+
+// NewScaffold scans packages of module in dir for echo handlers and routes provider constructors.
+// Handler packages are added to config with paths relative to dir, spec builder is generated into gen directory.
+// Packages with errors are skipped
+func NewScaffold(dir string) (Scaffold, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return Scaffold{}, fmt.Errorf("resolve dir: %w", err)
+	}
+
+	cfg := &packages.Config{
+		Dir: absDir,
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedModule,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return Scaffold{}, fmt.Errorf("load packages: %w", err)
+	}
+
+	slices.SortFunc(pkgs, func(a, b *packages.Package) int {
+		return strings.Compare(a.PkgPath, b.PkgPath)
+	})
+
+	var res Scaffold
+	for _, pkg := range pkgs {
+		if res.Config.Input.Title == "" && pkg.Module != nil {
+			res.Config.Input.Title = path.Base(pkg.Module.Path)
+		}
+
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+
+		// scaffold is a starting point, so packages, which can't be type checked, are skipped instead of failing
+		if len(pkg.Errors) > 0 {
+			logging.Warn("skipping package with errors", logging.Pkg(pkg.PkgPath), "error", pkg.Errors[0])
+			continue
+		}
+
+		if parser.ContainsHandlers(pkg) {
+			rel, err := filepath.Rel(absDir, filepath.Dir(pkg.GoFiles[0]))
+			if err != nil {
+				return Scaffold{}, fmt.Errorf("resolve path of package %s: %w", pkg.PkgPath, err)
+			}
+
+			logging.Debug("found handlers package", logging.Pkg(pkg.PkgPath))
+			res.Config.Input.Handlers = append(res.Config.Input.Handlers, HandlersConfig{Path: "./" + filepath.ToSlash(rel)})
+		}
+
+		res.Ctors = append(res.Ctors, routesProviderCtors(pkg)...)
+	}
+
+	if len(res.Ctors) > 0 {
+		res.Config.Input.RoutesProviderPkg = res.Ctors[0].Pkg
+		res.Config.Input.RoutesProviderCtor = res.Ctors[0].Name
+	}
+
+	res.Config.Input.Version = "0.0.1"
+	res.Config.Output.Path = "gen/spec.go"
+	res.Config.Output.SpecPath = "gen/openapi.yaml"
+	return res, nil
+}
+
+// routesProviderCtors returns exported functions of package without parameters, which return routes provider.
+// Main packages can't be imported by spec builder, so they are skipped
+func routesProviderCtors(pkg *packages.Package) []RoutesProviderCtor {
+	if pkg.Name == "main" {
+		return nil
+	}
+
+	var res []RoutesProviderCtor
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		fn, ok := scope.Lookup(name).(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+
+		sig := fn.Signature()
+		if sig.Params().Len() != 0 || sig.TypeParams().Len() != 0 || sig.Results().Len() == 0 {
+			continue
+		}
+
+		if isRoutesProvider(sig.Results().At(0).Type()) {
+			logging.Debug("found routes provider constructor", logging.Pkg(pkg.PkgPath), "name", name)
+			res = append(res, RoutesProviderCtor{Pkg: pkg.PkgPath, Name: name})
+		}
+	}
+	return res
+}
+
+// isRoutesProvider reports if method set of t has OnRouteAdded and ProvideRoutes methods of typed.RoutesProvider
+func isRoutesProvider(t types.Type) bool {
+	mset := types.NewMethodSet(t)
+
+	onRouteAdded := mset.Lookup(nil, "OnRouteAdded")
+	if onRouteAdded == nil {
+		return false
+	}
+	sig := onRouteAdded.Obj().Type().(*types.Signature)
+	if sig.Params().Len() != 1 {
+		return false
+	}
+	callback, ok := sig.Params().At(0).Type().Underlying().(*types.Signature)
+	if !ok || callback.Params().Len() != 4 {
+		return false
+	}
+
+	provideRoutes := mset.Lookup(nil, "ProvideRoutes")
+	if provideRoutes == nil {
+		return false
+	}
+	return provideRoutes.Obj().Type().(*types.Signature).Params().Len() == 0
+}
+
+var scaffoldTemplate = template.Must(template.New("scaffold").Parse(`# Created by typed init. See README for all config keys.
+input:
+  title: {{ printf "%q" .Config.Input.Title }}
+  version: {{ printf "%q" .Config.Input.Version }}
+{{- if .Config.Input.RoutesProviderCtor }}
+  # Constructor of typed.RoutesProvider implementation.
+  {{- range slice .Ctors 1 }}
+  # Other found constructors: {{ .Pkg }}.{{ .Name }}
+  {{- end }}
+  routes-provider-ctor: {{ printf "%q" .Config.Input.RoutesProviderCtor }}
+  routes-provider-pkg: {{ printf "%q" .Config.Input.RoutesProviderPkg }}
+{{- else }}
+  # Constructor of typed.RoutesProvider implementation wasn't found, set it
+  # and its package.
+  routes-provider-ctor: ""
+  routes-provider-pkg: ""
+{{- end }}
+{{- if .Config.Input.Handlers }}
+  handlers:
+  {{- range .Config.Input.Handlers }}
+    - path: {{ printf "%q" .Path }}
+  {{- end }}
+{{- else }}
+  # No echo handlers were found, add packages with handlers:
+  # handlers:
+  #   - path: ./api
+{{- end }}
+
+output:
+  path: {{ printf "%q" .Config.Output.Path }}
+  spec-path: {{ printf "%q" .Config.Output.SpecPath }}
+`))
+
+// Write writes scaffold as commented YAML config
+func (s Scaffold) Write(w io.Writer) error {
+	if err := scaffoldTemplate.Execute(w, s); err != nil {
+		return fmt.Errorf("execute scaffold template: %w", err)
+	}
+	return nil
+}
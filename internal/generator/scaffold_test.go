@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewScaffold(t *testing.T) {
+	dir := testsuite.FixturePath(t, "scaffold")
+
+	s, err := NewScaffold(dir)
+	require.NoError(t, err)
+
+	require.Equal(t, []RoutesProviderCtor{
+		{Pkg: "github.com/d1vbyz3r0/typed/testdata/scaffold/server", Name: "NewServer"},
+	}, s.Ctors)
+	require.Equal(t, []HandlersConfig{{Path: "./api"}}, s.Config.Input.Handlers)
+	require.Equal(t, "NewServer", s.Config.Input.RoutesProviderCtor)
+	require.Equal(t, "github.com/d1vbyz3r0/typed/testdata/scaffold/server", s.Config.Input.RoutesProviderPkg)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Write(&buf))
+
+	configPath := filepath.Join(t.TempDir(), "typed.yaml")
+	require.NoError(t, os.WriteFile(configPath, buf.Bytes(), 0644))
+
+	cfg, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, s.Config, cfg)
+}
+
+func TestScaffold_WriteWithoutRoutesProvider(t *testing.T) {
+	s := Scaffold{Config: Config{
+		Input:  InputConfig{Metadata: Metadata{Title: "app", Version: "0.0.1"}},
+		Output: OutputConfig{Path: "gen/spec.go", SpecPath: "gen/openapi.yaml"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Write(&buf))
+	require.Contains(t, buf.String(), "Constructor of typed.RoutesProvider implementation wasn't found")
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &cfg))
+	require.Equal(t, s.Config, cfg)
+	require.EqualError(t, cfg.Validate(), "routes-provider-ctor is required")
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/d1vbyz3r0/typed"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// newSpec creates spec with info, servers, tags, external docs and security requirements from metadata
+func newSpec(in Metadata) *openapi3.T {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:          in.Title,
+			Version:        in.Version,
+			Description:    in.Description,
+			TermsOfService: in.TermsOfService,
+		},
+		ExternalDocs: newExternalDocs(in.ExternalDocs),
+	}
+
+	if in.Contact != nil {
+		spec.Info.Contact = &openapi3.Contact{
+			Name:  in.Contact.Name,
+			URL:   in.Contact.URL,
+			Email: in.Contact.Email,
+		}
+	}
+
+	if in.License != nil {
+		spec.Info.License = &openapi3.License{
+			Name: in.License.Name,
+			URL:  in.License.URL,
+		}
+	}
+
+	for _, server := range in.Servers {
+		s := &openapi3.Server{
+			URL:         server.Url,
+			Description: server.Description,
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(server.Variables)) {
+			v := server.Variables[name]
+			if s.Variables == nil {
+				s.Variables = make(map[string]*openapi3.ServerVariable, len(server.Variables))
+			}
+			s.Variables[name] = &openapi3.ServerVariable{
+				Default:     v.Default,
+				Enum:        v.Enum,
+				Description: v.Description,
+			}
+		}
+
+		spec.Servers = append(spec.Servers, s)
+	}
+
+	for _, tag := range in.Tags {
+		spec.Tags = append(spec.Tags, &openapi3.Tag{
+			Name:         tag.Name,
+			Description:  tag.Description,
+			ExternalDocs: newExternalDocs(tag.ExternalDocs),
+		})
+	}
+
+	for _, req := range in.Security {
+		requirement := openapi3.NewSecurityRequirement()
+		for scheme, scopes := range req {
+			if scopes == nil {
+				scopes = []string{}
+			}
+			requirement[scheme] = scopes
+		}
+		spec.Security = append(spec.Security, requirement)
+	}
+
+	return spec
+}
+
+func newExternalDocs(docs *ExternalDocs) *openapi3.ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &openapi3.ExternalDocs{
+		URL:         docs.URL,
+		Description: docs.Description,
+	}
+}
+
+// specData serializes spec, created from metadata, as Go string literal, so generated program doesn't interpolate
+// config values into Go code
+func specData(m Metadata) (string, error) {
+	b, err := json.MarshalIndent(newSpec(m), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal spec: %w", err)
+	}
+	return goStringLiteral(string(b)), nil
+}
+
+// specOutputs creates spec outputs from specs of output config, metadata of specs overrides input one
+func (g *Generator) specOutputs() []*typed.SpecOutput {
+	res := make([]*typed.SpecOutput, 0, len(g.cfg.Output.Specs))
+	for _, s := range g.cfg.Output.Specs {
+		res = append(res, &typed.SpecOutput{
+			Name:     s.Name,
+			Path:     s.SpecPath,
+			Spec:     newSpec(g.cfg.Input.Metadata.Merge(s.Metadata)),
+			Include:  routeFilters(s.Routes.Include),
+			Exclude:  routeFilters(s.Routes.Exclude),
+			Overlays: s.Overlays,
+		})
+	}
+	return res
+}
+
+func routeFilters(filters []RouteFilter) []typed.RouteFilter {
+	res := make([]typed.RouteFilter, 0, len(filters))
+	for _, f := range filters {
+		res = append(res, typed.RouteFilter(f))
+	}
+	return res
+}
+
+// routeFilterLiteral renders set fields of filter as typed.RouteFilter composite literal without type
+func routeFilterLiteral(f RouteFilter) string {
+	var fields []string
+	if f.Path != "" {
+		fields = append(fields, "Path: "+strconv.Quote(f.Path))
+	}
+	if f.PathPrefix != "" {
+		fields = append(fields, "PathPrefix: "+strconv.Quote(f.PathPrefix))
+	}
+	if len(f.Methods) > 0 {
+		fields = append(fields, fmt.Sprintf("Methods: %#v", f.Methods))
+	}
+	if len(f.Tags) > 0 {
+		fields = append(fields, fmt.Sprintf("Tags: %#v", f.Tags))
+	}
+	if f.Middleware != "" {
+		fields = append(fields, "Middleware: "+strconv.Quote(f.Middleware))
+	}
+	if f.Handler != "" {
+		fields = append(fields, "Handler: "+strconv.Quote(f.Handler))
+	}
+	if f.HandlerPkg != "" {
+		fields = append(fields, "HandlerPkg: "+strconv.Quote(f.HandlerPkg))
+	}
+	if f.Name != "" {
+		fields = append(fields, "Name: "+strconv.Quote(f.Name))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// goStringLiteral returns raw string literal of s, if possible, or interpreted one otherwise
+func goStringLiteral(s string) string {
+	// raw string literals can't contain backquotes, carriage returns are dropped from them
+	// and BOM isn't allowed in Go source
+	if utf8.ValidString(s) && !strings.ContainsAny(s, "`\r\x00\ufeff") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,217 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/d1vbyz3r0/typed"
+	"github.com/d1vbyz3r0/typed/asyncapi"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/routes"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/tools/go/packages"
+)
+
+// GenerateStatic generates spec in one process, without rendering and running spec builder program.
+// Schemas are built from go/types and routes are discovered in routes provider package source code.
+func (g *Generator) GenerateStatic() error {
+	if g.cfg.Input.RoutesProviderPkg == "" {
+		return errors.New("routes-provider-pkg is required in static mode")
+	}
+
+	if g.cfg.Output.SpecPath == "" && len(g.cfg.Output.Specs) == 0 {
+		return errors.New("spec-path or specs are required in static mode")
+	}
+
+	if len(g.cfg.ProcessingHooks) > 0 {
+		logging.Warn("processing hooks are not supported in static mode, since middlewares are unknown", "hooks", g.cfg.ProcessingHooks)
+	}
+
+	for _, f := range slices.Concat(g.cfg.Input.Routes.Include, g.cfg.Input.Routes.Exclude) {
+		if f.Middleware != "" {
+			logging.Warn("middleware route filters don't match in static mode, since middlewares are unknown", "middleware", f.Middleware)
+		}
+	}
+
+	for _, spec := range g.cfg.Output.Specs {
+		for _, f := range slices.Concat(spec.Routes.Include, spec.Routes.Exclude) {
+			if f.Middleware != "" {
+				logging.Warn("middleware route filters don't match in static mode, since middlewares are unknown", "spec", spec.Name, "middleware", f.Middleware)
+			}
+		}
+	}
+
+	pkgs, results, err := g.loadAndParse([]string{g.cfg.Input.RoutesProviderPkg})
+	if err != nil {
+		return err
+	}
+
+	results = append(results, resolveInterfaces(pkgs, results, g.cfg.Input.Discriminator))
+	if err := reportDiagnostics(os.Stderr, results, g.cfg.Werror); err != nil {
+		return err
+	}
+
+	models, err := collectTypes(results)
+	if err != nil {
+		return fmt.Errorf("collect types: %w", err)
+	}
+
+	models, err = g.filterModels(models)
+	if err != nil {
+		return fmt.Errorf("filter models: %w", err)
+	}
+
+	rules := make([]typed.RenameRule, 0, len(g.cfg.Output.ComponentNames.Rename))
+	for _, r := range g.cfg.Output.ComponentNames.Rename {
+		rule, err := typed.NewRenameRule(r.Match, r.Replace)
+		if err != nil {
+			return fmt.Errorf("create rename rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	namer := typed.NewComponentNamer(nil, rules...)
+	var sourceOpts []typed.StaticModelSourceOpt
+	if g.cfg.Output.EmbeddedAllOf {
+		sourceOpts = append(sourceOpts, typed.WithStaticEmbeddedAllOf())
+	}
+
+	modelSource, err := typed.NewStaticModelSource(pkgs, models, namer, sourceOpts...)
+	if err != nil {
+		return fmt.Errorf("create static model source: %w", err)
+	}
+
+	spec := newSpec(g.cfg.Input.Metadata)
+	outputs := g.specOutputs()
+	var asyncDoc *asyncapi.Document
+	if g.cfg.Output.AsyncAPIPath != "" {
+		asyncDoc = typed.NewAsyncAPIDocument(spec)
+	}
+
+	var report *typed.Report
+	if g.cfg.Output.Report.Enabled() {
+		report = &typed.Report{}
+	}
+
+	err = typed.Generate(typed.GenerateOptions{
+		Spec:                    spec,
+		Namer:                   namer,
+		Models:                  modelSource,
+		Handlers:                matchStaticRoutes(pkgs, g.cfg.Input.RoutesProviderPkg, results, report, g.adapters()...),
+		APIPrefix:               g.cfg.Input.ApiPrefix,
+		Concurrency:             g.cfg.Concurrency,
+		AsyncAPI:                asyncDoc,
+		Outputs:                 outputs,
+		IncludeRoutes:           routeFilters(g.cfg.Input.Routes.Include),
+		ExcludeRoutes:           routeFilters(g.cfg.Input.Routes.Exclude),
+		KeepUnusedComponents:    g.cfg.Output.Components.KeepUnused,
+		AlwaysIncludeComponents: g.cfg.Output.Components.AlwaysInclude,
+		Report:                  report,
+	})
+	if err != nil {
+		return fmt.Errorf("generate spec: %w", err)
+	}
+
+	if err := typed.ApplyOverlays(spec, g.cfg.Input.Overlays...); err != nil {
+		return fmt.Errorf("apply overlays: %w", err)
+	}
+
+	if g.cfg.Output.SpecPath != "" {
+		if err := typed.SaveSpec(spec, g.cfg.Output.SpecPath); err != nil {
+			return fmt.Errorf("save spec: %w", err)
+		}
+	}
+
+	if err := typed.SaveSpecOutputs(outputs...); err != nil {
+		return fmt.Errorf("save spec outputs: %w", err)
+	}
+
+	if asyncDoc != nil {
+		if err := typed.SaveAsyncAPI(asyncDoc, g.cfg.Output.AsyncAPIPath); err != nil {
+			return fmt.Errorf("save asyncapi document: %w", err)
+		}
+	}
+
+	if g.cfg.Output.Report.Path != "" {
+		if err := typed.SaveReport(report, g.cfg.Output.Report.Path); err != nil {
+			return fmt.Errorf("save report: %w", err)
+		}
+	}
+
+	if report != nil {
+		if err := report.CheckCoverage(g.cfg.Output.Report.MinCoverage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchStaticRoutes extracts routes from routes provider package and matches them with parsed handlers.
+// Unmatched routes are added to report, if it's set
+func matchStaticRoutes(
+	pkgs []*packages.Package,
+	routesPkg string,
+	results []parser.Result,
+	report *typed.Report,
+	adapters ...handlers.Adapter,
+) []handlers.Handler {
+	parsed := make(map[string]parser.Handler)
+	for _, res := range results {
+		for _, h := range res.Handlers {
+			parsed[h.Key()] = h
+		}
+	}
+
+	res := make([]handlers.Handler, 0)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.PkgPath != routesPkg {
+			return
+		}
+
+		for _, route := range routes.Extract(pkg, routes.WithAdapters(adapters...)) {
+			h, ok := parsed[route.Key()]
+			if !ok {
+				logging.Warn("matched handler not found, skipping", logging.Pkg(route.Handler.Pkg().Path()), logging.Handler(route.Handler.Name()))
+				if report != nil {
+					report.Routes = append(report.Routes, typed.RouteReport{
+						Method:  route.Method,
+						Path:    handlers.OpenAPIPath(route.Path),
+						Handler: route.FullName(),
+						Status:  typed.RouteUnmatched,
+					})
+				}
+				continue
+			}
+
+			echoRoute := echo.Route{
+				Method: route.Method,
+				Path:   route.Path,
+				Name:   route.FullName(),
+			}
+			res = append(res, handlers.NewHandler(echoRoute, nil, h))
+		}
+	})
+
+	return res
+}
+
+func (g *Generator) adapters() []handlers.Adapter {
+	res := make([]handlers.Adapter, 0, len(g.cfg.Input.Adapters))
+	for _, a := range g.cfg.Input.Adapters {
+		res = append(res, handlers.Adapter{Func: a.Func, HandlerArg: a.HandlerArg})
+	}
+	return res
+}
+
+func (g *Generator) adapterFuncs() []string {
+	res := make([]string, 0, len(g.cfg.Input.Adapters))
+	for _, a := range g.cfg.Input.Adapters {
+		res = append(res, a.Func)
+	}
+	return res
+}
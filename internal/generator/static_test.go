@@ -0,0 +1,296 @@
+package generator
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateStatic(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "static")
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+
+	g, err := New(Config{
+		Input: InputConfig{
+			Metadata:          Metadata{Title: "static", Version: "1.0.0"},
+			RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/static",
+			Handlers:          []HandlersConfig{{Path: fixture}},
+			Models:            []ModelsConfig{{Path: fixture}},
+		},
+		Output: OutputConfig{
+			Path:     filepath.Join(t.TempDir(), "spec.go"),
+			SpecPath: specPath,
+		},
+		Cache: CacheConfig{Dir: t.TempDir()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, g.GenerateStatic())
+
+	spec, err := openapi3.NewLoader().LoadFromFile(specPath)
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate(t.Context()))
+	require.NoError(t, spec.Validate(t.Context()))
+	require.Equal(t, "static", spec.Info.Title)
+
+	getUser := spec.Paths.Find("/api/v1/users/{id}").GetOperation(http.MethodGet)
+	require.NotNil(t, getUser)
+	require.Equal(t, "GetUser", getUser.OperationID)
+	require.Equal(t, "GetUser returns user by id", getUser.Description)
+	require.Equal(t, "#/components/schemas/static.User", getUser.Responses.Status(http.StatusOK).Value.Content.Get("application/json").Schema.Ref)
+	require.NotNil(t, getUser.Parameters.GetByInAndName(openapi3.ParameterInPath, "id"))
+
+	createUser := spec.Paths.Find("/api/v1/users").GetOperation(http.MethodPost)
+	require.NotNil(t, createUser)
+	require.Equal(t, "#/components/schemas/static.CreateUserRequest", createUser.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+	require.NotNil(t, createUser.Parameters.GetByInAndName(openapi3.ParameterInHeader, "X-Trace-Id"))
+
+	deleteUser := spec.Paths.Find("/api/v1/users/{id}").GetOperation(http.MethodDelete)
+	require.NotNil(t, deleteUser)
+	require.NotNil(t, deleteUser.Responses.Status(http.StatusNoContent))
+
+	require.NotNil(t, spec.Paths.Find("/api/v1/health").GetOperation(http.MethodGet))
+	require.NotNil(t, spec.Paths.Find("/version").GetOperation(http.MethodGet))
+
+	user := spec.Components.Schemas["static.User"].Value
+	require.ElementsMatch(t, []string{"id", "name", "role", "created_at"}, user.Required)
+	require.Equal(t, []any{"admin", "user"}, user.Properties["role"].Value.Enum)
+	require.Equal(t, "date-time", user.Properties["created_at"].Value.Format)
+	require.Equal(t, "#/components/schemas/static.User", user.Properties["manager"].Ref)
+	require.Equal(t, "#/components/schemas/static.Address", user.Properties["address"].Ref)
+	require.True(t, user.Properties["tags"].Value.Type.Is(openapi3.TypeArray))
+
+	req := spec.Components.Schemas["static.CreateUserRequest"].Value
+	require.NotContains(t, req.Properties, "TraceID", "header fields are not a part of body")
+	require.Contains(t, req.Properties, "source", "embedded struct is flattened by default")
+	require.ElementsMatch(t, []string{"source", "name", "role"}, req.Required)
+}
+
+func TestGenerator_GenerateStaticMethodsWithSameName(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "parser/receivers")
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+
+	g, err := New(Config{
+		Input: InputConfig{
+			Metadata:          Metadata{Title: "receivers", Version: "1.0.0"},
+			RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/parser/receivers",
+			Handlers:          []HandlersConfig{{Path: fixture}},
+		},
+		Output: OutputConfig{
+			Path:     filepath.Join(t.TempDir(), "spec.go"),
+			SpecPath: specPath,
+		},
+		Cache: CacheConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, g.GenerateStatic())
+
+	spec, err := openapi3.NewLoader().LoadFromFile(specPath)
+	require.NoError(t, err)
+
+	users := spec.Paths.Find("/users").GetOperation(http.MethodGet)
+	require.NotNil(t, users)
+	require.Equal(t, "List returns users", users.Description)
+	require.Equal(t, "#/components/schemas/receivers.User", users.Responses.Status(http.StatusOK).Value.Content.Get("application/json").Schema.Value.Items.Ref)
+
+	orders := spec.Paths.Find("/orders").GetOperation(http.MethodGet)
+	require.NotNil(t, orders)
+	require.Equal(t, "List returns orders", orders.Description)
+	require.Equal(t, "#/components/schemas/receivers.Order", orders.Responses.Status(http.StatusOK).Value.Content.Get("application/json").Schema.Value.Items.Ref)
+}
+
+func TestGenerator_GenerateStaticEmbeddedAllOf(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "static")
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+
+	g, err := New(Config{
+		Input: InputConfig{
+			Metadata:          Metadata{Title: "static", Version: "1.0.0"},
+			RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/static",
+			Handlers:          []HandlersConfig{{Path: fixture}},
+		},
+		Output: OutputConfig{
+			Path:          filepath.Join(t.TempDir(), "spec.go"),
+			SpecPath:      specPath,
+			EmbeddedAllOf: true,
+		},
+		Cache: CacheConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, g.GenerateStatic())
+
+	spec, err := openapi3.NewLoader().LoadFromFile(specPath)
+	require.NoError(t, err)
+
+	meta := spec.Components.Schemas["static.RequestMeta"]
+	require.NotNil(t, meta, "embedded struct must be exported as component")
+	require.Equal(t, []string{"source"}, meta.Value.Required)
+
+	req := spec.Components.Schemas["static.CreateUserRequest"].Value
+	require.Empty(t, req.Properties)
+	require.Len(t, req.AllOf, 2)
+	require.Equal(t, "#/components/schemas/static.RequestMeta", req.AllOf[0].Ref)
+	require.ElementsMatch(t, []string{"name", "role"}, slices.Collect(maps.Keys(req.AllOf[1].Value.Properties)))
+	require.ElementsMatch(t, []string{"name", "role"}, req.AllOf[1].Value.Required)
+}
+
+func TestGenerator_GenerateStaticSpecs(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "static")
+	dir := t.TempDir()
+
+	g, err := New(Config{
+		Input: InputConfig{
+			Metadata:          Metadata{Title: "static", Version: "1.0.0"},
+			RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/static",
+			Handlers:          []HandlersConfig{{Path: fixture}},
+			Models:            []ModelsConfig{{Path: fixture}},
+		},
+		Output: OutputConfig{
+			Path: filepath.Join(dir, "spec.go"),
+			Specs: []SpecConfig{
+				{
+					Name:     "users",
+					SpecPath: filepath.Join(dir, "users.json"),
+					Metadata: Metadata{Title: "users"},
+					Routes: RoutesConfig{
+						Include: []RouteFilter{{PathPrefix: "/api/v1/users"}},
+						Exclude: []RouteFilter{{Methods: []string{http.MethodDelete}}},
+					},
+				},
+				{
+					Name:     "service",
+					SpecPath: filepath.Join(dir, "service.json"),
+					Routes: RoutesConfig{
+						Exclude: []RouteFilter{{PathPrefix: "/api/v1/users"}},
+					},
+				},
+			},
+		},
+		Cache: CacheConfig{Dir: t.TempDir()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, g.GenerateStatic())
+
+	users, err := openapi3.NewLoader().LoadFromFile(filepath.Join(dir, "users.json"))
+	require.NoError(t, err)
+	require.NoError(t, users.Validate(t.Context()))
+	require.Equal(t, "users", users.Info.Title)
+	require.Equal(t, "1.0.0", users.Info.Version)
+	require.NotNil(t, users.Paths.Find("/api/v1/users/{id}").GetOperation(http.MethodGet))
+	require.Nil(t, users.Paths.Find("/api/v1/users/{id}").GetOperation(http.MethodDelete))
+	require.NotNil(t, users.Paths.Find("/api/v1/users").GetOperation(http.MethodPost))
+	require.Nil(t, users.Paths.Find("/version"))
+	require.ElementsMatch(t, []string{"static.User", "static.Address", "static.CreateUserRequest"}, slices.Collect(maps.Keys(users.Components.Schemas)))
+
+	service, err := openapi3.NewLoader().LoadFromFile(filepath.Join(dir, "service.json"))
+	require.NoError(t, err)
+	require.NoError(t, service.Validate(t.Context()))
+	require.Equal(t, "static", service.Info.Title)
+	require.Equal(t, 2, service.Paths.Len())
+	require.NotNil(t, service.Paths.Find("/api/v1/health"))
+	require.NotNil(t, service.Paths.Find("/version"))
+	require.Empty(t, service.Components.Schemas)
+}
+
+func TestGenerator_GenerateStaticPrunesComponents(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "static")
+	tests := []struct {
+		name       string
+		components ComponentsConfig
+		want       []string
+	}{
+		{
+			name: "unreachable schemas are pruned",
+			want: []string{"static.User", "static.Address", "static.CreateUserRequest"},
+		},
+		{
+			name:       "always include",
+			components: ComponentsConfig{AlwaysInclude: []string{`^dto\.Form$`}},
+			want:       []string{"static.User", "static.Address", "static.CreateUserRequest", "dto.Form"},
+		},
+		{
+			name:       "keep unused",
+			components: ComponentsConfig{KeepUnused: true},
+			want:       []string{"static.User", "static.Address", "static.CreateUserRequest", "static.RequestMeta", "dto.User", "dto.Form", "dto.FormUploadResp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			g, err := New(Config{
+				Input: InputConfig{
+					Metadata:          Metadata{Title: "static", Version: "1.0.0"},
+					RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/static",
+					Handlers:          []HandlersConfig{{Path: fixture}},
+					Models:            []ModelsConfig{{Path: fixture}, {Path: testsuite.FixturePath(t, "dto")}},
+				},
+				Output: OutputConfig{
+					Path:       filepath.Join(dir, "spec.go"),
+					SpecPath:   filepath.Join(dir, "openapi.json"),
+					Components: tt.components,
+				},
+				Cache: CacheConfig{Dir: t.TempDir()},
+			})
+			require.NoError(t, err)
+			require.NoError(t, g.GenerateStatic())
+
+			spec, err := openapi3.NewLoader().LoadFromFile(filepath.Join(dir, "openapi.json"))
+			require.NoError(t, err)
+			require.NoError(t, spec.Validate(t.Context()))
+			require.ElementsMatch(t, tt.want, slices.Collect(maps.Keys(spec.Components.Schemas)))
+		})
+	}
+}
+
+func TestGenerator_GenerateStaticReport(t *testing.T) {
+	fixture := testsuite.FixturePath(t, "static")
+	dir := t.TempDir()
+	cfg := Config{
+		Input: InputConfig{
+			Metadata:          Metadata{Title: "static", Version: "1.0.0"},
+			RoutesProviderPkg: "github.com/d1vbyz3r0/typed/testdata/static",
+			Handlers:          []HandlersConfig{{Path: fixture}},
+			Models:            []ModelsConfig{{Path: fixture}},
+			Routes:            RoutesConfig{Exclude: []RouteFilter{{Path: "^/version$"}}},
+		},
+		Output: OutputConfig{
+			Path:     filepath.Join(dir, "spec.go"),
+			SpecPath: filepath.Join(dir, "openapi.json"),
+			Report:   ReportConfig{Path: filepath.Join(dir, "report.json"), MinCoverage: 100},
+		},
+		Cache: CacheConfig{Dir: t.TempDir()},
+	}
+
+	g, err := New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, g.GenerateStatic())
+
+	data, err := os.ReadFile(cfg.Output.Report.Path)
+	require.NoError(t, err)
+
+	var report typed.Report
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Equal(t, typed.ReportSummary{
+		Routes:              5,
+		Matched:             4,
+		Excluded:            1,
+		Documented:          4,
+		DefaultedParameters: 2,
+		Coverage:            100,
+	}, report.Summary)
+	require.Contains(t, report.Routes, typed.RouteReport{
+		Method:  http.MethodGet,
+		Path:    "/version",
+		Handler: "github.com/d1vbyz3r0/typed/testdata/static.Version",
+		Status:  typed.RouteExcluded,
+	})
+}
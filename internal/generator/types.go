@@ -24,6 +24,11 @@ func collectTypes(results []parser.Result) ([]*typing.Type, error) {
 				_types[k] = t
 			}
 
+			// same for interfaces, which are found unresolved in handlers and models, and resolved with implementations later
+			if t.Kind() == typing.TypeKindInterface && len(t.Implementations()) > len(prev.Implementations()) {
+				_types[k] = t
+			}
+
 			return
 		}
 
@@ -41,13 +46,11 @@ func collectTypes(results []parser.Result) ([]*typing.Type, error) {
 				}
 			}
 
-			for _, responses := range h.Responses {
-				for _, resp := range responses {
-					processType(resp.ModelType)
-					err := typing.Traverse(resp.ModelType, processType)
-					if err != nil {
-						return nil, err
-					}
+			for _, model := range h.Models() {
+				processType(model)
+				err := typing.Traverse(model, processType)
+				if err != nil {
+					return nil, err
 				}
 			}
 		}
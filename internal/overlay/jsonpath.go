@@ -0,0 +1,408 @@
+package overlay
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// node is a value, selected by JSONPath expression
+type node struct {
+	value any
+	// path is a normalized JSONPath of value
+	path string
+	// set replaces value in its parent. It's nil for root
+	set func(v any)
+}
+
+// selector selects child nodes of node
+type selector func(n node) []node
+
+// compile parses JSONPath expression. Supported subset: root "$", child names ".name", "['name']" and unions
+// "['a','b']", wildcards ".*" and "[*]", array indexes "[0]" and "[-1]", recursive descent "..name" and "..*",
+// filters "[?(@.name == 'value')]" with == and != operators and existence filters "[?(@.name)]".
+func compile(expr string) ([]selector, error) {
+	p := &pathParser{expr: expr}
+	if !p.consume("$") {
+		return nil, fmt.Errorf("jsonpath %q: must start with $", expr)
+	}
+
+	var res []selector
+	for !p.done() {
+		switch {
+		case p.consume(".."):
+			sel, err := p.segment()
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", expr, err)
+			}
+			res = append(res, descendants(sel))
+
+		case p.peek('.') || p.peek('['):
+			sel, err := p.segment()
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", expr, err)
+			}
+			res = append(res, sel)
+
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q at %d", expr, p.expr[p.pos], p.pos)
+		}
+	}
+
+	return res, nil
+}
+
+// query selects nodes of root, matching compiled expression
+func query(root node, selectors []selector) []node {
+	nodes := []node{root}
+	for _, sel := range selectors {
+		var next []node
+		for _, n := range nodes {
+			next = append(next, sel(n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) done() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *pathParser) peek(c byte) bool {
+	return !p.done() && p.expr[p.pos] == c
+}
+
+func (p *pathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) skipSpaces() {
+	for p.peek(' ') {
+		p.pos++
+	}
+}
+
+// segment parses ".name", ".*" or bracket segment. Leading dot is optional after recursive descent
+func (p *pathParser) segment() (selector, error) {
+	if p.consume("[") {
+		return p.bracket()
+	}
+
+	p.consume(".")
+	if p.consume("*") {
+		return wildcard, nil
+	}
+
+	start := p.pos
+	for !p.done() && !p.peek('.') && !p.peek('[') {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("empty name at %d", start)
+	}
+	return children(p.expr[start:p.pos]), nil
+}
+
+func (p *pathParser) bracket() (selector, error) {
+	p.skipSpaces()
+	switch {
+	case p.consume("*"):
+		return wildcard, p.closeBracket()
+
+	case p.consume("?"):
+		return p.filter()
+
+	case p.peek('\'') || p.peek('"'):
+		var names []string
+		for {
+			p.skipSpaces()
+			name, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+			p.skipSpaces()
+			if !p.consume(",") {
+				break
+			}
+		}
+		return children(names...), p.closeBracket()
+
+	default:
+		start := p.pos
+		for !p.done() && !p.peek(']') {
+			p.pos++
+		}
+		idx, err := strconv.Atoi(strings.TrimSpace(p.expr[start:p.pos]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index at %d: %w", start, err)
+		}
+		return index(idx), p.closeBracket()
+	}
+}
+
+func (p *pathParser) closeBracket() error {
+	p.skipSpaces()
+	if !p.consume("]") {
+		return fmt.Errorf("expected ] at %d", p.pos)
+	}
+	return nil
+}
+
+func (p *pathParser) quoted() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("expected quoted name at %d", p.pos)
+	}
+
+	quote := p.expr[p.pos]
+	if quote != '\'' && quote != '"' {
+		return "", fmt.Errorf("expected quoted name at %d", p.pos)
+	}
+	p.pos++
+
+	var b strings.Builder
+	for !p.done() {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && !p.done():
+			b.WriteByte(p.expr[p.pos])
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted name")
+}
+
+// filter parses "(@.name op value)" or "@.name op value" and closing bracket
+func (p *pathParser) filter() (selector, error) {
+	p.skipSpaces()
+	parens := p.consume("(")
+	p.skipSpaces()
+	if !p.consume("@") {
+		return nil, fmt.Errorf("expected @ at %d", p.pos)
+	}
+
+	var fields []string
+	for p.peek('.') || p.peek('[') {
+		if p.consume("[") {
+			p.skipSpaces()
+			name, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, name)
+			if err := p.closeBracket(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		p.consume(".")
+		start := p.pos
+		for !p.done() && !strings.ContainsRune(".[ =!)]", rune(p.expr[p.pos])) {
+			p.pos++
+		}
+		fields = append(fields, p.expr[start:p.pos])
+	}
+
+	p.skipSpaces()
+	var (
+		op    string
+		value any
+	)
+	switch {
+	case p.consume("=="):
+		op = "=="
+	case p.consume("!="):
+		op = "!="
+	}
+
+	if op != "" {
+		p.skipSpaces()
+		var err error
+		value, err = p.literal()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.skipSpaces()
+	if parens && !p.consume(")") {
+		return nil, fmt.Errorf("expected ) at %d", p.pos)
+	}
+	if err := p.closeBracket(); err != nil {
+		return nil, err
+	}
+
+	return filtered(fields, op, value), nil
+}
+
+func (p *pathParser) literal() (any, error) {
+	if p.peek('\'') || p.peek('"') {
+		return p.quoted()
+	}
+
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(" )]", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+
+	raw := p.expr[start:p.pos]
+	switch raw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal %q at %d", raw, start)
+	}
+	return f, nil
+}
+
+func children(names ...string) selector {
+	return func(n node) []node {
+		obj, ok := n.value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		var res []node
+		for _, name := range names {
+			if v, ok := obj[name]; ok {
+				res = append(res, child(obj, name, v, n.path))
+			}
+		}
+		return res
+	}
+}
+
+func wildcard(n node) []node {
+	switch v := n.value.(type) {
+	case map[string]any:
+		res := make([]node, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			res = append(res, child(v, k, v[k], n.path))
+		}
+		return res
+
+	case []any:
+		res := make([]node, 0, len(v))
+		for i := range v {
+			res = append(res, element(v, i, n.path))
+		}
+		return res
+
+	default:
+		return nil
+	}
+}
+
+func index(idx int) selector {
+	return func(n node) []node {
+		arr, ok := n.value.([]any)
+		if !ok {
+			return nil
+		}
+
+		i := idx
+		if i < 0 {
+			i += len(arr)
+		}
+		if i < 0 || i >= len(arr) {
+			return nil
+		}
+		return []node{element(arr, i, n.path)}
+	}
+}
+
+// descendants applies selector to node and all its descendants
+func descendants(sel selector) selector {
+	return func(n node) []node {
+		res := sel(n)
+		for _, c := range wildcard(n) {
+			res = append(res, descendants(sel)(c)...)
+		}
+		return res
+	}
+}
+
+func filtered(fields []string, op string, value any) selector {
+	return func(n node) []node {
+		var res []node
+		for _, c := range wildcard(n) {
+			v, ok := lookup(c.value, fields)
+			switch {
+			case op == "" && ok,
+				op == "==" && ok && equal(v, value),
+				op == "!=" && (!ok || !equal(v, value)):
+				res = append(res, c)
+			}
+		}
+		return res
+	}
+}
+
+func lookup(v any, fields []string) (any, bool) {
+	for _, f := range fields {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok = obj[f]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func child(obj map[string]any, key string, v any, parentPath string) node {
+	return node{
+		value: v,
+		path:  parentPath + formatKey(key),
+		set: func(v any) {
+			obj[key] = v
+		},
+	}
+}
+
+func element(arr []any, i int, parentPath string) node {
+	return node{
+		value: arr[i],
+		path:  fmt.Sprintf("%s[%d]", parentPath, i),
+		set: func(v any) {
+			arr[i] = v
+		},
+	}
+}
+
+// formatKey formats key as JSONPath segment
+func formatKey(key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) == -1 {
+		return "." + key
+	}
+	return "['" + strings.ReplaceAll(key, "'", "\\'") + "']"
+}
@@ -0,0 +1,76 @@
+package overlay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `{
+	"paths": {
+		"/users": {
+			"get": {"operationId": "ListUsers", "tags": ["users"]},
+			"post": {"operationId": "CreateUser", "tags": ["users", "admin"]}
+		},
+		"/health": {
+			"get": {"operationId": "Health", "deprecated": true}
+		}
+	},
+	"servers": [{"url": "http://a"}, {"url": "http://b"}]
+}`
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "$", want: []string{"$"}},
+		{expr: "$.paths['/users'].get", want: []string{"$.paths['/users'].get"}},
+		{expr: `$.paths["/users"]["get","post"].operationId`, want: []string{"$.paths['/users'].get.operationId", "$.paths['/users'].post.operationId"}},
+		{expr: "$.paths.*.get", want: []string{"$.paths['/health'].get", "$.paths['/users'].get"}},
+		{expr: "$.paths[*].post", want: []string{"$.paths['/users'].post"}},
+		{expr: "$.servers[0]", want: []string{"$.servers[0]"}},
+		{expr: "$.servers[-1].url", want: []string{"$.servers[1].url"}},
+		{expr: "$.servers[5]", want: nil},
+		{expr: "$..operationId", want: []string{"$.paths['/health'].get.operationId", "$.paths['/users'].get.operationId", "$.paths['/users'].post.operationId"}},
+		{expr: "$.paths.*[?(@.operationId == 'CreateUser')]", want: []string{"$.paths['/users'].post"}},
+		{expr: "$.paths.*[?@.operationId!='CreateUser']", want: []string{"$.paths['/health'].get", "$.paths['/users'].get"}},
+		{expr: "$.paths.*[?(@.deprecated == true)]", want: []string{"$.paths['/health'].get"}},
+		{expr: "$.paths.*[?(@.deprecated)]", want: []string{"$.paths['/health'].get"}},
+		{expr: "$.servers[?(@.url == 'http://b')]", want: []string{"$.servers[1]"}},
+		{expr: "$.missing.field", want: nil},
+	}
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(testDoc), &doc))
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			selectors, err := compile(tt.expr)
+			require.NoError(t, err)
+
+			var got []string
+			for _, n := range query(node{value: doc, path: "$"}, selectors) {
+				got = append(got, n.path)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"paths",
+		"$.",
+		"$.paths['/users'",
+		"$.servers[x]",
+		"$.paths[?(@.a == )]",
+		"$.paths[?(@.a == 'b']",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := compile(expr)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Package overlay applies hand-written fragments to generated OpenAPI documents. Fragments are either
+// OpenAPI Overlay 1.0 documents with JSONPath targets or partial OpenAPI documents, deep-merged into the root.
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action is an action of Overlay document. Exactly one of Update and Remove is applied to all target nodes
+type Action struct {
+	Target      string `json:"target" yaml:"target"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Update      any    `json:"update,omitempty" yaml:"update,omitempty"`
+	Remove      bool   `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// Overlay is a loaded overlay file. Partial OpenAPI document is represented as single update of root
+type Overlay struct {
+	// Path is a path of overlay file
+	Path    string
+	Actions []Action
+}
+
+// Conflict is a value of document, replaced by overlay with a different value
+type Conflict struct {
+	// Overlay is a path of overlay file
+	Overlay string `json:"overlay"`
+	// Path is a JSONPath of replaced value
+	Path string `json:"path"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s: %v replaced with %v", c.Overlay, c.Path, c.Old, c.New)
+}
+
+// Load reads YAML or JSON overlay file. File with top-level "overlay" field is parsed as Overlay 1.0 document,
+// other files are parsed as partial OpenAPI documents
+func Load(path string) (Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Overlay{}, fmt.Errorf("read overlay: %w", err)
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Overlay{}, fmt.Errorf("unmarshal overlay %s: %w", path, err)
+	}
+
+	doc, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return Overlay{}, fmt.Errorf("overlay %s: document must be an object", path)
+	}
+
+	if _, ok := doc["overlay"]; !ok {
+		return Overlay{Path: path, Actions: []Action{{Target: "$", Update: doc}}}, nil
+	}
+
+	var parsed struct {
+		Actions []Action `json:"actions"`
+	}
+	if err := remarshal(doc, &parsed); err != nil {
+		return Overlay{}, fmt.Errorf("parse overlay %s: %w", path, err)
+	}
+
+	for i, action := range parsed.Actions {
+		if action.Target == "" {
+			return Overlay{}, fmt.Errorf("overlay %s: action %d: target is required", path, i)
+		}
+		if action.Update == nil && !action.Remove {
+			return Overlay{}, fmt.Errorf("overlay %s: action %d: update or remove is required", path, i)
+		}
+	}
+
+	return Overlay{Path: path, Actions: parsed.Actions}, nil
+}
+
+// Apply applies actions of overlays to doc in order: overlays are applied one by one and actions of each overlay
+// in order of declaration, so later actions see results of previous ones. Doc is modified in place.
+// Replaced values are returned as conflicts. Actions, which targets match nothing, are returned as unmatched
+func Apply(doc map[string]any, overlays ...Overlay) (conflicts []Conflict, unmatched []string, err error) {
+	root := node{value: doc, path: "$"}
+	for _, o := range overlays {
+		for i, action := range o.Actions {
+			selectors, err := compile(action.Target)
+			if err != nil {
+				return nil, nil, fmt.Errorf("overlay %s: action %d: %w", o.Path, i, err)
+			}
+
+			nodes := query(root, selectors)
+			if len(nodes) == 0 {
+				unmatched = append(unmatched, fmt.Sprintf("%s: %s", o.Path, action.Target))
+				continue
+			}
+
+			if action.Remove {
+				if err := remove(nodes); err != nil {
+					return nil, nil, fmt.Errorf("overlay %s: action %d: %w", o.Path, i, err)
+				}
+				compact(doc)
+				continue
+			}
+
+			m := &merger{overlay: o.Path}
+			for _, n := range nodes {
+				m.update(n, normalize(action.Update))
+			}
+			conflicts = append(conflicts, m.conflicts...)
+		}
+	}
+
+	return conflicts, unmatched, nil
+}
+
+type merger struct {
+	overlay   string
+	conflicts []Conflict
+}
+
+// update merges value into node: objects are merged recursively, value is appended to arrays,
+// other values are replaced
+func (m *merger) update(n node, value any) {
+	switch target := n.value.(type) {
+	case map[string]any:
+		if obj, ok := value.(map[string]any); ok {
+			m.merge(target, obj, n.path)
+			return
+		}
+
+	case []any:
+		if n.set != nil {
+			n.set(append(target, clone(value)))
+			return
+		}
+	}
+
+	m.replace(n, value)
+}
+
+func (m *merger) merge(dst, src map[string]any, path string) {
+	for _, k := range slices.Sorted(maps.Keys(src)) {
+		n := child(dst, k, dst[k], path)
+		existing, ok := dst[k]
+		if !ok {
+			n.set(clone(src[k]))
+			continue
+		}
+
+		dstObj, dstIsObj := existing.(map[string]any)
+		srcObj, srcIsObj := src[k].(map[string]any)
+		if dstIsObj && srcIsObj {
+			m.merge(dstObj, srcObj, n.path)
+			continue
+		}
+
+		m.replace(n, src[k])
+	}
+}
+
+// replace sets value of node. Replacement of non-empty different value is reported as conflict
+func (m *merger) replace(n node, value any) {
+	if equal(n.value, value) {
+		return
+	}
+
+	if !isEmpty(n.value) {
+		m.conflicts = append(m.conflicts, Conflict{Overlay: m.overlay, Path: n.path, Old: n.value, New: value})
+	}
+
+	if n.set != nil {
+		n.set(clone(value))
+	}
+}
+
+// removed marks values of selected nodes, which are deleted by compact
+type removedMarker struct{}
+
+var removed = &removedMarker{}
+
+func remove(nodes []node) error {
+	for _, n := range nodes {
+		if n.set == nil {
+			return fmt.Errorf("root can't be removed")
+		}
+		n.set(removed)
+	}
+	return nil
+}
+
+// compact deletes removed values from objects and arrays of v. Removed values are marked first,
+// so indexes of array elements don't change, while selected nodes are removed
+func compact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if child == removed {
+				delete(v, k)
+				continue
+			}
+			v[k] = compact(child)
+		}
+		return v
+
+	case []any:
+		res := v[:0]
+		for _, child := range v {
+			if child != removed {
+				res = append(res, compact(child))
+			}
+		}
+		return res
+
+	default:
+		return v
+	}
+}
+
+// normalize converts YAML values to JSON ones: maps keys to strings and numbers to float64,
+// so values are compared with values of marshaled document
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, child := range v {
+			res[k] = normalize(child)
+		}
+		return res
+
+	case map[any]any:
+		res := make(map[string]any, len(v))
+		for k, child := range v {
+			res[fmt.Sprint(k)] = normalize(child)
+		}
+		return res
+
+	case []any:
+		res := make([]any, len(v))
+		for i, child := range v {
+			res[i] = normalize(child)
+		}
+		return res
+
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+
+	default:
+		return v
+	}
+}
+
+// clone copies objects and arrays, so document doesn't share values with overlay, when update is applied
+// to several targets
+func clone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, child := range v {
+			res[k] = clone(child)
+		}
+		return res
+
+	case []any:
+		res := make([]any, len(v))
+		for i, child := range v {
+			res[i] = clone(child)
+		}
+		return res
+
+	default:
+		return v
+	}
+}
+
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func remarshal(src any, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
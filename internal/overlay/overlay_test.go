@@ -0,0 +1,141 @@
+package overlay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeOverlay(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []Action
+		wantErr string
+	}{
+		{
+			name: "overlay",
+			file: "overlay.yaml",
+			content: `
+overlay: 1.0.0
+info: {title: docs, version: 1.0.0}
+actions:
+  - target: $.paths['/users'].get
+    update:
+      description: Lists users
+  - target: $.paths['/health']
+    remove: true
+`,
+			want: []Action{
+				{Target: "$.paths['/users'].get", Update: map[string]any{"description": "Lists users"}},
+				{Target: "$.paths['/health']", Remove: true},
+			},
+		},
+		{
+			name:    "partial document",
+			file:    "partial.json",
+			content: `{"servers": [{"url": "https://api.example.com"}], "x-limit": 10}`,
+			want: []Action{
+				{Target: "$", Update: map[string]any{
+					"servers": []any{map[string]any{"url": "https://api.example.com"}},
+					"x-limit": float64(10),
+				}},
+			},
+		},
+		{
+			name:    "missing target",
+			file:    "overlay.yaml",
+			content: "overlay: 1.0.0\nactions:\n  - update: {}\n",
+			wantErr: "target is required",
+		},
+		{
+			name:    "missing update",
+			file:    "overlay.yaml",
+			content: "overlay: 1.0.0\nactions:\n  - target: $.info\n",
+			wantErr: "update or remove is required",
+		},
+		{
+			name:    "not an object",
+			file:    "overlay.yaml",
+			content: "- a\n- b\n",
+			wantErr: "document must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeOverlay(t, tt.file, tt.content)
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, path, got.Path)
+			require.Equal(t, tt.want, got.Actions)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(testDoc), &doc))
+
+	docs := Overlay{Path: "docs.yaml", Actions: []Action{
+		{Target: "$.paths.*.get", Update: map[string]any{"description": "read"}},
+		{Target: "$.paths['/users'].post", Update: map[string]any{"operationId": "AddUser", "tags": []any{"admin"}}},
+		{Target: "$.servers", Update: map[string]any{"url": "http://c"}},
+		{Target: "$.servers[?(@.url == 'http://a')]", Remove: true},
+		{Target: "$.paths['/missing']", Update: map[string]any{"get": map[string]any{}}},
+	}}
+	partial := Overlay{Path: "partial.yaml", Actions: []Action{
+		{Target: "$", Update: map[string]any{
+			"paths": map[string]any{
+				"/health": map[string]any{"get": map[string]any{"description": "liveness"}},
+			},
+			"webhooks": map[string]any{"userCreated": map[string]any{}},
+		}},
+	}}
+
+	conflicts, unmatched, err := Apply(doc, docs, partial)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docs.yaml: $.paths['/missing']"}, unmatched)
+	require.Equal(t, []Conflict{
+		{Overlay: "docs.yaml", Path: "$.paths['/users'].post.operationId", Old: "CreateUser", New: "AddUser"},
+		{Overlay: "docs.yaml", Path: "$.paths['/users'].post.tags", Old: []any{"users", "admin"}, New: []any{"admin"}},
+		{Overlay: "partial.yaml", Path: "$.paths['/health'].get.description", Old: "read", New: "liveness"},
+	}, conflicts)
+
+	var want map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"paths": {
+			"/users": {
+				"get": {"operationId": "ListUsers", "tags": ["users"], "description": "read"},
+				"post": {"operationId": "AddUser", "tags": ["admin"]}
+			},
+			"/health": {
+				"get": {"operationId": "Health", "deprecated": true, "description": "liveness"}
+			}
+		},
+		"servers": [{"url": "http://b"}, {"url": "http://c"}],
+		"webhooks": {"userCreated": {}}
+	}`), &want))
+	require.Equal(t, want, doc)
+}
+
+func TestApply_RemoveRoot(t *testing.T) {
+	_, _, err := Apply(map[string]any{}, Overlay{Path: "o.yaml", Actions: []Action{{Target: "$", Remove: true}}})
+	require.ErrorContains(t, err, "root can't be removed")
+}
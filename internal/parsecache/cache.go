@@ -0,0 +1,135 @@
+// Package parsecache stores parsing results on disk, keyed by content hash of packages,
+// so unchanged packages are not loaded with types and parsed on every run.
+package parsecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+// formatVersion is a version of cached entries format. It's a part of every key, so entries of other formats are ignored
+const formatVersion = "5"
+
+// Cache is a directory with JSON encoded entries. Nil *Cache is a disabled cache: entries are never found and not saved
+type Cache struct {
+	dir string
+}
+
+// DefaultDir returns typed directory in user cache directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("get user cache dir: %w", err)
+	}
+	return filepath.Join(dir, "typed"), nil
+}
+
+// New creates cache in provided directory. If dir is empty, DefaultDir is used
+func New(dir string) (*Cache, error) {
+	if dir == "" {
+		var err error
+		dir, err = DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+
+	return &Cache{dir: dir}, nil
+}
+
+// Dir returns cache directory
+func (c *Cache) Dir() string {
+	if c == nil {
+		return ""
+	}
+	return c.dir
+}
+
+// Get decodes entry with provided key into v and reports if it was found.
+// Broken entries are treated as missing ones
+func (c *Cache) Get(key string, v any) bool {
+	if c == nil {
+		return false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logging.Warn("failed to read cache entry", "key", key, "error", err)
+		}
+		return false
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		logging.Warn("failed to decode cache entry, ignoring it", "key", key, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// Put encodes v and saves it with provided key. Entry is written to temporary file first,
+// so concurrent readers never see partially written entries
+func (c *Cache) Put(key string, v any) error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode entry: %w", err)
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create entry dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// Key returns hex encoded sha256 of provided parts and cache format version
+func Key(parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte(formatVersion))
+	for _, part := range parts {
+		// parts are separated with zero byte, so ("ab", "c") and ("a", "bc") have different keys
+		h.Write([]byte{0})
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,42 @@
+package parsecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_GetPut(t *testing.T) {
+	c, err := New(t.TempDir())
+	require.NoError(t, err)
+
+	type entry struct {
+		Name string
+	}
+
+	key := Key("pkg", "content")
+	var got entry
+	require.False(t, c.Get(key, &got))
+
+	require.NoError(t, c.Put(key, entry{Name: "cached"}))
+	require.True(t, c.Get(key, &got))
+	require.Equal(t, entry{Name: "cached"}, got)
+
+	require.NoError(t, os.WriteFile(filepath.Join(c.Dir(), key[:2], key+".json"), []byte("{"), 0o644))
+	require.False(t, c.Get(key, &got), "broken entry is a miss")
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+
+	require.NoError(t, c.Put("key", 1))
+	var v int
+	require.False(t, c.Get("key", &v))
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, Key("a", "b"), Key("a", "b"))
+	require.NotEqual(t, Key("ab", "c"), Key("a", "bc"))
+}
@@ -0,0 +1,153 @@
+package parsecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const typedModule = "github.com/d1vbyz3r0/typed"
+
+var typedVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == typedModule {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != typedModule {
+			continue
+		}
+
+		if dep.Replace != nil {
+			return dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		return dep.Version
+	}
+
+	return "unknown"
+})
+
+// keyer computes content keys of packages. Key of package is built from its go files, go.sum of its module,
+// typed version and keys of imported packages. Packages of the main module and modules replaced with local
+// directories are hashed by content, other module packages by module version, standard library ones by path only
+type keyer struct {
+	salt   string
+	keys   map[string]string
+	gosums map[string]string
+}
+
+func newKeyer(salt string) *keyer {
+	return &keyer{
+		salt:   salt,
+		keys:   make(map[string]string),
+		gosums: make(map[string]string),
+	}
+}
+
+func (k *keyer) key(pkg *packages.Package) (string, error) {
+	if key, ok := k.keys[pkg.PkgPath]; ok {
+		return key, nil
+	}
+
+	parts := []string{k.salt, typedVersion(), pkg.PkgPath}
+	switch {
+	case pkg.Module == nil:
+		// standard library
+
+	case !isLocal(pkg.Module):
+		parts = append(parts, pkg.Module.Path+"@"+pkg.Module.Version)
+
+	default:
+		files := slices.Sorted(slices.Values(pkg.GoFiles))
+		for _, file := range files {
+			sum, err := hashFile(file)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, filepath.Base(file), sum)
+		}
+
+		gosum, err := k.gosum(pkg.Module)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, gosum)
+
+		imports := make([]*packages.Package, 0, len(pkg.Imports))
+		for _, imp := range pkg.Imports {
+			imports = append(imports, imp)
+		}
+		slices.SortFunc(imports, func(a, b *packages.Package) int {
+			return strings.Compare(a.PkgPath, b.PkgPath)
+		})
+
+		for _, imp := range imports {
+			impKey, err := k.key(imp)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, impKey)
+		}
+	}
+
+	key := Key(parts...)
+	k.keys[pkg.PkgPath] = key
+	return key, nil
+}
+
+// gosum returns hash of go.sum of module. Missing go.sum is hashed as empty one, since go command creates it on load
+func (k *keyer) gosum(mod *packages.Module) (string, error) {
+	if mod.GoMod == "" {
+		return "", nil
+	}
+
+	path := filepath.Join(filepath.Dir(mod.GoMod), "go.sum")
+	if sum, ok := k.gosums[path]; ok {
+		return sum, nil
+	}
+
+	sum, err := hashFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		empty := sha256.Sum256(nil)
+		sum, err = hex.EncodeToString(empty[:]), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	k.gosums[path] = sum
+	return sum, nil
+}
+
+func isLocal(mod *packages.Module) bool {
+	return mod.Main || mod.Replace != nil && mod.Replace.Version == ""
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,303 @@
+package parsecache
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/logging"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// lightLoadMode is enough to compute content keys of packages
+	lightLoadMode = packages.NeedName |
+		packages.NeedFiles |
+		packages.NeedImports |
+		packages.NeedDeps |
+		packages.NeedModule
+
+	fullLoadMode = packages.NeedTypes |
+		packages.NeedSyntax |
+		packages.NeedTypesInfo |
+		packages.NeedFiles |
+		packages.NeedName
+)
+
+// Loader loads and parses packages, reusing results of unchanged packages saved in cache.
+// Packages are loaded with types and syntax only if some of them are not cached
+type Loader struct {
+	parser      *parser.Parser
+	cache       *Cache
+	concurrency int
+}
+
+// NewLoader creates loader. If c is nil, packages are always loaded with types and parsed
+func NewLoader(p *parser.Parser, c *Cache, concurrency int) *Loader {
+	return &Loader{
+		parser:      p,
+		cache:       c,
+		concurrency: concurrency,
+	}
+}
+
+// ParseOpts returns parsing options, shared by generator and handlers finder, so they reuse cached results of each other.
+// adapters are full names of functions, adapting handlers with custom context
+func ParseOpts(adapters ...string) []parser.ParseOpt {
+	return []parser.ParseOpt{
+		parser.ParseAllModels(),
+		parser.ParseEnums(),
+		parser.ParseInlineForms(),
+		parser.ParseInlinePathParams(),
+		parser.ParseInlineQueryParams(),
+		parser.ParseInlineHeaders(),
+		parser.ParseAdaptedHandlers(adapters...),
+	}
+}
+
+// Loaded holds loaded packages and their parsing results
+type Loaded struct {
+	// Packages are packages matching patterns. They have types and syntax only if Typed is true, otherwise
+	// only names and files are loaded
+	Packages []*packages.Package
+	Typed    bool
+	// Results are parsing results in order of Packages
+	Results []parser.Result
+	// Parsed holds paths of packages, which results were not found in cache
+	Parsed []string
+}
+
+// Load loads packages, matching patterns, and parses them with provided options
+func (l *Loader) Load(patterns []string, opts ...parser.ParseOpt) (Loaded, error) {
+	return l.load(patterns, false, opts...)
+}
+
+// LoadTyped loads packages, matching patterns, with types and syntax, and parses them with provided options.
+// Parsing results are still reused from cache
+func (l *Loader) LoadTyped(patterns []string, opts ...parser.ParseOpt) (Loaded, error) {
+	return l.load(patterns, true, opts...)
+}
+
+// Plan holds packages, matching patterns, loaded without types, and their content keys. It allows to look up
+// results, derived from packages, in cache before they are loaded with types
+type Plan struct {
+	// Key is content key of all packages. It's empty, when loader has no cache
+	Key string
+
+	patterns []string
+	opts     []parser.ParseOpt
+	pkgs     []*packages.Package
+	keys     []string
+}
+
+// Plan loads packages, matching patterns, without types and computes their content keys with provided parsing options.
+// Nothing is loaded, when loader has no cache
+func (l *Loader) Plan(patterns []string, opts ...parser.ParseOpt) (*Plan, error) {
+	p := &Plan{
+		patterns: patterns,
+		opts:     opts,
+	}
+	if l.cache == nil {
+		return p, nil
+	}
+
+	pkgs, err := loadPackages(lightLoadMode, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	k := newKeyer(parser.OptsKey(opts...))
+	keys := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		keys[i], err = k.key(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("compute key of package %s: %w", pkg.PkgPath, err)
+		}
+	}
+
+	p.Key = Key(keys...)
+	p.pkgs = pkgs
+	p.keys = keys
+	return p, nil
+}
+
+// LoadTypedPlan loads planned packages with types and syntax and parses them, same as LoadTyped, but without
+// loading packages and computing their keys again
+func (l *Loader) LoadTypedPlan(p *Plan) (Loaded, error) {
+	return l.loadPlan(p, true)
+}
+
+func (l *Loader) load(patterns []string, typed bool, opts ...parser.ParseOpt) (Loaded, error) {
+	p, err := l.Plan(patterns, opts...)
+	if err != nil {
+		return Loaded{}, err
+	}
+	return l.loadPlan(p, typed)
+}
+
+func (l *Loader) loadPlan(p *Plan, typed bool) (Loaded, error) {
+	if l.cache == nil {
+		pkgs, err := loadPackages(fullLoadMode, p.patterns)
+		if err != nil {
+			return Loaded{}, err
+		}
+
+		results, err := l.parse(pkgs, p.opts)
+		if err != nil {
+			return Loaded{}, err
+		}
+		parsed := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			parsed = append(parsed, pkg.PkgPath)
+		}
+		return Loaded{Packages: pkgs, Typed: true, Results: results, Parsed: parsed}, nil
+	}
+
+	pkgs, keys := p.pkgs, p.keys
+	results := make([]parser.Result, len(pkgs))
+	var missed []int
+	for i := range pkgs {
+		if !l.cache.Get(keys[i], &results[i]) {
+			missed = append(missed, i)
+		}
+	}
+
+	logging.Debug("looked up parsing results in cache", "packages", len(pkgs), "missed", len(missed))
+	loaded := Loaded{
+		Packages: pkgs,
+		Results:  results,
+	}
+
+	if len(missed) == 0 && !typed {
+		return loaded, nil
+	}
+
+	patterns := p.patterns
+	if !typed {
+		// only changed packages are loaded with types
+		patterns = make([]string, 0, len(missed))
+		for _, idx := range missed {
+			patterns = append(patterns, pkgDir(pkgs[idx]))
+		}
+	}
+
+	typedPkgs, err := loadPackages(fullLoadMode, patterns)
+	if err != nil {
+		return Loaded{}, err
+	}
+
+	byPath := make(map[string]*packages.Package, len(typedPkgs))
+	for _, pkg := range typedPkgs {
+		byPath[pkg.PkgPath] = pkg
+	}
+
+	toParse := make([]*packages.Package, len(missed))
+	for i, idx := range missed {
+		pkg, ok := byPath[pkgs[idx].PkgPath]
+		if !ok {
+			return Loaded{}, fmt.Errorf("package %s not found in loaded packages", pkgs[idx].PkgPath)
+		}
+		toParse[i] = pkg
+	}
+
+	parsed, err := l.parse(toParse, p.opts)
+	if err != nil {
+		return Loaded{}, err
+	}
+
+	for i, idx := range missed {
+		results[idx] = parsed[i]
+		loaded.Parsed = append(loaded.Parsed, parsed[i].PkgPath)
+		if len(parsed[i].Instances) > 0 {
+			// instances hold go/types types, which can't be encoded
+			logging.Debug("package has generic wrapper instances, it's not cached", "pkg", parsed[i].PkgPath)
+			continue
+		}
+
+		if err := l.cache.Put(keys[idx], parsed[i]); err != nil {
+			logging.Debug("failed to save parsing result to cache", "pkg", parsed[i].PkgPath, "error", err)
+		}
+	}
+
+	if typed {
+		loaded.Packages = typedPkgs
+		loaded.Typed = true
+	}
+	return loaded, nil
+}
+
+// parse parses packages concurrently and returns results in order of packages
+func (l *Loader) parse(pkgs []*packages.Package, opts []parser.ParseOpt) ([]parser.Result, error) {
+	var (
+		eg      errgroup.Group
+		results = make([]parser.Result, len(pkgs))
+	)
+
+	if l.concurrency > 0 {
+		eg.SetLimit(l.concurrency)
+	}
+
+	for i, pkg := range pkgs {
+		eg.Go(func() error {
+			res, err := l.parser.Parse(pkg, opts...)
+			if err != nil {
+				return fmt.Errorf("parse pkg %s: %w", pkg.PkgPath, err)
+			}
+
+			results[i] = res
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// PackagesKey returns content key of packages, matching patterns. Packages are loaded without types
+func PackagesKey(patterns []string, salt string) (string, error) {
+	pkgs, err := loadPackages(lightLoadMode, patterns)
+	if err != nil {
+		return "", err
+	}
+
+	k := newKeyer(salt)
+	keys := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		key, err := k.key(pkg)
+		if err != nil {
+			return "", fmt.Errorf("compute key of package %s: %w", pkg.PkgPath, err)
+		}
+		keys = append(keys, key)
+	}
+	return Key(keys...), nil
+}
+
+func loadPackages(mode packages.LoadMode, patterns []string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: mode}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("failed to load one or more packages: %s", strings.Join(patterns, ", "))
+	}
+
+	// packages are sorted, so keys and results don't depend on load order
+	slices.SortFunc(pkgs, func(a, b *packages.Package) int {
+		return strings.Compare(a.PkgPath, b.PkgPath)
+	})
+	return pkgs, nil
+}
+
+// pkgDir returns directory of package, which can be used as load pattern
+func pkgDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) == 0 {
+		return pkg.PkgPath
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
@@ -0,0 +1,121 @@
+package parsecache
+
+import (
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoader(t *testing.T) *Loader {
+	t.Helper()
+
+	p, err := parser.New()
+	require.NoError(t, err)
+
+	c, err := New(t.TempDir())
+	require.NoError(t, err)
+	return NewLoader(p, c, 0)
+}
+
+func TestLoader_ReusesCachedResults(t *testing.T) {
+	loader := newTestLoader(t)
+	patterns := []string{testsuite.FixturePath(t, "handlers"), testsuite.FixturePath(t, "parser/c1")}
+
+	first, err := loader.Load(patterns, ParseOpts()...)
+	require.NoError(t, err)
+	require.Len(t, first.Parsed, 2)
+	require.Len(t, first.Results, 2)
+	require.NotEmpty(t, first.Results[0].Handlers)
+
+	second, err := loader.Load(patterns, ParseOpts()...)
+	require.NoError(t, err)
+	require.Empty(t, second.Parsed)
+	require.False(t, second.Typed, "packages are not loaded with types, when all results are cached")
+	require.Equal(t, first.Results, second.Results)
+
+	typed, err := loader.LoadTyped(patterns, ParseOpts()...)
+	require.NoError(t, err)
+	require.True(t, typed.Typed)
+	require.Empty(t, typed.Parsed)
+	require.NotNil(t, typed.Packages[0].TypesInfo)
+	require.Equal(t, first.Results, typed.Results)
+}
+
+func TestLoader_InvalidatesChangedPackages(t *testing.T) {
+	module := testsuite.NewModule(t, "example.com/project")
+	module.Write(t, "dto/dto.go", `
+package dto
+
+type User struct{}
+`)
+	module.Write(t, "api/api.go", `
+package api
+
+import "example.com/project/dto"
+
+type Page struct {
+	Users []dto.User
+}
+`)
+	t.Chdir(module.Root())
+
+	loader := newTestLoader(t)
+	patterns := []string{module.Path("api"), module.Path("dto")}
+	load := func() Loaded {
+		loaded, err := loader.Load(patterns, ParseOpts()...)
+		require.NoError(t, err)
+		return loaded
+	}
+
+	// parser resolvers load echo on first parsing, which may change go.sum of test module and keys of its packages
+	require.Len(t, load().Parsed, 2)
+	load()
+	require.Empty(t, load().Parsed)
+
+	module.Write(t, "dto/dto.go", `
+package dto
+
+type User struct{}
+type Admin struct{}
+`)
+
+	loaded := load()
+	require.Equal(t, []string{"example.com/project/api", "example.com/project/dto"}, loaded.Parsed,
+		"changed package and its importers are parsed again")
+	require.Len(t, loaded.Results[1].AdditionalModels, 2)
+	require.Empty(t, load().Parsed)
+
+	keyWithEnums, err := PackagesKey(patterns, parser.OptsKey(parser.ParseEnums()))
+	require.NoError(t, err)
+	keyWithModels, err := PackagesKey(patterns, parser.OptsKey(parser.ParseAllModels()))
+	require.NoError(t, err)
+	require.NotEqual(t, keyWithEnums, keyWithModels)
+}
+
+func TestLoader_Plan(t *testing.T) {
+	loader := newTestLoader(t)
+	patterns := []string{testsuite.FixturePath(t, "handlers"), testsuite.FixturePath(t, "parser/c1")}
+
+	plan, err := loader.Plan(patterns, ParseOpts()...)
+	require.NoError(t, err)
+
+	key, err := PackagesKey(patterns, parser.OptsKey(ParseOpts()...))
+	require.NoError(t, err)
+	require.Equal(t, key, plan.Key)
+
+	first, err := loader.LoadTypedPlan(plan)
+	require.NoError(t, err)
+	require.True(t, first.Typed)
+	require.Len(t, first.Parsed, 2)
+
+	second, err := loader.LoadTypedPlan(plan)
+	require.NoError(t, err)
+	require.Empty(t, second.Parsed)
+	require.Equal(t, first.Results, second.Results)
+
+	uncached, err := NewLoader(loader.parser, nil, 0).Plan(patterns, ParseOpts()...)
+	require.NoError(t, err)
+	require.Empty(t, uncached.Key)
+}
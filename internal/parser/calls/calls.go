@@ -1,8 +1,17 @@
 package calls
 
-import "go/ast"
-
-func IsEchoContextMethodCall(call *ast.CallExpr) bool {
+import (
+	"go/ast"
+	"go/types"
+)
+
+const echoPkg = "github.com/labstack/echo/v4"
+
+// IsEchoContextMethodCall reports if call is a method call of echo.Context.
+// Methods are resolved with types info, so calls on aliased imports, aliases of echo.Context, arbitrary receiver
+// expressions and custom contexts embedding echo.Context, such as type AppContext struct{ echo.Context }, are recognized.
+// Methods, redeclared by custom context, are not echo.Context methods.
+func IsEchoContextMethodCall(call *ast.CallExpr, info *types.Info) bool {
 	if call == nil {
 		return false
 	}
@@ -12,74 +21,73 @@ func IsEchoContextMethodCall(call *ast.CallExpr) bool {
 		return false
 	}
 
-	ident, ok := sel.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
-
-	if ident.Obj == nil || ident.Obj.Decl == nil {
-		return false
-	}
-
-	decl, ok := ident.Obj.Decl.(*ast.Field)
-	if !ok {
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
 		return false
 	}
 
-	declSel, ok := decl.Type.(*ast.SelectorExpr)
+	fn, ok := selection.Obj().(*types.Func)
 	if !ok {
 		return false
 	}
 
-	declXIdent, ok := declSel.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
+	recv := fn.Signature().Recv()
+	return recv != nil && IsEchoContext(recv.Type())
+}
 
-	if declSel.Sel == nil {
-		return false
-	}
+// IsEchoContext reports if type is echo.Context or its alias
+func IsEchoContext(t types.Type) bool {
+	return isEchoType(t, "Context")
+}
 
-	return declXIdent.Name == "echo" && declSel.Sel.Name == "Context"
+// IsCustomEchoContext reports if type is a custom context, wrapping echo.Context: struct or pointer to struct with
+// echo.Context field, or interface embedding echo.Context. Embedded structs and interfaces are checked recursively
+func IsCustomEchoContext(t types.Type) bool {
+	return isCustomEchoContext(t, make(map[types.Type]struct{}))
 }
 
-func IsHeaderMethodCall(call *ast.CallExpr) bool {
-	if call == nil {
-		return false
+func isCustomEchoContext(t types.Type, seen map[types.Type]struct{}) bool {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
 	}
 
-	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if !ok {
+	if _, ok := seen[t]; ok {
 		return false
 	}
+	seen[t] = struct{}{}
 
-	ident, ok := sel.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := range u.NumFields() {
+			field := u.Field(i)
+			if IsEchoContext(field.Type()) || field.Embedded() && isCustomEchoContext(field.Type(), seen) {
+				return true
+			}
+		}
 
-	if ident.Obj == nil || ident.Obj.Decl == nil {
-		return false
+	case *types.Interface:
+		for i := range u.NumEmbeddeds() {
+			embedded := u.EmbeddedType(i)
+			if IsEchoContext(embedded) || isCustomEchoContext(embedded, seen) {
+				return true
+			}
+		}
 	}
 
-	decl, ok := ident.Obj.Decl.(*ast.Field)
-	if !ok {
-		return false
-	}
+	return false
+}
 
-	declSel, ok := decl.Type.(*ast.SelectorExpr)
-	if !ok {
-		return false
-	}
+// IsEchoHandlerFunc reports if type is echo.HandlerFunc or its alias
+func IsEchoHandlerFunc(t types.Type) bool {
+	return isEchoType(t, "HandlerFunc")
+}
 
-	declXIdent, ok := declSel.X.(*ast.Ident)
+func isEchoType(t types.Type, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
 	if !ok {
 		return false
 	}
 
-	if declSel.Sel == nil {
-		return false
-	}
-
-	return declXIdent.Name == "http" && declSel.Sel.Name == "Header"
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == echoPkg && obj.Name() == name
 }
@@ -0,0 +1,160 @@
+// Package diag records constructs of handler source code, which parser can't represent in spec,
+// such as non-constant status codes or parameter names, so they are reported with their positions
+package diag
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+type Severity string
+
+const (
+	// SeverityWarning construct is represented in spec with less details, such as string typed parameter
+	SeverityWarning Severity = "warning"
+	// SeverityError construct is missing in spec, such as response with non-constant status code
+	SeverityError Severity = "error"
+)
+
+// Code is a stable identifier of diagnostic kind. Codes are never reused, so they can be referred to in CI and docs
+type Code string
+
+const (
+	// NonConstantStatusCode TYP001 status code of response isn't a constant or literal
+	NonConstantStatusCode Code = "TYP001"
+	// UnresolvedContentType TYP002 content type of response can't be resolved
+	UnresolvedContentType Code = "TYP002"
+	// UnsupportedResponseType TYP003 response model type can't be represented in spec
+	UnsupportedResponseType Code = "TYP003"
+	// NonLiteralParamName TYP004 path, query, form or header name isn't a string literal
+	NonLiteralParamName Code = "TYP004"
+	// UnsupportedBindType TYP005 argument of c.Bind can't be used as request model
+	UnsupportedBindType Code = "TYP005"
+	// UnsupportedMessageType TYP006 streamed model, SSE event or websocket message type can't be represented in spec
+	UnsupportedMessageType Code = "TYP006"
+	// AmbiguousEventType TYP007 SSE handler sends events of different types
+	AmbiguousEventType Code = "TYP007"
+	// MissingDiscriminatorProperty TYP008 interface implementation has no required property of default discriminator
+	MissingDiscriminatorProperty Code = "TYP008"
+)
+
+var severities = map[Code]Severity{
+	NonConstantStatusCode:        SeverityError,
+	UnresolvedContentType:        SeverityError,
+	UnsupportedResponseType:      SeverityError,
+	NonLiteralParamName:          SeverityWarning,
+	UnsupportedBindType:          SeverityError,
+	UnsupportedMessageType:       SeverityWarning,
+	AmbiguousEventType:           SeverityWarning,
+	MissingDiscriminatorProperty: SeverityWarning,
+}
+
+// Severity returns severity of diagnostics with code
+func (c Code) Severity() Severity {
+	if s, ok := severities[c]; ok {
+		return s
+	}
+	return SeverityWarning
+}
+
+// Diagnostic is a construct skipped by parser
+type Diagnostic struct {
+	Pos token.Position `json:"pos"`
+	// Handler is a name of handler, where construct is found
+	Handler  string   `json:"handler,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     Code     `json:"code"`
+	// Message is a reason, why construct was skipped
+	Message string `json:"message"`
+}
+
+// String formats diagnostic like compilers do: file:line:col: severity: handler: message (code)
+func (d Diagnostic) String() string {
+	return d.format(d.Pos.String())
+}
+
+func (d Diagnostic) format(pos string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s: ", pos, d.Severity)
+	if d.Handler != "" {
+		fmt.Fprintf(&sb, "%s: ", d.Handler)
+	}
+	fmt.Fprintf(&sb, "%s (%s)", d.Message, d.Code)
+	return sb.String()
+}
+
+// Sort sorts diagnostics by file, line and column
+func Sort(diags []Diagnostic) {
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+		)
+	})
+}
+
+// Print writes sorted diagnostics to w, one per line. File names are made relative to dir, when they are inside it
+func Print(w io.Writer, dir string, diags []Diagnostic) error {
+	diags = slices.Clone(diags)
+	Sort(diags)
+
+	for _, d := range diags {
+		pos := d.Pos
+		if dir != "" && filepath.IsAbs(pos.Filename) {
+			if rel, err := filepath.Rel(dir, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+				pos.Filename = rel
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, d.format(pos.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collector collects diagnostics of single handler. Nil collector only logs them
+type Collector struct {
+	fset    *token.FileSet
+	handler string
+	diags   []Diagnostic
+}
+
+func NewCollector(fset *token.FileSet, handler string) *Collector {
+	return &Collector{fset: fset, handler: handler}
+}
+
+// Addf records diagnostic with code at pos
+func (c *Collector) Addf(pos token.Pos, code Code, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if c == nil {
+		logging.Debug("skipped construct", "code", code, "reason", msg)
+		return
+	}
+
+	d := Diagnostic{
+		Pos:      c.fset.Position(pos),
+		Handler:  c.handler,
+		Severity: code.Severity(),
+		Code:     code,
+		Message:  msg,
+	}
+	logging.Debug("skipped construct", logging.Pos(d.Pos), logging.Handler(d.Handler), "code", code, "reason", d.Message)
+	c.diags = append(c.diags, d)
+}
+
+// Diagnostics returns collected diagnostics in order of recording
+func (c *Collector) Diagnostics() []Diagnostic {
+	if c == nil {
+		return nil
+	}
+	return c.diags
+}
@@ -0,0 +1,43 @@
+package diag
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	diags := []Diagnostic{
+		{
+			Pos:      token.Position{Filename: "/src/api/users.go", Line: 42, Column: 9},
+			Handler:  "GetUser",
+			Severity: NonConstantStatusCode.Severity(),
+			Code:     NonConstantStatusCode,
+			Message:  "response skipped, unresolved status code",
+		},
+		{
+			Pos:      token.Position{Filename: "/src/api/users.go", Line: 12, Column: 16},
+			Handler:  "ListUsers",
+			Severity: NonLiteralParamName.Severity(),
+			Code:     NonLiteralParamName,
+			Message:  "query param name is not a string literal: name",
+		},
+		{
+			Pos:      token.Position{Filename: "/lib/auth/auth.go", Line: 3, Column: 1},
+			Severity: AmbiguousEventType.Severity(),
+			Code:     AmbiguousEventType,
+			Message:  "event schema skipped",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, "/src", diags))
+
+	want := "/lib/auth/auth.go:3:1: warning: event schema skipped (TYP007)\n" +
+		"api/users.go:12:16: warning: ListUsers: query param name is not a string literal: name (TYP004)\n" +
+		"api/users.go:42:9: error: GetUser: response skipped, unresolved status code (TYP001)\n"
+	require.Equal(t, want, buf.String())
+	require.Equal(t, "GetUser", diags[0].Handler, "diagnostics must not be reordered in place")
+}
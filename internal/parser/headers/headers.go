@@ -9,6 +9,7 @@ import (
 
 	"github.com/d1vbyz3r0/typed/common/meta"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/logging"
 )
 
@@ -38,7 +39,7 @@ func IsHttpHeaderMethod(call *ast.CallExpr, typesInfo *types.Info) bool {
 	return recvTypeName == "http.Header"
 }
 
-func NewInlineRequestHeaders(funcDecl *ast.FuncDecl, typesInfo *types.Info) []Header {
+func NewInlineRequestHeaders(funcDecl *ast.FuncDecl, typesInfo *types.Info, diags *diag.Collector) []Header {
 	var headers []Header
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
@@ -61,7 +62,7 @@ func NewInlineRequestHeaders(funcDecl *ast.FuncDecl, typesInfo *types.Info) []He
 
 		lit, ok := call.Args[0].(*ast.BasicLit)
 		if !ok {
-			logging.Debug("skipping non BasicLit value")
+			diags.Addf(call.Args[0].Pos(), diag.NonLiteralParamName, "header name is not a string literal: %s", types.ExprString(call.Args[0]))
 			return true
 		}
 
@@ -48,7 +48,7 @@ func Test_NewInlineHeaders(t *testing.T) {
 			return true
 		}
 
-		got := NewInlineRequestHeaders(decl, typesInfo)
+		got := NewInlineRequestHeaders(decl, typesInfo, nil)
 		require.ElementsMatch(t, want, got)
 		return true
 	})
@@ -0,0 +1,38 @@
+package headers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+)
+
+type headerJSON struct {
+	Name     string          `json:"name"`
+	Type     json.RawMessage `json:"type"`
+	Required bool            `json:"required,omitempty"`
+	Value    string          `json:"value,omitempty"`
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	t, err := typing.MarshalReflectType(h.Type)
+	if err != nil {
+		return nil, fmt.Errorf("marshal header %s type: %w", h.Name, err)
+	}
+	return json.Marshal(headerJSON{Name: h.Name, Type: t, Required: h.Required, Value: h.Value})
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	var v headerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	t, err := typing.UnmarshalReflectType(v.Type)
+	if err != nil {
+		return fmt.Errorf("unmarshal header %s type: %w", v.Name, err)
+	}
+
+	*h = Header{Name: v.Name, Type: t, Required: v.Required, Value: v.Value}
+	return nil
+}
@@ -3,67 +3,220 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/d1vbyz3r0/typed/common/meta"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/calls"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/internal/parser/enums"
 	"github.com/d1vbyz3r0/typed/internal/parser/request"
 	"github.com/d1vbyz3r0/typed/internal/parser/response"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/codes"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/mime"
+	"github.com/d1vbyz3r0/typed/internal/parser/websocket"
 	"github.com/d1vbyz3r0/typed/logging"
 	"golang.org/x/tools/go/packages"
 )
 
-// isWrapperFunction checks if func has signature: func(...) echo.HandlerFunc {}
-func isWrapperFunction(funcDecl *ast.FuncDecl) bool {
-	if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) != 1 {
-		return false
-	}
+// isWrapperFunction checks if func has signature: func(...) echo.HandlerFunc {}.
+// Result type is resolved with types info, so aliased echo imports and aliases of echo.HandlerFunc are supported
+func isWrapperFunction(sig *types.Signature) bool {
+	return sig.Results().Len() == 1 && calls.IsEchoHandlerFunc(sig.Results().At(0).Type())
+}
 
-	result := funcDecl.Type.Results.List[0].Type
-	if sel, ok := result.(*ast.SelectorExpr); ok {
-		if x, ok := sel.X.(*ast.Ident); ok {
-			return x.Name == "echo" && sel.Sel.Name == "HandlerFunc"
-		}
+// isEchoHandler checks if func has signature of echo.HandlerFunc: func(echo.Context) error
+func isEchoHandler(sig *types.Signature) bool {
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
 	}
 
-	return false
+	return calls.IsEchoContext(sig.Params().At(0).Type()) &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
 }
 
-// isEchoHandler checks if func has signature of echo.HandlerFunc
-func isEchoHandler(funcDecl *ast.FuncDecl) bool {
-	if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) != 1 {
+// isCustomContextHandler checks if func has signature of handler with custom context: func(*AppContext) error,
+// where context type wraps echo.Context
+func isCustomContextHandler(sig *types.Signature) bool {
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
 		return false
 	}
 
-	if funcDecl.Type.Params == nil || len(funcDecl.Type.Params.List) != 1 {
-		return false
-	}
+	return calls.IsCustomEchoContext(sig.Params().At(0).Type()) &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
 
-	paramType := funcDecl.Type.Params.List[0].Type
-	if sel, ok := paramType.(*ast.SelectorExpr); ok {
-		if x, ok := sel.X.(*ast.Ident); ok {
-			if x.Name != "echo" || sel.Sel.Name != "Context" {
-				return false
-			}
+// isHandlerSignature reports if signature is func(echo.Context) error or func(...) echo.HandlerFunc
+func isHandlerSignature(sig *types.Signature) bool {
+	return isEchoHandler(sig) || isWrapperFunction(sig)
+}
+
+// ContainsHandlers reports if package declares echo handlers or wrapper functions, returning echo.HandlerFunc
+func ContainsHandlers(pkg *packages.Package) bool {
+	for _, file := range pkg.Syntax {
+		if len(handlerDecls(file, pkg.TypesInfo, nil)) > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	result := funcDecl.Type.Results.List[0].Type
-	if ident, ok := result.(*ast.Ident); ok {
-		return ident.Name == "error"
+// handlerDecls returns declarations of handlers and wrappers in file: functions, methods, package variables,
+// initialized with function literals, and function literals, passed to calls inside functions, such as inline route
+// handlers. Function literals are returned as declarations named after variable or as compiler names closures,
+// such as MapRoutes.func1, with receiver of enclosing method.
+// If adapters are set, adapter functions are skipped and handlers with custom context are returned
+func handlerDecls(file *ast.File, info *types.Info, adapters map[string]struct{}) []*ast.FuncDecl {
+	isHandler := func(sig *types.Signature) bool {
+		return isHandlerSignature(sig) || len(adapters) > 0 && isCustomContextHandler(sig)
 	}
 
-	return false
+	var res []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			res = append(res, closureDecls(decl, info, isHandler)...)
+
+			fn, ok := info.Defs[decl.Name].(*types.Func)
+			if !ok || !isHandler(fn.Signature()) {
+				continue
+			}
+
+			if _, ok := adapters[fn.FullName()]; ok {
+				logging.Debug("skipping handler adapter", "func", fn.FullName())
+				continue
+			}
+			res = append(res, decl)
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok || len(vs.Names) != len(vs.Values) {
+					continue
+				}
+
+				for i, name := range vs.Names {
+					lit, ok := ast.Unparen(vs.Values[i]).(*ast.FuncLit)
+					if !ok {
+						continue
+					}
+
+					v, ok := info.Defs[name].(*types.Var)
+					if !ok {
+						continue
+					}
+
+					sig, ok := v.Type().Underlying().(*types.Signature)
+					if !ok || !isHandler(sig) {
+						continue
+					}
+
+					doc := vs.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+
+					res = append(res, &ast.FuncDecl{
+						Doc:  doc,
+						Name: name,
+						Type: lit.Type,
+						Body: lit.Body,
+					})
+				}
+			}
+		}
+	}
+	return res
 }
 
 type Handler struct {
-	Doc       string
-	Name      string
-	Pkg       string
+	Doc  string
+	Name string
+	Pkg  string
+	// Recv is a receiver type name of method handler, such as *UserHandler. It's empty for functions and variables
+	Recv      string
 	Request   *request.Request
 	Responses response.StatusCodeMapping
+	// WebSocket holds messages, sent and received by websocket handler. It's nil for regular handlers
+	WebSocket *websocket.Messages
+	// TypeParams holds type parameter names of generic wrapper function, such as Handle[Req, Resp any]() echo.HandlerFunc.
+	// Models of such handler refer to type parameters, it's instantiated for each type arguments found at call sites
+	TypeParams []string
+	// TypeArgs holds type arguments of generic wrapper instance
+	TypeArgs []*typing.Type
+	// Ignored is set by //typed:ignore directive in handler doc comment, such handlers are not added to spec
+	Ignored bool
+	// Diagnostics are constructs of handler, skipped by parser, with their positions
+	Diagnostics []diag.Diagnostic
+}
+
+// IgnoreDirective hides handler from spec, when it's placed in handler doc comment
+const IgnoreDirective = "typed:ignore"
+
+// Key returns handler key in format <pkg path>.<func name>[<type args>] for functions and
+// (<pkg path>.<recv>).<method name> for methods, such as (*github.com/acme/api.UserHandler).List
+func (h Handler) Key() string {
+	return meta.FuncKey(h.Pkg, h.Recv, h.Name) + typing.FormatTypeArgs(h.TypeArgs)
+}
+
+// IsGeneric reports if handler is generic wrapper, which models refer to type parameters
+func (h Handler) IsGeneric() bool {
+	return len(h.TypeParams) > 0
+}
+
+// Instantiate returns handler with type parameters replaced by provided type arguments in request, responses
+// and websocket messages
+func (h Handler) Instantiate(args []types.Type) (Handler, error) {
+	if len(args) != len(h.TypeParams) {
+		return Handler{}, fmt.Errorf("expected %d type arguments, got %d", len(h.TypeParams), len(args))
+	}
+
+	goArgs := make(map[string]types.Type, len(args))
+	typeArgs := make(map[string]*typing.Type, len(args))
+	res := h
+	res.TypeParams = nil
+	res.TypeArgs = make([]*typing.Type, len(args))
+	for i, arg := range args {
+		t, err := typing.NewType(arg)
+		if err != nil {
+			return Handler{}, fmt.Errorf("type argument %s: %w", h.TypeParams[i], err)
+		}
+
+		goArgs[h.TypeParams[i]] = arg
+		typeArgs[h.TypeParams[i]] = t
+		res.TypeArgs[i] = t
+	}
+
+	req, err := h.Request.Instantiate(goArgs)
+	if err != nil {
+		return Handler{}, fmt.Errorf("instantiate request: %w", err)
+	}
+
+	res.Request = req
+	res.Responses = h.Responses.Substitute(typeArgs)
+	res.WebSocket = h.WebSocket.Substitute(typeArgs)
+	return res, nil
+}
+
+// Instance is an instantiation of generic wrapper function, such as Handle[CreateUserReq, UserResp]
+type Instance struct {
+	// Key is a key of generic wrapper in format <pkg path>.<func name>
+	Key      string
+	TypeArgs []types.Type
+}
+
+// Models returns models of responses and websocket messages. Request model is not included
+func (h Handler) Models() []*typing.Type {
+	var res []*typing.Type
+	for _, responses := range h.Responses {
+		for _, resp := range responses {
+			res = append(res, resp.Models()...)
+		}
+	}
+	return append(res, h.WebSocket.Models()...)
 }
 
 type Result struct {
@@ -72,38 +225,63 @@ type Result struct {
 	// AdditionalModels will contain array of all type declarations and structs used in c.Bind() if ParseAllModels was provided as opt.
 	// It can contain duplicates, it's up to you to deduplicate them.
 	AdditionalModels []*typing.Type
+	// Generics holds generic wrapper handlers, which are not added to Handlers until instantiated with InstantiateGenerics
+	Generics []Handler
+	// Instances holds instantiations of generic wrappers, found in package. Wrappers can be declared in other packages.
+	// Type arguments are go/types types, so instances are not encoded
+	Instances []Instance `json:"-"`
+	// Diagnostics are skipped constructs of all handlers in package, including generic wrappers
+	Diagnostics []diag.Diagnostic
 }
 
 type Parser struct {
+	// resolvers load net/http and echo packages, so they are created on first Parse call only.
+	// It allows to skip loading, when all results are read from cache
+	once          sync.Once
+	err           error
 	codesResolver *codes.Resolver
 	mimeResolver  *mime.Resolver
 }
 
 func New() (*Parser, error) {
-	cr, err := codes.NewResolver()
-	if err != nil {
-		return nil, fmt.Errorf("create codes resolver: %v", err)
-	}
+	return &Parser{}, nil
+}
 
-	mr, err := mime.NewResolver()
-	if err != nil {
-		return nil, fmt.Errorf("create mime resolver: %v", err)
-	}
+func (p *Parser) initResolvers() error {
+	p.once.Do(func() {
+		cr, err := codes.NewResolver()
+		if err != nil {
+			p.err = fmt.Errorf("create codes resolver: %v", err)
+			return
+		}
 
-	return &Parser{
-		codesResolver: cr,
-		mimeResolver:  mr,
-	}, nil
+		mr, err := mime.NewResolver()
+		if err != nil {
+			p.err = fmt.Errorf("create mime resolver: %v", err)
+			return
+		}
+
+		p.codesResolver = cr
+		p.mimeResolver = mr
+	})
+	return p.err
 }
 
 // Parse parses package and returns all found enums and handlers
 func (p *Parser) Parse(pkg *packages.Package, opts ...ParseOpt) (Result, error) {
+	if err := p.initResolvers(); err != nil {
+		return Result{}, err
+	}
+
 	parseOpts := new(parserOpts)
 	for _, opt := range opts {
 		opt(parseOpts)
 	}
 
-	result := Result{PkgPath: pkg.PkgPath}
+	result := Result{
+		PkgPath:   pkg.PkgPath,
+		Instances: FindInstances(pkg),
+	}
 	for _, file := range pkg.Syntax {
 		if parseOpts.parseEnums {
 			foundEnums, err := enums.Extract(pkg.Types, file, pkg.TypesInfo)
@@ -113,79 +291,75 @@ func (p *Parser) Parse(pkg *packages.Package, opts ...ParseOpt) (Result, error)
 			result.AdditionalModels = append(result.AdditionalModels, foundEnums...)
 		}
 
-		ast.Inspect(file, func(n ast.Node) bool {
-			decl, ok := n.(*ast.FuncDecl)
-			if !ok {
-				return true
-			}
+		for _, decl := range handlerDecls(file, pkg.TypesInfo, parseOpts.adapters) {
+			logging.Debug("found echo handler", logging.Pkg(pkg.PkgPath), logging.Handler(decl.Name.Name), "filename", file.Name)
 
-			if !isEchoHandler(decl) && !isWrapperFunction(decl) {
-				return true
-			}
+			diags := diag.NewCollector(pkg.Fset, decl.Name.Name)
+			req := request.New(decl, pkg.TypesInfo, diags, parseOpts.RequestParseOpts()...)
+			responses := response.NewStatusCodeMapping(decl, p.codesResolver, p.mimeResolver, pkg.TypesInfo, diags)
+			messages := websocket.NewMessages(decl, pkg.TypesInfo, diags)
 
-			logging.Debug("found echo handler", "pkg", pkg, "filename", file.Name, "name", decl.Name.Name)
+			h := Handler{
+				Doc:         meta.GetFuncDocumentation(decl),
+				Name:        decl.Name.Name,
+				Pkg:         pkg.PkgPath,
+				Recv:        recvName(decl, pkg.TypesInfo),
+				Request:     req,
+				Responses:   responses,
+				WebSocket:   messages,
+				TypeParams:  typeParams(decl),
+				Ignored:     meta.HasDirective(decl.Doc, IgnoreDirective),
+				Diagnostics: diags.Diagnostics(),
+			}
+			result.Diagnostics = append(result.Diagnostics, h.Diagnostics...)
 
-			req := request.New(decl, pkg.TypesInfo, parseOpts.RequestParseOpts()...)
-			responses := response.NewStatusCodeMapping(decl, p.codesResolver, p.mimeResolver, pkg.TypesInfo)
+			if h.IsGeneric() {
+				logging.Debug("found generic wrapper handler", logging.Pkg(pkg.PkgPath), logging.Handler(h.Name), "type_params", h.TypeParams)
+				result.Generics = append(result.Generics, h)
+				continue
+			}
 
 			if parseOpts.parseAllModels {
 				if req.ModelType != nil {
 					result.AdditionalModels = append(result.AdditionalModels, req.ModelType)
 				}
-
-				for _, resp := range responses {
-					for _, r := range resp {
-						if r.ModelType == nil {
-							continue
-						}
-						result.AdditionalModels = append(result.AdditionalModels, r.ModelType)
-					}
-				}
-			}
-
-			h := Handler{
-				Doc:       meta.GetFuncDocumentation(decl),
-				Name:      decl.Name.Name,
-				Pkg:       pkg.PkgPath,
-				Request:   req,
-				Responses: responses,
+				result.AdditionalModels = append(result.AdditionalModels, h.Models()...)
 			}
 
 			result.Handlers = append(result.Handlers, h)
-			return true
-		})
+		}
 
 		if parseOpts.parseAllModels {
 			scope := pkg.Types.Scope()
 			for _, name := range scope.Names() {
 				obj := scope.Lookup(name)
 				if obj == nil {
-					logging.Warn("object not found in scope", "pkg", pkg.PkgPath, "name", name)
+					logging.Warn("object not found in scope", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
 				if !obj.Exported() {
-					logging.Debug("skipping non-exported object, can't be a model", "pkg", pkg.PkgPath, "name", name)
+					logging.Debug("skipping non-exported object, can't be a model", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
 				if typing.IsFunc(obj.Type()) {
-					logging.Debug("skipping function object, can't be a model", "pkg", pkg.PkgPath, "name", name)
+					logging.Debug("skipping function object, can't be a model", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
-				if typing.IsInterface(obj.Type()) {
-					logging.Debug("skipping interface object, can't be a model", "pkg", pkg.PkgPath, "name", name)
+				if typing.IsInterface(obj.Type()) && typing.IsAnyType(obj.Type().Underlying()) {
+					logging.Debug("skipping empty interface object, can't be a model", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
 				if typing.IsConstOrGlobal(obj) {
-					logging.Debug("skipping const/global object, can't be a model", "pkg", pkg.PkgPath, "name", name)
+					logging.Debug("skipping const/global object, can't be a model", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
 				if typing.HasTypeParams(obj.Type()) {
-					logging.Debug("skipping generic type declaration, only instantiated generic type can be a model", pkg.PkgPath, "name", name)
+					logging.Debug("skipping generic type declaration, only instantiated generic type can be a model", logging.Pkg(pkg.PkgPath), "name", name)
 					continue
 				}
 
@@ -202,3 +376,163 @@ func (p *Parser) Parse(pkg *packages.Package, opts ...ParseOpt) (Result, error)
 
 	return result, nil
 }
+
+// InstantiateGenerics adds handler to results for each instance of generic wrapper handler.
+// Wrappers and their instances can be found in different packages, so results of all packages should be provided
+func InstantiateGenerics(results []Result) {
+	generics := make(map[string]Handler)
+	owners := make(map[string]int)
+	for i, res := range results {
+		for _, h := range res.Generics {
+			generics[h.Key()] = h
+			owners[h.Key()] = i
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, res := range results {
+		for _, inst := range res.Instances {
+			h, ok := generics[inst.Key]
+			if !ok {
+				logging.Debug("generic wrapper of instance not found in parsed packages", "key", inst.Key)
+				continue
+			}
+
+			instance, err := h.Instantiate(inst.TypeArgs)
+			if err != nil {
+				logging.Warn("failed to instantiate generic wrapper handler", "key", inst.Key, "error", err)
+				continue
+			}
+
+			if _, ok := seen[instance.Key()]; ok {
+				continue
+			}
+			seen[instance.Key()] = struct{}{}
+
+			logging.Debug("instantiated generic wrapper handler", "key", instance.Key())
+			owner := &results[owners[inst.Key]]
+			owner.Handlers = append(owner.Handlers, instance)
+		}
+	}
+}
+
+// typeParams returns type parameter names of generic wrapper function
+func typeParams(decl *ast.FuncDecl) []string {
+	if decl.Type.TypeParams == nil {
+		return nil
+	}
+
+	var res []string
+	for _, field := range decl.Type.TypeParams.List {
+		for _, name := range field.Names {
+			res = append(res, name.Name)
+		}
+	}
+	return res
+}
+
+// recvName returns receiver type name of method handler. Handlers, declared as variables, have no receiver
+func recvName(decl *ast.FuncDecl, info *types.Info) string {
+	if fn, ok := info.Defs[decl.Name].(*types.Func); ok {
+		return meta.GetRecvName(fn)
+	}
+
+	// closures have receiver of enclosing method
+	if decl.Recv != nil && len(decl.Recv.List) == 1 {
+		if t := info.TypeOf(decl.Recv.List[0].Type); t != nil {
+			return meta.GetRecvTypeName(t)
+		}
+	}
+	return ""
+}
+
+// closureDecls returns function literals with handler signature, passed to calls in function body, such as
+// e.GET("/health", func(c echo.Context) error { ... }). Nested literals are skipped, as their names
+// depend on enclosing literals
+func closureDecls(decl *ast.FuncDecl, info *types.Info, isHandler func(sig *types.Signature) bool) []*ast.FuncDecl {
+	names := meta.ClosureNames(decl)
+	if len(names) == 0 {
+		return nil
+	}
+
+	var res []*ast.FuncDecl
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		for _, arg := range call.Args {
+			lit, ok := ast.Unparen(arg).(*ast.FuncLit)
+			if !ok {
+				continue
+			}
+
+			name, ok := names[lit]
+			if !ok {
+				continue
+			}
+
+			sig, ok := info.TypeOf(lit).(*types.Signature)
+			if !ok || !isHandler(sig) {
+				continue
+			}
+
+			res = append(res, &ast.FuncDecl{
+				Recv: decl.Recv,
+				Name: &ast.Ident{NamePos: lit.Pos(), Name: name},
+				Type: lit.Type,
+				Body: lit.Body,
+			})
+		}
+		return true
+	})
+	return res
+}
+
+// FindInstances returns instantiations of generic handlers and wrappers, returning echo.HandlerFunc, with concrete type arguments.
+// Instances inside other generic functions have type parameters as arguments, so they are skipped
+func FindInstances(pkg *packages.Package) []Instance {
+	var res []Instance
+	for ident, inst := range pkg.TypesInfo.Instances {
+		fn, ok := pkg.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() == nil || !isHandlerSignature(fn.Origin().Signature()) {
+			continue
+		}
+
+		args := slices.Collect(inst.TypeArgs.Types())
+		if !allConcrete(args) {
+			logging.Debug("skipping instance with type parameters or unsupported types as arguments", "func", fn.FullName())
+			continue
+		}
+
+		res = append(res, Instance{
+			Key:      meta.FuncKey(fn.Pkg().Path(), meta.GetRecvName(fn), fn.Name()),
+			TypeArgs: args,
+		})
+	}
+
+	// instances are stored in map, so they are sorted for stable order of handlers
+	slices.SortFunc(res, func(a, b Instance) int {
+		return strings.Compare(a.Key+formatTypes(a.TypeArgs), b.Key+formatTypes(b.TypeArgs))
+	})
+	return res
+}
+
+func allConcrete(ts []types.Type) bool {
+	for _, t := range ts {
+		model, err := typing.NewType(t)
+		if err != nil || model.ContainsTypeParams() {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTypes(ts []types.Type) string {
+	res := make([]string, len(ts))
+	for i, t := range ts {
+		res[i] = t.String()
+	}
+	return strings.Join(res, ",")
+}
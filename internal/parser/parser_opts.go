@@ -1,6 +1,13 @@
 package parser
 
-import "github.com/d1vbyz3r0/typed/internal/parser/request"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
+)
 
 type ParseOpt func(p *parserOpts)
 
@@ -11,6 +18,8 @@ type parserOpts struct {
 	parseInlineQueryParams bool
 	parseInlineForms       bool
 	parseInlineHeaders     bool
+	// adapters holds full names of functions, adapting handlers with custom context to echo.HandlerFunc
+	adapters map[string]struct{}
 }
 
 func (o *parserOpts) RequestParseOpts() []request.ParseOpt {
@@ -70,3 +79,39 @@ func ParseInlineHeaders() ParseOpt {
 		p.parseInlineHeaders = true
 	}
 }
+
+// ParseAdaptedHandlers enables parsing of handlers with custom context, such as func(c *AppContext) error,
+// which are adapted to echo.HandlerFunc by provided functions. Adapters are full function names, such as
+// github.com/acme/app/web.Wrap, they are not parsed as wrapper handlers.
+func ParseAdaptedHandlers(adapters ...string) ParseOpt {
+	return func(p *parserOpts) {
+		if p.adapters == nil {
+			p.adapters = make(map[string]struct{}, len(adapters))
+		}
+
+		for _, adapter := range adapters {
+			p.adapters[adapter] = struct{}{}
+		}
+	}
+}
+
+// OptsKey returns string, describing combination of options. Results of parsing with the same options have equal keys,
+// so it's used as a part of cache key
+func OptsKey(opts ...ParseOpt) string {
+	o := new(parserOpts)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	adapters := slices.Sorted(maps.Keys(o.adapters))
+	return fmt.Sprintf(
+		"models=%t,enums=%t,path=%t,query=%t,forms=%t,headers=%t,adapters=%s",
+		o.parseAllModels,
+		o.parseEnums,
+		o.parseInlinePathParams,
+		o.parseInlineQueryParams,
+		o.parseInlineForms,
+		o.parseInlineHeaders,
+		strings.Join(adapters, ";"),
+	)
+}
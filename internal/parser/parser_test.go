@@ -1,11 +1,9 @@
 package parser
 
 import (
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"maps"
 	"net/http"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"testing"
@@ -20,108 +18,101 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestIsEchoHandler_NormalHandler(t *testing.T) {
-	src := `
-package test
+func TestParser_HandlerDetection(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "parser/detection")
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/parser/detection"
 
-func Handler(c echo.Context) error {
-	return nil
-}`
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, parser.AllErrors)
+	p, err := New()
 	require.NoError(t, err)
 
-	ast.Inspect(file, func(n ast.Node) bool {
-		decl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		ok = isEchoHandler(decl)
-		require.True(t, ok)
-		return false
-	})
-}
-
-func TestIsEchoHandler_StructMethod(t *testing.T) {
-	src := `
-package test
-type Handler struct{}
-
-func (h *Handler) Handler(c echo.Context) error {
-	return nil
-}`
-
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, parser.AllErrors)
+	res, err := p.Parse(pkg)
 	require.NoError(t, err)
 
-	ast.Inspect(file, func(n ast.Node) bool {
-		decl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		ok = isEchoHandler(decl)
-		require.True(t, ok)
-		return false
-	})
-}
-
-func TestIsWrapperFunction(t *testing.T) {
-	src := `
-package test
-
-func Handler() echo.HandlerFunc {
-	return func(c echo.Context) error {
-		return nil
+	result := []response.Response{{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(pkgPath, "Result")}}
+	tests := []struct {
+		name        string
+		doc         string
+		model       *typing.Type
+		responses   response.StatusCodeMapping
+		recv        string
+		ignored     bool
+		diagnostics []string
+	}{
+		{
+			name:      "Method",
+			doc:       "Method is a struct method handler",
+			recv:      "*Server",
+			responses: response.StatusCodeMapping{http.StatusNoContent: {{}}},
+		},
+		{
+			name:      "Aliased",
+			doc:       "Aliased uses aliased echo import and context alias",
+			model:     typing.Named(pkgPath, "Request"),
+			responses: response.StatusCodeMapping{http.StatusOK: result},
+		},
+		{
+			name:      "Wrapper",
+			doc:       "Wrapper returns alias of echo.HandlerFunc and responds with custom context",
+			responses: response.StatusCodeMapping{http.StatusCreated: result},
+		},
+		{
+			name: "Variable",
+			doc:  "Variable is a function literal handler",
+			responses: response.StatusCodeMapping{
+				http.StatusOK: {{ContentType: echo.MIMETextPlain, ModelType: typing.Basic("string")}},
+			},
+		},
+		{
+			name:      "FieldContext",
+			responses: response.StatusCodeMapping{http.StatusAccepted: result},
+		},
+		{
+			name:      "Hidden",
+			doc:       "Hidden is excluded from spec",
+			responses: response.StatusCodeMapping{http.StatusOK: {{}}},
+			ignored:   true,
+		},
+		{
+			name:      "MapRoutes.func2",
+			recv:      "*Router",
+			responses: response.StatusCodeMapping{http.StatusOK: result},
+		},
+		{
+			name:      "Skipped",
+			doc:       "Skipped has constructs, which can't be resolved statically",
+			responses: response.StatusCodeMapping{},
+			diagnostics: []string{
+				"detection.go:85:9: error: Skipped: response skipped, unresolved status code: resolve status code for String: unsupported expression type *ast.Ident. Expected BasicLit or SelectorExpr (TYP001)",
+			},
+		},
 	}
-}`
-
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, parser.AllErrors)
-	require.NoError(t, err)
-
-	ast.Inspect(file, func(n ast.Node) bool {
-		decl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		ok = isWrapperFunction(decl)
-		require.True(t, ok)
-		return false
-	})
-}
 
-func TestInlineHandlerDecl(t *testing.T) {
-	src := `
-package main
-
-func main() {
-	e := echo.New()
-	e.GET("/", func(c echo.Context) error {
-		return nil
-	})
-}`
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, parser.AllErrors)
-	require.NoError(t, err)
-
-	ast.Inspect(file, func(n ast.Node) bool {
-		decl, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
-		}
-
-		if decl.Name.Name == "main" {
-			return true
-		}
-
-		ok = isEchoHandler(decl)
-		require.True(t, ok)
-		return false
-	})
+	handlers := make(map[string]Handler)
+	for _, h := range res.Handlers {
+		handlers[h.Name] = h
+	}
+	require.Len(t, handlers, len(tests))
+	require.Len(t, res.Diagnostics, 1)
+	require.Equal(t, "Skipped", res.Diagnostics[0].Handler)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, ok := handlers[tt.name]
+			require.True(t, ok)
+			require.Equal(t, tt.doc, h.Doc)
+			require.Equal(t, tt.model, h.Request.ModelType)
+			require.Equal(t, tt.responses, h.Responses)
+			require.Equal(t, tt.recv, h.Recv)
+			require.Equal(t, tt.ignored, h.Ignored)
+
+			var diagnostics []string
+			for _, d := range h.Diagnostics {
+				d.Pos.Filename = filepath.Base(d.Pos.Filename)
+				diagnostics = append(diagnostics, d.String())
+			}
+			require.Equal(t, tt.diagnostics, diagnostics)
+		})
+	}
 }
 
 func TestParser(t *testing.T) {
@@ -150,8 +141,9 @@ func TestParser(t *testing.T) {
 					},
 					PathParams: []path.Param{
 						{
-							Name: "id",
-							Type: reflect.TypeFor[int64](),
+							Name:     "id",
+							Type:     reflect.TypeFor[int64](),
+							Inferred: true,
 						},
 					},
 					QueryParams: []query.Param{
@@ -160,8 +152,9 @@ func TestParser(t *testing.T) {
 							Type: reflect.TypeFor[string](),
 						},
 						{
-							Name: "json",
-							Type: reflect.TypeFor[bool](),
+							Name:     "json",
+							Type:     reflect.TypeFor[bool](),
+							Inferred: true,
 						},
 					},
 				},
@@ -208,7 +201,11 @@ func TestParser(t *testing.T) {
 					http.StatusInternalServerError: []response.Response{
 						{
 							ContentType: echo.MIMEApplicationJSON,
-							ModelType:   typing.Map(typing.Basic("string"), typing.Basic("string")),
+							Object: &response.InlineObject{
+								Properties: []response.InlineProperty{
+									{Name: "error", Required: true, Type: typing.Basic("string")},
+								},
+							},
 						},
 					},
 					http.StatusOK: []response.Response{
@@ -250,7 +247,6 @@ func TestParserAllModels(t *testing.T) {
 			typing.Named("github.com/d1vbyz3r0/typed/testdata/parser/allmodels", "User"),
 			typing.Enum(typing.Named("github.com/d1vbyz3r0/typed/testdata/parser/allmodels", "Role"), []any{"admin", "user"}),
 			typing.Basic("string"),
-			typing.Map(typing.Basic("string"), typing.Basic("string")),
 		},
 	}
 
@@ -294,3 +290,111 @@ func TestParser_SkipsGenericDeclaration(t *testing.T) {
 
 	require.ElementsMatch(t, want, slices.Collect(maps.Keys(got)))
 }
+
+func TestParser_GenericWrappers(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "parser/wrappers")
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/parser/wrappers"
+
+	p, err := New()
+	require.NoError(t, err)
+
+	res, err := p.Parse(pkg)
+	require.NoError(t, err)
+	require.Empty(t, res.Handlers)
+	require.Len(t, res.Generics, 1)
+	require.Equal(t, []string{"Req", "Resp"}, res.Generics[0].TypeParams)
+	require.Equal(t, typing.TypeParam("Req"), res.Generics[0].Request.ModelType)
+	require.Len(t, res.Instances, 2)
+
+	results := []Result{res}
+	InstantiateGenerics(results)
+
+	errResponse := []response.Response{
+		{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(pkgPath, "Error")},
+	}
+
+	tests := []struct {
+		key       string
+		model     *typing.Type
+		mapping   request.ContentTypeMapping
+		responses response.StatusCodeMapping
+	}{
+		{
+			key:     pkgPath + ".Handle[" + pkgPath + ".CreateUserRequest," + pkgPath + ".User]",
+			model:   typing.Named(pkgPath, "CreateUserRequest"),
+			mapping: request.ContentTypeMapping{echo.MIMEApplicationJSON: request.Body{}},
+			responses: response.StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(pkgPath, "User")},
+				},
+				http.StatusBadRequest:          errResponse,
+				http.StatusInternalServerError: errResponse,
+			},
+		},
+		{
+			key:     pkgPath + ".Handle[" + pkgPath + ".ListUsersRequest,[]" + pkgPath + ".User]",
+			model:   typing.Named(pkgPath, "ListUsersRequest"),
+			mapping: request.ContentTypeMapping{},
+			responses: response.StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Slice(typing.Named(pkgPath, "User"))},
+				},
+				http.StatusBadRequest:          errResponse,
+				http.StatusInternalServerError: errResponse,
+			},
+		},
+	}
+
+	handlers := make(map[string]Handler)
+	for _, h := range results[0].Handlers {
+		handlers[h.Key()] = h
+	}
+	require.Len(t, handlers, len(tests))
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			h, ok := handlers[tt.key]
+			require.True(t, ok)
+			require.False(t, h.IsGeneric())
+			require.Equal(t, tt.model, h.Request.ModelType)
+			require.Equal(t, tt.mapping, h.Request.ContentTypeMapping)
+			require.Equal(t, tt.responses, h.Responses)
+		})
+	}
+}
+
+func TestParser_AdaptedHandlers(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "parser/adapters")
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/parser/adapters"
+
+	p, err := New()
+	require.NoError(t, err)
+
+	res, err := p.Parse(pkg)
+	require.NoError(t, err)
+	names := make([]string, 0, len(res.Handlers))
+	for _, h := range res.Handlers {
+		names = append(names, h.Name)
+	}
+	// adapters are wrappers, returning echo.HandlerFunc, while custom context handlers are unknown
+	require.ElementsMatch(t, []string{"Wrap", "Named"}, names)
+
+	res, err = p.Parse(pkg, ParseAdaptedHandlers(pkgPath+".Wrap", pkgPath+".Named"))
+	require.NoError(t, err)
+
+	handlers := make(map[string]Handler)
+	for _, h := range res.Handlers {
+		handlers[h.Name] = h
+	}
+	require.Len(t, handlers, 2)
+
+	user := typing.Named(pkgPath, "User")
+	require.Equal(t, response.StatusCodeMapping{
+		http.StatusOK: {{ContentType: echo.MIMEApplicationJSON, ModelType: user}},
+	}, handlers["GetUser"].Responses)
+
+	require.Equal(t, typing.Named(pkgPath, "CreateUserRequest"), handlers["CreateUser"].Request.ModelType)
+	require.Equal(t, response.StatusCodeMapping{
+		http.StatusCreated: {{ContentType: echo.MIMEApplicationJSON, ModelType: user}},
+	}, handlers["CreateUser"].Responses)
+}
@@ -0,0 +1,256 @@
+package polymorph
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/logging"
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultDiscriminator is a property name used for discriminator, when implementations declare Type() string method,
+// but discriminator property name wasn't configured
+const DefaultDiscriminator = "type"
+
+const discriminatorMethod = "Type"
+
+type candidate struct {
+	named *types.Named
+	pkg   *packages.Package
+}
+
+// Resolver finds implementations of interfaces among named types declared in loaded packages
+type Resolver struct {
+	pkgs          map[string]*packages.Package
+	candidates    []candidate
+	discriminator string
+	diags         []diag.Diagnostic
+}
+
+// NewResolver creates resolver for provided packages.
+// discriminator is a discriminator property name, it can be empty.
+func NewResolver(pkgs []*packages.Package, discriminator string) *Resolver {
+	r := &Resolver{
+		pkgs:          make(map[string]*packages.Package, len(pkgs)),
+		discriminator: discriminator,
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+
+		r.pkgs[pkg.PkgPath] = pkg
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() || obj.IsAlias() {
+				continue
+			}
+
+			named, ok := obj.Type().(*types.Named)
+			if !ok || typing.IsInterface(named) || typing.HasTypeParams(named) {
+				continue
+			}
+
+			r.candidates = append(r.candidates, candidate{
+				named: named,
+				pkg:   pkg,
+			})
+		}
+	}
+
+	slices.SortFunc(r.candidates, func(a, b candidate) int {
+		ak := a.named.Obj().Pkg().Path() + "." + a.named.Obj().Name()
+		bk := b.named.Obj().Pkg().Path() + "." + b.named.Obj().Name()
+		if ak < bk {
+			return -1
+		} else if ak > bk {
+			return 1
+		}
+		return 0
+	})
+
+	return r
+}
+
+// Resolve returns interface type descriptor with all found implementations.
+// ok is false if interface declaration wasn't found in loaded packages or there are no implementations.
+func (r *Resolver) Resolve(t *typing.Type) (res *typing.Type, ok bool) {
+	if t == nil || t.Kind() != typing.TypeKindInterface {
+		return nil, false
+	}
+
+	pkg, ok := r.pkgs[t.Pkg()]
+	if !ok {
+		logging.Debug("interface package not loaded, skipping", logging.Pkg(t.Pkg()), "name", t.Name())
+		return nil, false
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(t.Name()).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		impls    []typing.Implementation
+		matched  []candidate
+		hasValue bool
+	)
+	for _, c := range r.candidates {
+		if !types.Implements(c.named, iface) && !types.Implements(types.NewPointer(c.named), iface) {
+			continue
+		}
+
+		implType, err := typing.NewType(c.named)
+		if err != nil {
+			logging.Warn("failed to create implementation type", "interface", t, "type", c.named, "error", err)
+			continue
+		}
+
+		value, found := discriminatorValue(c)
+		hasValue = hasValue || found
+		impls = append(impls, typing.Implementation{
+			Type:               implType,
+			DiscriminatorValue: value,
+		})
+		matched = append(matched, c)
+	}
+
+	if len(impls) == 0 {
+		logging.Debug("no implementations found for interface", logging.Pkg(t.Pkg()), "name", t.Name())
+		return nil, false
+	}
+
+	discriminator := r.discriminator
+	if discriminator == "" && hasValue && r.hasDiscriminatorProperty(t, matched) {
+		discriminator = DefaultDiscriminator
+	}
+
+	logging.Debug("resolved interface implementations", "interface", t, "count", len(impls), "discriminator", discriminator)
+	return typing.Interface(typing.Named(t.Pkg(), t.Name()), discriminator, impls...), true
+}
+
+// Diagnostics returns diagnostics of resolved interfaces, such as implementations without discriminator property
+func (r *Resolver) Diagnostics() []diag.Diagnostic {
+	return r.diags
+}
+
+// hasDiscriminatorProperty reports if all implementations have required property of default discriminator.
+// Otherwise, discriminator can't be used to select implementation, so it's omitted and diagnostic is recorded
+func (r *Resolver) hasDiscriminatorProperty(t *typing.Type, impls []candidate) bool {
+	for _, c := range impls {
+		if hasRequiredProperty(c.named, DefaultDiscriminator) {
+			continue
+		}
+
+		diags := diag.NewCollector(c.pkg.Fset, "")
+		diags.Addf(c.named.Obj().Pos(), diag.MissingDiscriminatorProperty, "%s implements %s, but has no required %q property, discriminator is omitted", c.named.Obj().Name(), t, DefaultDiscriminator)
+		r.diags = append(r.diags, diags.Diagnostics()...)
+		return false
+	}
+	return true
+}
+
+// hasRequiredProperty reports if struct declares exported non-nullable string field, which json name is name.
+// Fields with omitempty option can be missing in payload, so they are not required
+func hasRequiredProperty(named *types.Named, name string) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		if !f.Exported() || f.Embedded() {
+			continue
+		}
+
+		tag, hasTag := reflect.StructTag(st.Tag(i)).Lookup("json")
+		fieldName, opts, _ := strings.Cut(tag, ",")
+		if !hasTag || fieldName == "" {
+			fieldName = f.Name()
+		}
+
+		if fieldName != name || slices.Contains(strings.Split(opts, ","), "omitempty") {
+			continue
+		}
+
+		basic, ok := f.Type().Underlying().(*types.Basic)
+		return ok && basic.Info()&types.IsString != 0
+	}
+	return false
+}
+
+// discriminatorValue returns constant, returned from Type() string method of candidate.
+// Method must consist of single return statement with constant expression.
+func discriminatorValue(c candidate) (string, bool) {
+	sel := types.NewMethodSet(types.NewPointer(c.named)).Lookup(c.named.Obj().Pkg(), discriminatorMethod)
+	if sel == nil {
+		return "", false
+	}
+
+	fn, ok := sel.Obj().(*types.Func)
+	if !ok {
+		return "", false
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return "", false
+	}
+
+	basic, ok := sig.Results().At(0).Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsString == 0 {
+		return "", false
+	}
+
+	decl := findFuncDecl(c.pkg, fn)
+	if decl == nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return "", false
+	}
+
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+
+	tv, ok := c.pkg.TypesInfo.Types[ret.Results[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		logging.Debug("discriminator method doesn't return constant", "type", c.named)
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+func findFuncDecl(pkg *packages.Package, fn *types.Func) *ast.FuncDecl {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			if pkg.TypesInfo.Defs[funcDecl.Name] == fn {
+				return funcDecl
+			}
+		}
+	}
+	return nil
+}
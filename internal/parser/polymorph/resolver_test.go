@@ -0,0 +1,128 @@
+package polymorph
+
+import (
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+const fixturePkg = "github.com/d1vbyz3r0/typed/testdata/polymorph"
+
+func TestResolver_Resolve(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "polymorph")
+
+	tests := []struct {
+		name          string
+		t             *typing.Type
+		discriminator string
+		wantOk        bool
+		want          *typing.Type
+	}{
+		{
+			name:   "implementations with Type method use default discriminator",
+			t:      typing.Interface(typing.Named(fixturePkg, "Event"), ""),
+			wantOk: true,
+			want: typing.Interface(
+				typing.Named(fixturePkg, "Event"),
+				DefaultDiscriminator,
+				typing.Implementation{Type: typing.Named(fixturePkg, "Created"), DiscriminatorValue: "created"},
+				typing.Implementation{Type: typing.Named(fixturePkg, "Deleted"), DiscriminatorValue: "deleted"},
+				typing.Implementation{Type: typing.Named(fixturePkg, "Unknown")},
+			),
+		},
+		{
+			name:          "configured discriminator",
+			t:             typing.Interface(typing.Named(fixturePkg, "Event"), ""),
+			discriminator: "kind",
+			wantOk:        true,
+			want: typing.Interface(
+				typing.Named(fixturePkg, "Event"),
+				"kind",
+				typing.Implementation{Type: typing.Named(fixturePkg, "Created"), DiscriminatorValue: "created"},
+				typing.Implementation{Type: typing.Named(fixturePkg, "Deleted"), DiscriminatorValue: "deleted"},
+				typing.Implementation{Type: typing.Named(fixturePkg, "Unknown")},
+			),
+		},
+		{
+			name:   "interface without implementations",
+			t:      typing.Interface(typing.Named(fixturePkg, "Shape"), ""),
+			wantOk: false,
+		},
+		{
+			name:   "interface from not loaded package",
+			t:      typing.Interface(typing.Named("github.com/acme/events", "Event"), ""),
+			wantOk: false,
+		},
+		{
+			name:   "not an interface",
+			t:      typing.Named(fixturePkg, "Created"),
+			wantOk: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewResolver([]*packages.Package{pkg}, tc.discriminator)
+			got, ok := r.Resolve(tc.t)
+			require.Equal(t, tc.wantOk, ok)
+			if !tc.wantOk {
+				return
+			}
+
+			require.Equal(t, tc.want.Discriminator(), got.Discriminator())
+			require.Equal(t, len(tc.want.Implementations()), len(got.Implementations()))
+			for i, impl := range tc.want.Implementations() {
+				require.Equal(t, impl.Type.String(), got.Implementations()[i].Type.String())
+				require.Equal(t, impl.DiscriminatorValue, got.Implementations()[i].DiscriminatorValue)
+			}
+		})
+	}
+}
+
+func TestResolver_MissingDiscriminatorProperty(t *testing.T) {
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/polymorph/notifications"
+	pkg := testsuite.LoadFixturePackage(t, "polymorph/notifications")
+	r := NewResolver([]*packages.Package{pkg}, "")
+
+	got, ok := r.Resolve(typing.Interface(typing.Named(pkgPath, "Notification"), ""))
+	require.True(t, ok)
+	require.Empty(t, got.Discriminator())
+	require.Len(t, got.Implementations(), 3)
+	require.Equal(t, "push", got.Implementations()[1].DiscriminatorValue)
+
+	diags := r.Diagnostics()
+	require.Len(t, diags, 1)
+	require.Equal(t, diag.MissingDiscriminatorProperty, diags[0].Code)
+	require.Equal(t, "notifications.go", filepath.Base(diags[0].Pos.Filename))
+	require.Contains(t, diags[0].Message, "Push implements")
+
+	got, ok = NewResolver([]*packages.Package{pkg}, "kind").Resolve(typing.Interface(typing.Named(pkgPath, "Notification"), ""))
+	require.True(t, ok)
+	require.Equal(t, "kind", got.Discriminator(), "configured discriminator isn't checked")
+}
+
+func TestHasRequiredProperty(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "polymorph/notifications")
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Email", want: true},
+		{name: "Push", want: false},
+		{name: "SMS", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			named := pkg.Types.Scope().Lookup(tc.name).Type().(*types.Named)
+			require.Equal(t, tc.want, hasRequiredProperty(named, DefaultDiscriminator))
+		})
+	}
+}
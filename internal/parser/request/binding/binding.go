@@ -113,8 +113,8 @@ func HasFiles(s *types.Struct) bool {
 	return false
 }
 
-func IsBindCall(call *ast.CallExpr) bool {
-	if !calls.IsEchoContextMethodCall(call) {
+func IsBindCall(call *ast.CallExpr, info *types.Info) bool {
+	if !calls.IsEchoContextMethodCall(call, info) {
 		return false
 	}
 
@@ -3,6 +3,7 @@ package form
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
 	"mime/multipart"
 	"reflect"
 	"strconv"
@@ -10,13 +11,14 @@ import (
 
 	"github.com/d1vbyz3r0/typed/common/meta"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/logging"
 )
 
 var multipartType = reflect.TypeOf(new(multipart.FileHeader))
 
 // NewInlineForm builds reflect.Struct from inline form usages and reports if form contains files and any fields found
-func NewInlineForm(funcDecl *ast.FuncDecl) (form reflect.Type, hasFiles bool, found bool) {
+func NewInlineForm(funcDecl *ast.FuncDecl, diags *diag.Collector) (form reflect.Type, hasFiles bool, found bool) {
 	fields := make([]reflect.StructField, 0)
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
@@ -33,7 +35,7 @@ func NewInlineForm(funcDecl *ast.FuncDecl) (form reflect.Type, hasFiles bool, fo
 		case "FormFile":
 			lit, ok := call.Args[0].(*ast.BasicLit)
 			if !ok {
-				logging.Debug("skipping non BasicLit value")
+				diags.Addf(call.Args[0].Pos(), diag.NonLiteralParamName, "form field name is not a string literal: %s", types.ExprString(call.Args[0]))
 				return true
 			}
 
@@ -54,7 +56,7 @@ func NewInlineForm(funcDecl *ast.FuncDecl) (form reflect.Type, hasFiles bool, fo
 		case "FormValue":
 			lit, ok := call.Args[0].(*ast.BasicLit)
 			if !ok {
-				logging.Debug("skipping non BasicLit value")
+				diags.Addf(call.Args[0].Pos(), diag.NonLiteralParamName, "form field name is not a string literal: %s", types.ExprString(call.Args[0]))
 				return true
 			}
 
@@ -52,7 +52,7 @@ func Handler(c echo.Context) error {
 			return true
 		}
 
-		got, hasFiles, ok := NewInlineForm(decl)
+		got, hasFiles, ok := NewInlineForm(decl, nil)
 		require.Equal(t, false, hasFiles)
 
 		require.True(t, ok)
@@ -127,7 +127,7 @@ func Handler(c echo.Context) error {
 			return true
 		}
 
-		got, hasFiles, ok := NewInlineForm(decl)
+		got, hasFiles, ok := NewInlineForm(decl, nil)
 		require.Equal(t, true, hasFiles)
 
 		require.True(t, ok)
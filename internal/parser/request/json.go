@@ -0,0 +1,43 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+)
+
+type bodyJSON struct {
+	Form json.RawMessage `json:"form,omitempty"`
+}
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if b.Form == nil {
+		return json.Marshal(bodyJSON{})
+	}
+
+	form, err := typing.MarshalReflectType(b.Form)
+	if err != nil {
+		return nil, fmt.Errorf("marshal inline form: %w", err)
+	}
+	return json.Marshal(bodyJSON{Form: form})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var v bodyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*b = Body{}
+	if v.Form == nil {
+		return nil
+	}
+
+	form, err := typing.UnmarshalReflectType(v.Form)
+	if err != nil {
+		return fmt.Errorf("unmarshal inline form: %w", err)
+	}
+	b.Form = form
+	return nil
+}
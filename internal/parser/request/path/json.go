@@ -0,0 +1,37 @@
+package path
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+)
+
+type paramJSON struct {
+	Name     string          `json:"name"`
+	Type     json.RawMessage `json:"type"`
+	Inferred bool            `json:"inferred,omitempty"`
+}
+
+func (p Param) MarshalJSON() ([]byte, error) {
+	t, err := typing.MarshalReflectType(p.Type)
+	if err != nil {
+		return nil, fmt.Errorf("marshal param %s type: %w", p.Name, err)
+	}
+	return json.Marshal(paramJSON{Name: p.Name, Type: t, Inferred: p.Inferred})
+}
+
+func (p *Param) UnmarshalJSON(data []byte) error {
+	var v paramJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	t, err := typing.UnmarshalReflectType(v.Type)
+	if err != nil {
+		return fmt.Errorf("unmarshal param %s type: %w", v.Name, err)
+	}
+
+	*p = Param{Name: v.Name, Type: t, Inferred: v.Inferred}
+	return nil
+}
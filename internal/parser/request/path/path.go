@@ -3,20 +3,24 @@ package path
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
 	"reflect"
 	"strconv"
 
 	"github.com/d1vbyz3r0/typed/common/meta"
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/logging"
 )
 
 type Param struct {
 	Name string
 	Type reflect.Type
+	// Inferred is set, when type is inferred from param usage or struct field. Otherwise, type defaults to string
+	Inferred bool
 }
 
-func NewInlinePathParams(funcDecl *ast.FuncDecl) []Param {
+func NewInlinePathParams(funcDecl *ast.FuncDecl, diags *diag.Collector) []Param {
 	var params []Param
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
@@ -35,12 +39,13 @@ func NewInlinePathParams(funcDecl *ast.FuncDecl) []Param {
 
 		lit, ok := call.Args[0].(*ast.BasicLit)
 		if !ok {
-			logging.Debug("skipping non BasicLit value")
+			diags.Addf(call.Args[0].Pos(), diag.NonLiteralParamName, "path param name is not a string literal: %s", types.ExprString(call.Args[0]))
 			return true
 		}
 
 		paramName, _ := strconv.Unquote(lit.Value)
 		paramType := reflect.TypeOf("")
+		inferred := false
 
 		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 			call, ok := n.(*ast.CallExpr)
@@ -67,6 +72,7 @@ func NewInlinePathParams(funcDecl *ast.FuncDecl) []Param {
 				}
 
 				paramType = t
+				inferred = true
 				return false
 			}
 
@@ -74,8 +80,9 @@ func NewInlinePathParams(funcDecl *ast.FuncDecl) []Param {
 		})
 
 		params = append(params, Param{
-			Name: paramName,
-			Type: paramType,
+			Name:     paramName,
+			Type:     paramType,
+			Inferred: inferred,
 		})
 
 		logging.Debug(
@@ -105,8 +112,9 @@ func NewStructPathParams(s reflect.Type) ([]Param, error) {
 		}
 
 		params = append(params, Param{
-			Name: tag,
-			Type: field.Type,
+			Name:     tag,
+			Type:     field.Type,
+			Inferred: true,
 		})
 
 		logging.Debug(
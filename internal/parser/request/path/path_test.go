@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -18,12 +19,14 @@ func Test_NewInlinePathParams(t *testing.T) {
 			Type: reflect.TypeOf(""),
 		},
 		{
-			Name: "p2",
-			Type: reflect.TypeOf(int64(0)),
+			Name:     "p2",
+			Type:     reflect.TypeOf(int64(0)),
+			Inferred: true,
 		},
 		{
-			Name: "p3",
-			Type: reflect.TypeOf(uuid.UUID{}),
+			Name:     "p3",
+			Type:     reflect.TypeOf(uuid.UUID{}),
+			Inferred: true,
 		},
 	}
 
@@ -47,13 +50,34 @@ func Handler(c echo.Context) error {
 			return true
 		}
 
-		got := NewInlinePathParams(decl)
+		got := NewInlinePathParams(decl, nil)
 		require.ElementsMatch(t, want, got)
 
 		return true
 	})
 }
 
+func Test_NewInlinePathParamsNonLiteralName(t *testing.T) {
+	src := `
+package test
+
+func Handler(c echo.Context) error {
+	name := "id"
+	id := c.Param(name)
+}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handler.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	diags := diag.NewCollector(fset, "Handler")
+	got := NewInlinePathParams(file.Decls[0].(*ast.FuncDecl), diags)
+	require.Empty(t, got)
+	require.Len(t, diags.Diagnostics(), 1)
+	require.Equal(t, "handler.go:6:16: warning: Handler: path param name is not a string literal: name (TYP004)", diags.Diagnostics()[0].String())
+}
+
 func Test_NewStructPathParams(t *testing.T) {
 	type Struct struct {
 		Name string `param:"name"`
@@ -65,12 +89,14 @@ func Test_NewStructPathParams(t *testing.T) {
 
 	want := []Param{
 		{
-			Name: "name",
-			Type: reflect.TypeOf(""),
+			Name:     "name",
+			Type:     reflect.TypeOf(""),
+			Inferred: true,
 		},
 		{
-			Name: "age",
-			Type: reflect.TypeOf(int(0)),
+			Name:     "age",
+			Type:     reflect.TypeOf(int(0)),
+			Inferred: true,
 		},
 	}
 
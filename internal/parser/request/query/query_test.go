@@ -18,16 +18,19 @@ func Test_NewInlineQuery(t *testing.T) {
 			Type: reflect.TypeOf(""),
 		},
 		{
-			Name: "q2",
-			Type: reflect.TypeOf(int64(0)),
+			Name:     "q2",
+			Type:     reflect.TypeOf(int64(0)),
+			Inferred: true,
 		},
 		{
-			Name: "q3",
-			Type: reflect.TypeOf(uuid.UUID{}),
+			Name:     "q3",
+			Type:     reflect.TypeOf(uuid.UUID{}),
+			Inferred: true,
 		},
 		{
-			Name: "q4",
-			Type: reflect.TypeOf(false),
+			Name:     "q4",
+			Type:     reflect.TypeOf(false),
+			Inferred: true,
 		},
 	}
 
@@ -52,7 +55,7 @@ func Handler(c echo.Context) error {
 			return true
 		}
 
-		got := NewInlineQueryParams(decl)
+		got := NewInlineQueryParams(decl, nil)
 		require.ElementsMatch(t, want, got)
 		return true
 	})
@@ -69,12 +72,14 @@ func Test_NewStructQueryParams(t *testing.T) {
 
 	want := []Param{
 		{
-			Name: "name",
-			Type: reflect.TypeOf(""),
+			Name:     "name",
+			Type:     reflect.TypeOf(""),
+			Inferred: true,
 		},
 		{
-			Name: "age",
-			Type: reflect.TypeOf(int(0)),
+			Name:     "age",
+			Type:     reflect.TypeOf(int(0)),
+			Inferred: true,
 		},
 	}
 
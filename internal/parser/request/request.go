@@ -1,11 +1,15 @@
 package request
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"maps"
 	"reflect"
 
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/internal/parser/headers"
 	"github.com/d1vbyz3r0/typed/internal/parser/request/binding"
 	"github.com/d1vbyz3r0/typed/internal/parser/request/form"
@@ -42,7 +46,44 @@ type Request struct {
 	Headers            []headers.Header
 }
 
-func New(funcDecl *ast.FuncDecl, info *types.Info, opts ...ParseOpt) *Request {
+// Instantiate returns copy of request with type parameters replaced by type arguments of generic wrapper instance.
+// If bind model is a type parameter, content types are resolved from type argument
+func (r *Request) Instantiate(args map[string]types.Type) (*Request, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	res := *r
+	if !r.ModelType.ContainsTypeParams() {
+		return &res, nil
+	}
+
+	if r.ModelType.Kind() == typing.TypeKindTypeParam {
+		arg, ok := args[r.ModelType.Name()]
+		if !ok {
+			return &res, nil
+		}
+
+		res.ModelType = nil
+		res.ContentTypeMapping = maps.Clone(r.ContentTypeMapping)
+		res.bind(types.NewPointer(arg), nil, token.NoPos)
+		return &res, nil
+	}
+
+	typeArgs := make(map[string]*typing.Type, len(args))
+	for name, arg := range args {
+		t, err := typing.NewType(arg)
+		if err != nil {
+			return nil, fmt.Errorf("type argument %s: %w", name, err)
+		}
+		typeArgs[name] = t
+	}
+
+	res.ModelType = r.ModelType.Substitute(typeArgs)
+	return &res, nil
+}
+
+func New(funcDecl *ast.FuncDecl, info *types.Info, diags *diag.Collector, opts ...ParseOpt) *Request {
 	parseOpts := new(requestParseOpts)
 	for _, opt := range opts {
 		opt(parseOpts)
@@ -56,15 +97,15 @@ func New(funcDecl *ast.FuncDecl, info *types.Info, opts ...ParseOpt) *Request {
 	}
 
 	if parseOpts.parseInlinePathParams {
-		r.PathParams = path.NewInlinePathParams(funcDecl)
+		r.PathParams = path.NewInlinePathParams(funcDecl, diags)
 	}
 
 	if parseOpts.parseInlineQueryParams {
-		r.QueryParams = query.NewInlineQueryParams(funcDecl)
+		r.QueryParams = query.NewInlineQueryParams(funcDecl, diags)
 	}
 
 	if parseOpts.parseInlineForms {
-		f, hasFiles, found := form.NewInlineForm(funcDecl)
+		f, hasFiles, found := form.NewInlineForm(funcDecl, diags)
 		if found {
 			if !hasFiles {
 				// if form doesn't contain files, content-type can be both application/x-www-form-urlencoded and multipart/form-data
@@ -80,7 +121,7 @@ func New(funcDecl *ast.FuncDecl, info *types.Info, opts ...ParseOpt) *Request {
 	}
 
 	if parseOpts.parseInlineHeaders {
-		r.Headers = headers.NewInlineRequestHeaders(funcDecl, info)
+		r.Headers = headers.NewInlineRequestHeaders(funcDecl, info, diags)
 	}
 
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
@@ -89,54 +130,65 @@ func New(funcDecl *ast.FuncDecl, info *types.Info, opts ...ParseOpt) *Request {
 			return true
 		}
 
-		if !binding.IsBindCall(call) || len(call.Args) != 1 {
+		if !binding.IsBindCall(call, info) || len(call.Args) != 1 {
 			return true
 		}
 
-		argType := info.TypeOf(call.Args[0])
-		named, ok := typing.GetUnderlyingNamedType(argType)
-		if !ok {
-			logging.Error("failed to get underlying named type", "arg_type", argType)
-			return true
-		}
+		r.bind(info.TypeOf(call.Args[0]), diags, call.Args[0].Pos())
+		return true
+	})
 
-		s, ok := typing.GetUnderlyingStruct(argType)
-		if !ok {
-			logging.Error("expected struct as bind arg", "got", argType)
-			return true
-		}
+	return r
+}
 
-		if s.NumFields() == 0 {
-			logging.Debug("ignoring empty struct", "struct", named)
-			return true
+// bind sets model type and content types of request body from c.Bind() argument type at pos.
+// Type parameter of generic wrapper is kept as model type, content types are resolved on instantiation
+func (r *Request) bind(argType types.Type, diags *diag.Collector, pos token.Pos) {
+	if ptr, ok := argType.(*types.Pointer); ok {
+		if tp, ok := ptr.Elem().(*types.TypeParam); ok {
+			r.ModelType = typing.TypeParam(tp.Obj().Name())
+			return
 		}
+	}
 
-		modelType, err := typing.NewType(named)
-		if err != nil {
-			logging.Error("failed to build typing.Type", "type", named, "err", err)
-			return true
-		}
+	named, ok := typing.GetUnderlyingNamedType(argType)
+	if !ok {
+		diags.Addf(pos, diag.UnsupportedBindType, "bind argument of type %s isn't a named type", argType)
+		return
+	}
 
-		r.ModelType = modelType
+	s, ok := typing.GetUnderlyingStruct(argType)
+	if !ok {
+		diags.Addf(pos, diag.UnsupportedBindType, "bind argument of type %s isn't a struct", argType)
+		return
+	}
 
-		hasUntagged := binding.HasAtLeastOneFieldWithoutBindingTag(s, bodyBindingTags, paramBindingTags)
-		if binding.HasTag(s, "form") {
-			if !binding.HasFiles(s) {
-				r.ContentTypeMapping[echo.MIMEApplicationForm] = Body{}
-			}
-			r.ContentTypeMapping[echo.MIMEMultipartForm] = Body{}
-		}
+	if s.NumFields() == 0 {
+		logging.Debug("ignoring empty struct", "struct", named)
+		return
+	}
 
-		if binding.HasTag(s, "json") || hasUntagged {
-			r.ContentTypeMapping[echo.MIMEApplicationJSON] = Body{}
-		}
+	modelType, err := typing.NewType(named)
+	if err != nil {
+		diags.Addf(pos, diag.UnsupportedBindType, "unsupported bind argument type %s: %v", named, err)
+		return
+	}
+
+	r.ModelType = modelType
 
-		if binding.HasTag(s, "xml") || hasUntagged {
-			r.ContentTypeMapping[echo.MIMEApplicationXML] = Body{}
+	hasUntagged := binding.HasAtLeastOneFieldWithoutBindingTag(s, bodyBindingTags, paramBindingTags)
+	if binding.HasTag(s, "form") {
+		if !binding.HasFiles(s) {
+			r.ContentTypeMapping[echo.MIMEApplicationForm] = Body{}
 		}
+		r.ContentTypeMapping[echo.MIMEMultipartForm] = Body{}
+	}
 
-		return true
-	})
+	if binding.HasTag(s, "json") || hasUntagged {
+		r.ContentTypeMapping[echo.MIMEApplicationJSON] = Body{}
+	}
 
-	return r
+	if binding.HasTag(s, "xml") || hasUntagged {
+		r.ContentTypeMapping[echo.MIMEApplicationXML] = Body{}
+	}
 }
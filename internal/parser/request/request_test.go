@@ -15,7 +15,7 @@ func requestFromFixture(t *testing.T, fixture string) *Request {
 	t.Helper()
 
 	pkg, fn := testsuite.LoadFixtureFunc(t, "request/"+fixture, "Handler")
-	return New(fn, pkg.TypesInfo, ParseInlineForms(), ParseInlinePathParams(), ParseInlineQueryParams())
+	return New(fn, pkg.TypesInfo, nil, ParseInlineForms(), ParseInlinePathParams(), ParseInlineQueryParams())
 }
 
 func TestNewRequest_JSON(t *testing.T) {
@@ -50,6 +50,7 @@ var supportedFuns = []string{
 var (
 	rawBodyFuncs = []string{jsonBlobContextFunc, xmlBlobContextFunc, streamContextFunc}
 	noBodyFuncs  = []string{redirectContextFunc, noContentContextFunc}
+	objectFuncs  = []string{jsonContextFunc, jsonPrettyContextFunc, xmlContextFunc, xmlPrettyContextFunc}
 )
 
 func newContextResponseType(
@@ -58,7 +59,7 @@ func newContextResponseType(
 	mr *mime.Resolver,
 	typesInfo *types.Info,
 ) (t ContextResponseType, supported bool) {
-	if !calls.IsEchoContextMethodCall(call) {
+	if !calls.IsEchoContextMethodCall(call, typesInfo) {
 		return ContextResponseType{}, false
 	}
 
@@ -161,3 +162,12 @@ func (t ContextResponseType) ModelType() (*typing.Type, error) {
 	}
 	return typing.NewType(t.types.TypeOf(t.call.Args[1]))
 }
+
+// InlineObject returns object synthesized from map literal or anonymous struct, passed to JSON or XML response funcs.
+// ok is false if model isn't an inline object
+func (t ContextResponseType) InlineObject() (obj *InlineObject, ok bool) {
+	if !slices.Contains(objectFuncs, t.funcName) {
+		return nil, false
+	}
+	return newInlineObject(t.call.Args[1], t.types)
+}
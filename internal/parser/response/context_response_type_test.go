@@ -2,13 +2,12 @@ package response
 
 import (
 	"go/ast"
-	"go/parser"
-	"go/token"
 	"net/http"
 	"testing"
 
 	"github.com/d1vbyz3r0/typed/internal/parser/response/codes"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/mime"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,6 +36,12 @@ func TestContextResponseType_ContentType(t *testing.T) {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSON(http.StatusOK, nil)
 }`,
@@ -52,6 +57,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSONPretty(http.StatusOK, nil, "")
 }`,
@@ -67,6 +78,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSONBlob(http.StatusOK, nil)
 }`,
@@ -82,6 +99,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XML(http.StatusOK, nil)
 }`,
@@ -97,6 +120,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XMLPretty(http.StatusOK, nil, "")
 }`,
@@ -112,6 +141,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XMLBlob(http.StatusOK, nil)
 }`,
@@ -127,6 +162,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.String(http.StatusOK, "")
 }`,
@@ -142,6 +183,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Blob(http.StatusOK, "application/json", nil)
 }`,
@@ -157,6 +204,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Redirect(301, "/some")
 }`,
@@ -172,6 +225,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }`,
@@ -187,6 +246,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Stream(http.StatusOK, "application/json", nil)
 }`,
@@ -200,16 +265,14 @@ func Handler(c echo.Context) error {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fset := token.NewFileSet()
-			file, err := parser.ParseFile(fset, "", tt.fields.src, parser.AllErrors)
-			require.NoError(t, err)
+			file, info := testsuite.CheckSource(t, tt.fields.src)
 			ast.Inspect(file, func(n ast.Node) bool {
 				call, ok := n.(*ast.CallExpr)
 				if !ok {
 					return true
 				}
 
-				ct, supported := newContextResponseType(call, cr, mr, nil)
+				ct, supported := newContextResponseType(call, cr, mr, info)
 				if !supported {
 					return true
 				}
@@ -248,6 +311,12 @@ func TestContextResponseType_StatusCode(t *testing.T) {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSON(http.StatusOK, nil)
 }`,
@@ -263,6 +332,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSONPretty(http.StatusOK, nil, "")
 }`,
@@ -278,6 +353,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.JSONBlob(http.StatusOK, nil)
 }`,
@@ -293,6 +374,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XML(http.StatusOK, nil)
 }`,
@@ -308,6 +395,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XMLPretty(http.StatusOK, nil, "")
 }`,
@@ -323,6 +416,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.XMLBlob(http.StatusOK, nil)
 }`,
@@ -338,6 +437,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.String(http.StatusOK, "")
 }`,
@@ -353,6 +458,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Blob(http.StatusOK, "application/json", nil)
 }`,
@@ -368,6 +479,13 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Redirect(http.StatusFound, "/json/"+uuid.New().String())
 }`,
@@ -383,6 +501,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }`,
@@ -398,6 +522,12 @@ func Handler(c echo.Context) error {
 				src: `
 package test
 
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
 func Handler(c echo.Context) error {
 	return c.Stream(http.StatusOK, "application/json", nil)
 }`,
@@ -411,15 +541,13 @@ func Handler(c echo.Context) error {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fset := token.NewFileSet()
-			file, err := parser.ParseFile(fset, "", tt.fields.src, parser.AllErrors)
-			require.NoError(t, err)
+			file, info := testsuite.CheckSource(t, tt.fields.src)
 			ast.Inspect(file, func(n ast.Node) bool {
 				call, ok := n.(*ast.CallExpr)
 				if !ok {
 					return true
 				}
-				ct, supported := newContextResponseType(call, cr, mr, nil)
+				ct, supported := newContextResponseType(call, cr, mr, info)
 				if !supported {
 					return true
 				}
@@ -0,0 +1,172 @@
+package response
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+// InlineObject is an object synthesized from map literal with constant string keys, such as echo.Map{"name": name},
+// or from anonymous struct
+type InlineObject struct {
+	Properties []InlineProperty
+}
+
+type InlineProperty struct {
+	Name     string
+	Required bool
+	// Type is a property type. It's nil for nested inline objects and values of unsupported types,
+	// which are described as free-form values
+	Type *typing.Type
+	// Object is set for nested inline objects
+	Object *InlineObject
+}
+
+// Types returns types of properties, including properties of nested objects
+func (o *InlineObject) Types() []*typing.Type {
+	var res []*typing.Type
+	for _, p := range o.Properties {
+		if p.Type != nil {
+			res = append(res, p.Type)
+		}
+
+		if p.Object != nil {
+			res = append(res, p.Object.Types()...)
+		}
+	}
+	return res
+}
+
+// Substitute returns copy of object with type parameters in property types replaced by args
+func (o *InlineObject) Substitute(args map[string]*typing.Type) *InlineObject {
+	if o == nil {
+		return nil
+	}
+
+	res := &InlineObject{Properties: make([]InlineProperty, len(o.Properties))}
+	for i, p := range o.Properties {
+		p.Type = p.Type.Substitute(args)
+		p.Object = p.Object.Substitute(args)
+		res.Properties[i] = p
+	}
+	return res
+}
+
+// newInlineObject synthesizes object from response expression, ok is false if expression can't be described as object
+func newInlineObject(expr ast.Expr, typesInfo *types.Info) (*InlineObject, bool) {
+	expr = ast.Unparen(expr)
+	t := typesInfo.TypeOf(expr)
+	if t == nil {
+		return nil, false
+	}
+
+	if s, ok := types.Unalias(t).(*types.Struct); ok {
+		return newStructObject(s), true
+	}
+
+	// properties of empty map can't be inferred, so it's kept as free-form map
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || len(lit.Elts) == 0 {
+		return nil, false
+	}
+
+	m, ok := t.Underlying().(*types.Map)
+	if !ok {
+		return nil, false
+	}
+
+	if key, ok := m.Key().Underlying().(*types.Basic); !ok || key.Info()&types.IsString == 0 {
+		return nil, false
+	}
+
+	obj := &InlineObject{
+		Properties: make([]InlineProperty, 0, len(lit.Elts)),
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+
+		tv, ok := typesInfo.Types[kv.Key]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			logging.Debug("map literal key is not a constant, keeping map", "key", types.ExprString(kv.Key))
+			return nil, false
+		}
+
+		prop := InlineProperty{
+			Name:     constant.StringVal(tv.Value),
+			Required: true,
+		}
+		if nested, ok := newInlineObject(kv.Value, typesInfo); ok {
+			prop.Object = nested
+		} else {
+			prop.Type = propertyType(typesInfo.TypeOf(kv.Value))
+		}
+		obj.Properties = append(obj.Properties, prop)
+	}
+
+	return obj, true
+}
+
+// newStructObject describes anonymous struct fields, following encoding/json naming rules
+func newStructObject(s *types.Struct) *InlineObject {
+	obj := &InlineObject{
+		Properties: make([]InlineProperty, 0, s.NumFields()),
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		tag := reflect.StructTag(s.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name()
+		}
+
+		prop := InlineProperty{
+			Name:     name,
+			Required: !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero"),
+		}
+		if nested, ok := types.Unalias(f.Type()).(*types.Struct); ok {
+			prop.Object = newStructObject(nested)
+		} else {
+			prop.Type = propertyType(f.Type())
+		}
+		obj.Properties = append(obj.Properties, prop)
+	}
+
+	return obj
+}
+
+// propertyType returns type descriptor of value, untyped constants are converted to default types.
+// It returns nil for nil values and unsupported types
+func propertyType(t types.Type) *typing.Type {
+	if t == nil {
+		return nil
+	}
+
+	t = types.Default(t)
+	if b, ok := t.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		return nil
+	}
+
+	res, err := typing.NewType(t)
+	if err != nil {
+		logging.Debug("unsupported inline object property type, using free-form value", "type", t, "error", err)
+		return nil
+	}
+	return res
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
 	"github.com/d1vbyz3r0/typed/internal/parser/headers"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/codes"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/mime"
@@ -24,7 +25,48 @@ type Response struct {
 	// ContentType is a content type retrieved from func usage context. It's empty for Redirect and NoContent
 	ContentType string
 	ModelType   *typing.Type
-	Headers     []headers.Header
+	// Object is set instead of ModelType, when model is a map literal with constant keys or anonymous struct
+	Object *InlineObject
+	// EventType is a type of payload, marshaled for each server-sent event. It's set for text/event-stream responses only
+	EventType *typing.Type
+	Headers   []headers.Header
+}
+
+// Models returns response model and types of inline object properties
+func (r Response) Models() []*typing.Type {
+	var res []*typing.Type
+	if r.ModelType != nil {
+		res = append(res, r.ModelType)
+	}
+
+	if r.Object != nil {
+		res = append(res, r.Object.Types()...)
+	}
+
+	if r.EventType != nil {
+		res = append(res, r.EventType)
+	}
+	return res
+}
+
+// Substitute returns copy of response with type parameters in models replaced by args
+func (r Response) Substitute(args map[string]*typing.Type) Response {
+	r.ModelType = r.ModelType.Substitute(args)
+	r.Object = r.Object.Substitute(args)
+	r.EventType = r.EventType.Substitute(args)
+	return r
+}
+
+// Substitute returns copy of mapping with type parameters in response models replaced by args
+func (m StatusCodeMapping) Substitute(args map[string]*typing.Type) StatusCodeMapping {
+	res := make(StatusCodeMapping, len(m))
+	for status, responses := range m {
+		res[status] = make([]Response, len(responses))
+		for i, resp := range responses {
+			res[status][i] = resp.Substitute(args)
+		}
+	}
+	return res
 }
 
 // NewStatusCodeMapping builds StatusCodeMapping from provided handler function declaration
@@ -33,9 +75,10 @@ func NewStatusCodeMapping(
 	cr *codes.Resolver,
 	mr *mime.Resolver,
 	typesInfo *types.Info,
+	diags *diag.Collector,
 ) StatusCodeMapping {
 	m := make(StatusCodeMapping)
-	m.extractResponses(funcDecl, cr, mr, typesInfo)
+	m.extractResponses(funcDecl, cr, mr, typesInfo, diags)
 	return m
 }
 
@@ -44,6 +87,7 @@ func (m StatusCodeMapping) extractResponses(
 	cr *codes.Resolver,
 	mr *mime.Resolver,
 	typesInfo *types.Info,
+	diags *diag.Collector,
 ) {
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
@@ -59,20 +103,24 @@ func (m StatusCodeMapping) extractResponses(
 
 		statusCode, err := resp.StatusCode()
 		if err != nil {
-			logging.Error("failed to get status code", "error", err)
+			diags.Addf(call.Pos(), diag.NonConstantStatusCode, "response skipped, unresolved status code: %v", err)
 			return true
 		}
 
 		contentType, err := resp.ContentType()
 		if err != nil {
-			logging.Error("failed to get content type", "error", err)
+			diags.Addf(call.Pos(), diag.UnresolvedContentType, "response skipped, unresolved content type: %v", err)
 			return true
 		}
 
-		model, err := resp.ModelType()
-		if err != nil {
-			logging.Error("failed to get model type info", "error", err)
-			return true
+		var model *typing.Type
+		obj, isObject := resp.InlineObject()
+		if !isObject {
+			model, err = resp.ModelType()
+			if err != nil {
+				diags.Addf(call.Pos(), diag.UnsupportedResponseType, "response skipped, unsupported model type: %v", err)
+				return true
+			}
 		}
 
 		respHeaders := findHeaders(funcDecl, call.Pos(), typesInfo)
@@ -81,14 +129,28 @@ func (m StatusCodeMapping) extractResponses(
 		m[statusCode] = append(m[statusCode], Response{
 			ContentType: contentType,
 			ModelType:   model,
+			Object:      obj,
 			Headers:     respHeaders,
 		})
 
 		return true
 	})
 
+	// declared content type wins, so flushed NDJSON or CSV streams are not described as events
+	contentType, declared := declaredContentType(funcDecl, typesInfo)
+	if declared && isEventStream(contentType) || !declared && hasSSEUsages(funcDecl, typesInfo) {
+		logging.Debug("found server-sent events usage", logging.Handler(funcDecl.Name.String()))
+		m[http.StatusOK] = append(m[http.StatusOK], Response{
+			ContentType: MIMETextEventStream,
+			EventType:   sseEventType(funcDecl, typesInfo, diags),
+		})
+	} else if status, resp, ok := streamingResponse(funcDecl, cr, typesInfo, diags); ok {
+		logging.Debug("found streaming response", logging.Handler(funcDecl.Name.String()), "content_type", resp.ContentType)
+		m[status] = append(m[status], resp)
+	}
+
 	if hasWebSocketUsages(funcDecl, typesInfo) {
-		logging.Debug("found websocket usage, extending headers", "handler", funcDecl.Name.String())
+		logging.Debug("found websocket usage, extending headers", logging.Handler(funcDecl.Name.String()))
 		m[http.StatusSwitchingProtocols] = append(m[http.StatusSwitchingProtocols], Response{
 			Headers: []headers.Header{
 				{
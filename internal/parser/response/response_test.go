@@ -15,6 +15,7 @@ import (
 	"github.com/d1vbyz3r0/typed/internal/parser/response/codes"
 	"github.com/d1vbyz3r0/typed/internal/parser/response/mime"
 	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,7 +63,7 @@ func TestStatusCodeMapping_extractResponses(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			pkg := testsuite.LoadFixturePackage(t, "handlers")
 			fn := testsuite.Func(t, pkg, "Handler")
-			mapping := NewStatusCodeMapping(fn, cr, mr, pkg.TypesInfo)
+			mapping := NewStatusCodeMapping(fn, cr, mr, pkg.TypesInfo, nil)
 			require.Equal(t, len(tt.want), len(mapping))
 			for status, want := range tt.want {
 				require.ElementsMatch(t, want, mapping[status])
@@ -71,6 +72,70 @@ func TestStatusCodeMapping_extractResponses(t *testing.T) {
 	}
 }
 
+func TestStatusCodeMapping_inlineObjects(t *testing.T) {
+	cr, err := codes.NewResolver()
+	require.NoError(t, err)
+
+	mr, err := mime.NewResolver()
+	require.NoError(t, err)
+
+	pkg := testsuite.LoadFixturePackage(t, "handlers")
+	fn := testsuite.Func(t, pkg, "InlineObjectHandler")
+	mapping := NewStatusCodeMapping(fn, cr, mr, pkg.TypesInfo, nil)
+
+	want := StatusCodeMapping{
+		http.StatusBadRequest: {
+			{
+				ContentType: "application/json",
+				Object: &InlineObject{
+					Properties: []InlineProperty{
+						{Name: "error", Required: true, Type: typing.Basic("string")},
+						{Name: "code", Required: true, Type: typing.Basic("int")},
+					},
+				},
+			},
+		},
+		http.StatusCreated: {
+			{
+				ContentType: "application/json",
+				Object: &InlineObject{
+					Properties: []InlineProperty{
+						{Name: "id", Required: true, Type: typing.Basic("int")},
+						{Name: "comment", Type: typing.Basic("string")},
+						{Name: "meta", Required: true, Object: &InlineObject{
+							Properties: []InlineProperty{
+								{Name: "tags", Required: true, Type: typing.Slice(typing.Basic("string"))},
+							},
+						}},
+					},
+				},
+			},
+		},
+		http.StatusOK: {
+			{
+				ContentType: "application/json",
+				Object: &InlineObject{
+					Properties: []InlineProperty{
+						{Name: "name", Required: true, Type: typing.Basic("string")},
+						{Name: "profile", Required: true, Type: typing.Pointer(typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Profile"))},
+						{Name: "data", Required: true, Type: typing.Map(typing.Basic("string"), typing.Basic("any"))},
+						{Name: "nested", Required: true, Object: &InlineObject{
+							Properties: []InlineProperty{
+								{Name: "ok", Required: true, Type: typing.Basic("bool")},
+							},
+						}},
+						{Name: "nothing", Required: true},
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, want, mapping)
+
+	models := mapping[http.StatusOK][0].Models()
+	require.Contains(t, models, typing.Pointer(typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Profile")))
+}
+
 func parseFunc(t *testing.T, src string) (*ast.FuncDecl, *types.Info, token.Pos) {
 	t.Helper()
 
@@ -316,3 +381,167 @@ func Handler(c *Ctx) error {
 		})
 	}
 }
+
+func TestStatusCodeMapping_serverSentEvents(t *testing.T) {
+	cr, err := codes.NewResolver()
+	require.NoError(t, err)
+
+	mr, err := mime.NewResolver()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		handler string
+		want    StatusCodeMapping
+	}{
+		{
+			name:    "content type, data frames and single event type",
+			handler: "EventsHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{
+						ContentType: MIMETextEventStream,
+						EventType:   typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Example"),
+					},
+				},
+			},
+		},
+		{
+			name:    "data frames without content type",
+			handler: "PingEventsHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: MIMETextEventStream},
+				},
+			},
+		},
+		{
+			name:    "http.Flusher with different event types",
+			handler: "MixedEventsHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: MIMETextEventStream},
+				},
+				http.StatusInternalServerError: {
+					{},
+				},
+			},
+		},
+		{
+			name:    "regular handler",
+			handler: "Handler",
+		},
+		{
+			name:    "flush without data frames",
+			handler: "FlushHandler",
+		},
+		{
+			name:    "data: literal not written to response",
+			handler: "DataPrefixHandler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := testsuite.LoadFixturePackage(t, "handlers")
+			fn := testsuite.Func(t, pkg, tt.handler)
+			mapping := NewStatusCodeMapping(fn, cr, mr, pkg.TypesInfo, nil)
+			if tt.want == nil {
+				for _, responses := range mapping {
+					for _, resp := range responses {
+						require.NotEqual(t, MIMETextEventStream, resp.ContentType)
+					}
+				}
+				return
+			}
+			require.Equal(t, tt.want, mapping)
+		})
+	}
+}
+
+func TestStatusCodeMapping_streamingResponses(t *testing.T) {
+	cr, err := codes.NewResolver()
+	require.NoError(t, err)
+
+	mr, err := mime.NewResolver()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		handler string
+		want    StatusCodeMapping
+	}{
+		{
+			name:    "json encoder in loop",
+			handler: "NDJSONHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{
+						ContentType: MIMEApplicationNDJSON,
+						ModelType:   typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Example"),
+					},
+				},
+			},
+		},
+		{
+			name:    "flushed json encoder with ndjson content type header",
+			handler: "NDJSONFlushHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{
+						ContentType: MIMEApplicationNDJSON,
+						ModelType:   typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Example"),
+					},
+				},
+			},
+		},
+		{
+			name:    "xml encoder with status code and content type header",
+			handler: "XMLStreamHandler",
+			want: StatusCodeMapping{
+				http.StatusAccepted: {
+					{
+						ContentType: echo.MIMEApplicationXML,
+						ModelType:   typing.Named("github.com/d1vbyz3r0/typed/testdata/handlers", "Profile"),
+					},
+				},
+			},
+		},
+		{
+			name:    "io.Copy with content type header",
+			handler: "CopyHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: "text/csv"},
+				},
+			},
+		},
+		{
+			name:    "raw writes with flush",
+			handler: "FlushHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: echo.MIMEOctetStream},
+				},
+			},
+		},
+		{
+			name:    "raw write",
+			handler: "RawWriteHandler",
+			want: StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: echo.MIMEOctetStream},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := testsuite.LoadFixturePackage(t, "handlers")
+			fn := testsuite.Func(t, pkg, tt.handler)
+			mapping := NewStatusCodeMapping(fn, cr, mr, pkg.TypesInfo, nil)
+			require.Equal(t, tt.want, mapping)
+		})
+	}
+}
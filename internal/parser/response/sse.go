@@ -0,0 +1,181 @@
+package response
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/parser/headers"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMETextEventStream is a content type of server-sent events stream
+const MIMETextEventStream = "text/event-stream"
+
+// hasSSEUsages reports if handler streams server-sent events: sets text/event-stream content type
+// or writes data: frames to response. Flush calls alone are not enough, as any streamed response can be flushed
+func hasSSEUsages(funcDecl *ast.FuncDecl, info *types.Info) (found bool) {
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+
+		if call, ok := n.(*ast.CallExpr); ok {
+			found = isEventStreamContentType(call, info) || isDataFrameWrite(call, info)
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// isEventStreamContentType reports if call sets content type header to text/event-stream:
+// c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+func isEventStreamContentType(call *ast.CallExpr, info *types.Info) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Set" && sel.Sel.Name != "Add") || len(call.Args) != 2 {
+		return false
+	}
+
+	if !headers.IsHttpHeaderMethod(call, info) {
+		return false
+	}
+
+	name, ok := stringConst(call.Args[0], info)
+	if !ok || !strings.EqualFold(name, echo.HeaderContentType) {
+		return false
+	}
+
+	value, ok := stringConst(call.Args[1], info)
+	return ok && isEventStream(value)
+}
+
+// isEventStream reports if content type header value is text/event-stream, possibly with parameters
+func isEventStream(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), MIMETextEventStream)
+}
+
+func isNamed(t types.Type, pkg string, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// isDataFrameWrite reports if call writes event stream data frame to response, such as
+// fmt.Fprintf(c.Response(), "data: %s\n\n", data), io.WriteString(w, "data: ping\n\n") or w.Write([]byte("data: ..."))
+func isDataFrameWrite(call *ast.CallExpr, info *types.Info) bool {
+	fn, ok := calledFunc(call, info)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+
+	var (
+		w    ast.Expr
+		args []ast.Expr
+	)
+
+	switch {
+	case fn.Pkg().Path() == "fmt" && strings.HasPrefix(fn.Name(), "Fprint") && len(call.Args) > 1:
+		w, args = call.Args[0], call.Args[1:]
+	case fn.Pkg().Path() == "io" && fn.Name() == "WriteString" && len(call.Args) == 2:
+		w, args = call.Args[0], call.Args[1:]
+	case (fn.Name() == "Write" || fn.Name() == "WriteString") && len(call.Args) == 1:
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		w, args = sel.X, call.Args
+	default:
+		return false
+	}
+
+	if !isResponseWriter(info.TypeOf(w)) {
+		return false
+	}
+
+	return slices.ContainsFunc(args, func(arg ast.Expr) bool {
+		return isDataFrame(arg, info)
+	})
+}
+
+// isDataFrame reports if expression is a constant event stream data frame, such as "data: %s\n\n" or []byte("data: ping\n\n")
+func isDataFrame(expr ast.Expr, info *types.Info) bool {
+	expr = ast.Unparen(expr)
+	if conv, ok := expr.(*ast.CallExpr); ok && len(conv.Args) == 1 && info.Types[conv.Fun].IsType() {
+		expr = conv.Args[0]
+	}
+
+	v, ok := stringConst(expr, info)
+	if !ok {
+		return false
+	}
+	return strings.HasPrefix(v, "data:") || strings.Contains(v, "\ndata:")
+}
+
+// sseEventType returns type of payload, marshaled with json.Marshal or json.Encoder.Encode for each event.
+// It returns nil if payload type can't be found or different types are marshaled
+func sseEventType(funcDecl *ast.FuncDecl, info *types.Info, diags *diag.Collector) *typing.Type {
+	var (
+		res  *typing.Type
+		many bool
+	)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || !isJSONEncodeCall(call, info) {
+			return true
+		}
+
+		t := info.TypeOf(call.Args[0])
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+
+		eventType, err := typing.NewType(t)
+		if err != nil {
+			diags.Addf(call.Pos(), diag.UnsupportedMessageType, "event schema skipped, unsupported event type: %v", err)
+			many = true
+			return false
+		}
+
+		if res != nil && res.String() != eventType.String() {
+			diags.Addf(call.Pos(), diag.AmbiguousEventType, "event schema skipped, different event types found: %s and %s", res, eventType)
+			many = true
+			return false
+		}
+
+		res = eventType
+		return true
+	})
+
+	if many {
+		return nil
+	}
+	return res
+}
+
+// isJSONEncodeCall reports if call is json.Marshal(v) or (*json.Encoder).Encode(v)
+func isJSONEncodeCall(call *ast.CallExpr, info *types.Info) bool {
+	fn, ok := calledFunc(call, info)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "encoding/json" {
+		return false
+	}
+	return fn.Name() == "Marshal" || fn.Name() == "Encode"
+}
+
+func stringConst(expr ast.Expr, info *types.Info) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
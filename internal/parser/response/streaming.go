@@ -0,0 +1,266 @@
+package response
+
+import (
+	"go/ast"
+	"go/types"
+	"net/http"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/parser/response/codes"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationNDJSON is a content type of newline delimited JSON stream
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// streamWrite describes direct write to response
+type streamWrite struct {
+	// contentType is a content type of encoder, it's empty for raw writes
+	contentType string
+	model       *typing.Type
+	inLoop      bool
+}
+
+// streamingResponse describes response, written directly to c.Response() with json.Encoder, xml.Encoder,
+// io.Copy or Write calls. Status code is taken from c.Response().WriteHeader(code) and content type from header sets
+func streamingResponse(funcDecl *ast.FuncDecl, cr *codes.Resolver, info *types.Info, diags *diag.Collector) (status int, resp Response, found bool) {
+	status = http.StatusOK
+	encoders := make(map[types.Object]string)
+	var (
+		writes      []streamWrite
+		headerValue string
+		stack       []ast.Node
+	)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			// enc := json.NewEncoder(c.Response())
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+
+			for i, rhs := range node.Rhs {
+				ident, ok := node.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				if contentType, ok := responseEncoder(rhs, info); ok {
+					if obj := info.ObjectOf(ident); obj != nil {
+						encoders[obj] = contentType
+					}
+				}
+			}
+
+		case *ast.CallExpr:
+			if isEventStreamContentType(node, info) {
+				return true
+			}
+
+			if v, ok := contentTypeHeader(node, info); ok {
+				headerValue = v
+				return true
+			}
+
+			fn, ok := calledFunc(node, info)
+			if !ok || fn.Pkg() == nil {
+				return true
+			}
+
+			switch {
+			case fn.Name() == "Encode" && (fn.Pkg().Path() == "encoding/json" || fn.Pkg().Path() == "encoding/xml"):
+				contentType, ok := encoderContentType(node, encoders, info)
+				if !ok || len(node.Args) != 1 {
+					return true
+				}
+
+				t := info.TypeOf(node.Args[0])
+				if ptr, ok := t.(*types.Pointer); ok {
+					t = ptr.Elem()
+				}
+
+				model, err := typing.NewType(t)
+				if err != nil {
+					diags.Addf(node.Pos(), diag.UnsupportedMessageType, "streamed model schema skipped, unsupported type: %v", err)
+					model = nil
+				}
+
+				writes = append(writes, streamWrite{
+					contentType: contentType,
+					model:       model,
+					inLoop:      inLoop(stack),
+				})
+
+			case fn.Name() == "Copy" && fn.Pkg().Path() == "io":
+				if len(node.Args) == 2 && isResponseWriter(info.TypeOf(node.Args[0])) {
+					writes = append(writes, streamWrite{inLoop: inLoop(stack)})
+				}
+
+			case fn.Name() == "Write" || fn.Name() == "WriteString":
+				if sel, ok := node.Fun.(*ast.SelectorExpr); ok && isResponseWriter(info.TypeOf(sel.X)) {
+					writes = append(writes, streamWrite{inLoop: inLoop(stack)})
+				}
+
+			case fn.Name() == "WriteHeader":
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || len(node.Args) != 1 || !isResponseWriter(info.TypeOf(sel.X)) {
+					return true
+				}
+
+				code, err := cr.Resolve(node.Args[0])
+				if err != nil {
+					diags.Addf(node.Pos(), diag.NonConstantStatusCode, "streaming response status code skipped: %v", err)
+					return true
+				}
+				status = code
+			}
+		}
+
+		return true
+	})
+
+	if len(writes) == 0 {
+		return 0, Response{}, false
+	}
+
+	// encoded writes describe response better than raw ones, so the first of them is used
+	w := writes[0]
+	for _, candidate := range writes {
+		if candidate.contentType != "" {
+			w = candidate
+			break
+		}
+	}
+
+	resp = Response{
+		ContentType: w.contentType,
+		ModelType:   w.model,
+	}
+
+	if w.contentType == echo.MIMEApplicationJSON && w.inLoop {
+		resp.ContentType = MIMEApplicationNDJSON
+	}
+
+	if headerValue != "" {
+		resp.ContentType, _, _ = strings.Cut(headerValue, ";")
+	}
+
+	if resp.ContentType == "" {
+		resp.ContentType = echo.MIMEOctetStream
+	}
+
+	return status, resp, true
+}
+
+// responseEncoder reports if expression creates json or xml encoder, writing to response, and returns its content type
+func responseEncoder(expr ast.Expr, info *types.Info) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+
+	fn, ok := calledFunc(call, info)
+	if !ok || fn.Pkg() == nil || fn.Name() != "NewEncoder" || !isResponseWriter(info.TypeOf(call.Args[0])) {
+		return "", false
+	}
+
+	switch fn.Pkg().Path() {
+	case "encoding/json":
+		return echo.MIMEApplicationJSON, true
+	case "encoding/xml":
+		return echo.MIMEApplicationXML, true
+	}
+	return "", false
+}
+
+// encoderContentType returns content type of encoder, which Encode method is called
+func encoderContentType(call *ast.CallExpr, encoders map[types.Object]string, info *types.Info) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+
+	if ident, ok := ast.Unparen(sel.X).(*ast.Ident); ok {
+		contentType, ok := encoders[info.ObjectOf(ident)]
+		return contentType, ok
+	}
+
+	return responseEncoder(sel.X, info)
+}
+
+// contentTypeHeader returns value of content type header, set with http.Header Set or Add
+func contentTypeHeader(call *ast.CallExpr, info *types.Info) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Set" && sel.Sel.Name != "Add") || len(call.Args) != 2 {
+		return "", false
+	}
+
+	if !isHttpHeader(info.TypeOf(sel.X)) {
+		return "", false
+	}
+
+	name, ok := stringConst(call.Args[0], info)
+	if !ok || !strings.EqualFold(name, echo.HeaderContentType) {
+		return "", false
+	}
+	return stringConst(call.Args[1], info)
+}
+
+// declaredContentType returns content type, set in response headers by handler
+func declaredContentType(funcDecl *ast.FuncDecl, info *types.Info) (contentType string, found bool) {
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && !found {
+			contentType, found = contentTypeHeader(call, info)
+		}
+		return !found
+	})
+	return contentType, found
+}
+
+func calledFunc(call *ast.CallExpr, info *types.Info) (*types.Func, bool) {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.Ident:
+		ident = fun
+	default:
+		return nil, false
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	return fn, ok
+}
+
+// isResponseWriter reports if t is *echo.Response or http.ResponseWriter
+func isResponseWriter(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return isNamed(ptr.Elem(), "github.com/labstack/echo/v4", "Response")
+	}
+	return isNamed(t, "net/http", "ResponseWriter")
+}
+
+func isHttpHeader(t types.Type) bool {
+	return isNamed(t, "net/http", "Header")
+}
+
+func inLoop(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		case *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
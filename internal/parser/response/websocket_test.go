@@ -62,7 +62,7 @@ func TestStatusCodeMapping_extractResponsesWebsockets(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			pkg, fun := testsuite.LoadFixtureFunc(t, "websockets", tt.handlerName)
-			m := NewStatusCodeMapping(fun, cr, mr, pkg.TypesInfo)
+			m := NewStatusCodeMapping(fun, cr, mr, pkg.TypesInfo, nil)
 			if len(tt.wantHeaders) == 0 {
 				require.NotContains(t, m, http.StatusSwitchingProtocols)
 				return
@@ -0,0 +1,653 @@
+package routes
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+	"slices"
+
+	"github.com/d1vbyz3r0/typed/common/meta"
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/tools/go/packages"
+)
+
+const echoPkg = "github.com/labstack/echo/v4"
+
+var methods = map[string]string{
+	"CONNECT": http.MethodConnect,
+	"DELETE":  http.MethodDelete,
+	"GET":     http.MethodGet,
+	"HEAD":    http.MethodHead,
+	"OPTIONS": http.MethodOptions,
+	"PATCH":   http.MethodPatch,
+	"POST":    http.MethodPost,
+	"PUT":     http.MethodPut,
+	"TRACE":   http.MethodTrace,
+}
+
+// anyMethods are methods registered by echo Any, in the same order
+var anyMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	echo.PROPFIND,
+	http.MethodPut,
+	http.MethodTrace,
+	echo.REPORT,
+}
+
+// Route is an echo route, found in routes registration code
+type Route struct {
+	Method string
+	Path   string
+	// Handler is a handler function, method or package variable, initialized with function literal.
+	// For handler factories, such as func() echo.HandlerFunc, it's the factory function.
+	// Inline function literals are functions, named as compiler names closures, such as MapRoutes.func1,
+	// with receiver of enclosing method
+	Handler types.Object
+	// TypeArgs are type arguments of generic handler or factory instance, such as Handle[CreateUserReq, UserResp](fn)
+	TypeArgs []*typing.Type
+	// Middlewares are group and route middlewares in order of registration.
+	// Middlewares, which can't be resolved to function, are skipped.
+	Middlewares []*types.Func
+	// Pos is a position of route registration call
+	Pos token.Pos
+}
+
+// Key returns handler key in the same format as parser.Handler.Key, so methods with same name of different
+// receivers have different keys
+func (r Route) Key() string {
+	var recv string
+	if fn, ok := r.Handler.(*types.Func); ok {
+		recv = meta.GetRecvName(fn.Origin())
+	}
+	return meta.FuncKey(r.Handler.Pkg().Path(), recv, r.Handler.Name()) + typing.FormatTypeArgs(r.TypeArgs)
+}
+
+// FullName returns handler full name, such as (*pkg/path.Server).GetUser for methods and pkg/path.Handler for functions and variables
+func (r Route) FullName() string {
+	if fn, ok := r.Handler.(*types.Func); ok {
+		return fn.FullName()
+	}
+	return r.Handler.Pkg().Path() + "." + r.Handler.Name()
+}
+
+// Adapter describes function, adapting handler with custom context to echo.HandlerFunc,
+// such as func Wrap(h func(c *AppContext) error) echo.HandlerFunc
+type Adapter struct {
+	// Func is a full function name, such as github.com/acme/app/web.Wrap
+	Func string
+	// HandlerArg is an index of adapted handler argument
+	HandlerArg int
+}
+
+type ExtractOpt func(e *extractor)
+
+// WithAdapters makes handlers, passed to adapters, route handlers instead of adapters
+func WithAdapters(adapters ...Adapter) ExtractOpt {
+	return func(e *extractor) {
+		for _, adapter := range adapters {
+			e.adapters[adapter.Func] = adapter.HandlerArg
+		}
+	}
+}
+
+// Extract finds routes registered with *echo.Echo and *echo.Group methods (GET, POST, ..., Add, Any, Match) in package.
+// Paths and methods must be constants. Routes of groups with non-constant prefixes are skipped, as their paths are unknown.
+// Group prefixes and middlewares are tracked through variables, struct fields
+// and package functions, which receive groups as arguments.
+func Extract(pkg *packages.Package, opts ...ExtractOpt) []Route {
+	e := &extractor{
+		pkg:      pkg.Types,
+		fset:     pkg.Fset,
+		info:     pkg.TypesInfo,
+		decls:    make(map[*types.Func]*ast.FuncDecl),
+		closures: make(map[*ast.FuncLit]*types.Func),
+		globals:  make(map[types.Object]*router),
+		active:   make(map[*ast.FuncDecl]struct{}),
+		adapters: make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	var decls []*ast.FuncDecl
+	for _, file := range pkg.Syntax {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+
+			if fn, ok := e.info.Defs[decl.Name].(*types.Func); ok {
+				e.decls[fn] = decl
+				decls = append(decls, decl)
+				e.addClosures(fn, decl)
+			}
+		}
+	}
+
+	// functions, receiving routers from other functions, are walked from call sites with known prefixes
+	called := make(map[*ast.FuncDecl]struct{})
+	for _, decl := range decls {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if callee, ok := e.callee(call); ok && e.hasRouterArgs(call) {
+					called[callee] = struct{}{}
+				}
+			}
+			return true
+		})
+	}
+
+	for _, decl := range decls {
+		if _, ok := called[decl]; ok {
+			continue
+		}
+		e.walk(decl, make(map[types.Object]*router))
+	}
+
+	return e.routes
+}
+
+// router is a state of *echo.Echo or *echo.Group value
+type router struct {
+	prefix      string
+	middlewares []*types.Func
+	// unknown is set, when prefix of router or its parent group isn't a constant, so paths of its routes can't be resolved
+	unknown bool
+}
+
+func (r *router) clone() *router {
+	if r == nil {
+		return &router{}
+	}
+
+	return &router{
+		prefix:      r.prefix,
+		middlewares: slices.Clone(r.middlewares),
+		unknown:     r.unknown,
+	}
+}
+
+type extractor struct {
+	pkg   *types.Package
+	fset  *token.FileSet
+	info  *types.Info
+	decls map[*types.Func]*ast.FuncDecl
+	// closures are functions of function literals, declared directly in function bodies
+	closures map[*ast.FuncLit]*types.Func
+	// globals are routers, saved to struct fields and package variables
+	globals map[types.Object]*router
+	active  map[*ast.FuncDecl]struct{}
+	routes  []Route
+	// adapters are handler argument indexes, keyed by adapter full name
+	adapters map[string]int
+}
+
+// addClosures saves functions of literals, declared in body of fn, so they can be route handlers
+func (e *extractor) addClosures(fn *types.Func, decl *ast.FuncDecl) {
+	for lit, name := range meta.ClosureNames(decl) {
+		sig, ok := e.info.TypeOf(lit).(*types.Signature)
+		if !ok {
+			continue
+		}
+
+		sig = types.NewSignatureType(fn.Signature().Recv(), nil, nil, sig.Params(), sig.Results(), sig.Variadic())
+		e.closures[lit] = types.NewFunc(lit.Pos(), e.pkg, name, sig)
+	}
+}
+
+// walk inspects function body. locals are routers, saved to local variables and parameters
+func (e *extractor) walk(decl *ast.FuncDecl, locals map[types.Object]*router) {
+	if _, ok := e.active[decl]; ok {
+		return
+	}
+
+	e.active[decl] = struct{}{}
+	defer delete(e.active, decl)
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+
+			for i, lhs := range node.Lhs {
+				e.trackRouter(locals, lhs, node.Rhs[i])
+			}
+
+		case *ast.ValueSpec:
+			if len(node.Names) != len(node.Values) {
+				return true
+			}
+
+			for i, name := range node.Names {
+				e.trackRouter(locals, name, node.Values[i])
+			}
+
+		case *ast.CallExpr:
+			e.call(locals, node)
+		}
+
+		return true
+	})
+}
+
+func (e *extractor) call(locals map[types.Object]*router, call *ast.CallExpr) {
+	if decl, ok := e.callee(call); ok && e.hasRouterArgs(call) {
+		params := make(map[types.Object]*router)
+		for i, obj := range e.paramObjects(decl) {
+			if i < len(call.Args) && obj != nil && isEchoRouter(e.info.TypeOf(call.Args[i])) {
+				params[obj] = e.router(locals, call.Args[i])
+			}
+		}
+
+		e.walk(decl, params)
+		return
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !isEchoRouter(e.info.TypeOf(sel.X)) {
+		return
+	}
+
+	if sel.Sel.Name == "Use" {
+		obj := e.object(sel.X)
+		if obj == nil {
+			logging.Debug("middlewares are registered on unknown router, ignoring")
+			return
+		}
+
+		r := e.router(locals, sel.X)
+		r.middlewares = append(r.middlewares, e.funcs(call.Args)...)
+		e.store(locals, obj, r)
+		return
+	}
+
+	e.routes = append(e.routes, e.route(locals, sel, call)...)
+}
+
+// trackRouter saves state of router, assigned to variable or field
+func (e *extractor) trackRouter(locals map[types.Object]*router, lhs ast.Expr, rhs ast.Expr) {
+	if !isEchoRouter(e.info.TypeOf(rhs)) {
+		return
+	}
+
+	obj := e.object(lhs)
+	if obj == nil {
+		return
+	}
+
+	r := e.router(locals, rhs)
+	e.store(locals, obj, r)
+	logging.Debug("found echo router", "name", obj.Name(), "prefix", r.prefix)
+}
+
+// router returns copy of router state for expression
+func (e *extractor) router(locals map[types.Object]*router, expr ast.Expr) *router {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return e.router(locals, x.X)
+
+	case *ast.Ident, *ast.SelectorExpr:
+		return e.lookup(locals, e.object(x)).clone()
+
+	case *ast.CallExpr:
+		sel, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Group" || len(x.Args) == 0 || !isEchoRouter(e.info.TypeOf(sel.X)) {
+			return &router{}
+		}
+
+		r := e.router(locals, sel.X)
+		p, ok := e.stringConst(x.Args[0])
+		if !ok {
+			logging.Debug("group prefix is not a constant, routes of group are skipped", logging.Pos(e.fset.Position(x.Pos())))
+			r.unknown = true
+		}
+
+		r.prefix += p
+		r.middlewares = append(r.middlewares, e.funcs(x.Args[1:])...)
+		return r
+	}
+
+	return &router{}
+}
+
+func (e *extractor) route(locals map[types.Object]*router, sel *ast.SelectorExpr, call *ast.CallExpr) []Route {
+	args := call.Args
+	var routeMethods []string
+	if method, ok := methods[sel.Sel.Name]; ok {
+		routeMethods = []string{method}
+	} else {
+		switch sel.Sel.Name {
+		case "Any":
+			routeMethods = anyMethods
+
+		case "Add":
+			if len(args) == 0 {
+				return nil
+			}
+
+			method, ok := e.stringConst(args[0])
+			if !ok {
+				logging.Debug("route method is not a constant, skipping")
+				return nil
+			}
+			routeMethods = []string{method}
+			args = args[1:]
+
+		case "Match":
+			if len(args) == 0 {
+				return nil
+			}
+
+			routeMethods, ok = e.stringConsts(args[0])
+			if !ok {
+				logging.Debug("route methods are not constants, skipping")
+				return nil
+			}
+			args = args[1:]
+
+		default:
+			return nil
+		}
+	}
+
+	if len(args) < 2 {
+		return nil
+	}
+
+	path, ok := e.stringConst(args[0])
+	if !ok {
+		logging.Debug("route path is not a constant, skipping", "methods", routeMethods)
+		return nil
+	}
+
+	r := e.router(locals, sel.X)
+	if r.unknown {
+		logging.Warn("route is registered on group with non-constant prefix, skipping", logging.Pos(e.fset.Position(call.Pos())), "methods", routeMethods, "path", path)
+		return nil
+	}
+
+	path = r.prefix + path
+	handler, ok := e.handlerFunc(args[1])
+	if !ok {
+		logging.Debug("route handler can't be resolved to function, skipping", "methods", routeMethods, "path", path)
+		return nil
+	}
+
+	middlewares := append(r.middlewares, e.funcs(args[2:])...)
+	res := make([]Route, 0, len(routeMethods))
+	for _, method := range routeMethods {
+		logging.Debug("found echo route", "method", method, "path", path, logging.Handler(handler.Name()))
+		res = append(res, Route{
+			Method:      method,
+			Path:        path,
+			Handler:     handler,
+			TypeArgs:    e.typeArgs(args[1]),
+			Middlewares: slices.Clone(middlewares),
+			Pos:         call.Pos(),
+		})
+	}
+
+	return res
+}
+
+// callee returns declaration of package function or method called
+func (e *extractor) callee(call *ast.CallExpr) (*ast.FuncDecl, bool) {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil, false
+	}
+
+	fn, ok := e.info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil, false
+	}
+
+	decl, ok := e.decls[fn.Origin()]
+	return decl, ok
+}
+
+func (e *extractor) hasRouterArgs(call *ast.CallExpr) bool {
+	return slices.ContainsFunc(call.Args, func(arg ast.Expr) bool {
+		return isEchoRouter(e.info.TypeOf(arg))
+	})
+}
+
+// paramObjects returns objects of function parameters in order of declaration
+func (e *extractor) paramObjects(decl *ast.FuncDecl) []types.Object {
+	var res []types.Object
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			res = append(res, nil)
+			continue
+		}
+
+		for _, name := range field.Names {
+			res = append(res, e.info.Defs[name])
+		}
+	}
+	return res
+}
+
+// object returns variable or field object of expression
+func (e *extractor) object(expr ast.Expr) types.Object {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return e.object(x.X)
+	case *ast.Ident:
+		return e.info.ObjectOf(x)
+	case *ast.SelectorExpr:
+		return e.info.ObjectOf(x.Sel)
+	}
+	return nil
+}
+
+func (e *extractor) lookup(locals map[types.Object]*router, obj types.Object) *router {
+	if obj == nil {
+		return nil
+	}
+
+	if r, ok := locals[obj]; ok {
+		return r
+	}
+	return e.globals[obj]
+}
+
+func (e *extractor) store(locals map[types.Object]*router, obj types.Object, r *router) {
+	if e.isGlobal(obj) {
+		e.globals[obj] = r
+		return
+	}
+	locals[obj] = r
+}
+
+// isGlobal reports if object outlives function call: struct field or package variable
+func (e *extractor) isGlobal(obj types.Object) bool {
+	v, ok := obj.(*types.Var)
+	if !ok {
+		return false
+	}
+	return v.IsField() || v.Parent() == e.pkg.Scope()
+}
+
+// funcs resolves middlewares to functions, unresolved middlewares are skipped
+func (e *extractor) funcs(exprs []ast.Expr) []*types.Func {
+	var res []*types.Func
+	for _, expr := range exprs {
+		obj, ok := e.handlerFunc(expr)
+		fn, isFunc := obj.(*types.Func)
+		if !ok || !isFunc {
+			logging.Debug("middleware can't be resolved to function, skipping")
+			continue
+		}
+		res = append(res, fn)
+	}
+	return res
+}
+
+// handlerFunc resolves handler expression to function: handler, h.Method, generic handler[T], factory call, such as newHandler(),
+// package variable, initialized with function literal, or inline function literal
+func (e *extractor) handlerFunc(expr ast.Expr) (types.Object, bool) {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return e.handlerFunc(x.X)
+
+	case *ast.FuncLit:
+		if fn, ok := e.closures[x]; ok {
+			return fn, true
+		}
+		return nil, false
+
+	case *ast.Ident:
+		return e.handlerObject(e.info.Uses[x])
+
+	case *ast.SelectorExpr:
+		return e.handlerObject(e.info.Uses[x.Sel])
+
+	case *ast.IndexExpr:
+		return e.handlerFunc(x.X)
+
+	case *ast.IndexListExpr:
+		return e.handlerFunc(x.X)
+
+	case *ast.CallExpr:
+		if handler, ok := e.adapted(x); ok {
+			return e.handlerFunc(handler)
+		}
+		return e.handlerFunc(x.Fun)
+	}
+
+	return nil, false
+}
+
+// adapted returns handler expression, passed to adapter call, such as h.GetUser in Wrap(h.GetUser)
+func (e *extractor) adapted(call *ast.CallExpr) (ast.Expr, bool) {
+	if len(e.adapters) == 0 {
+		return nil, false
+	}
+
+	obj, ok := e.handlerFunc(call.Fun)
+	fn, isFunc := obj.(*types.Func)
+	if !ok || !isFunc {
+		return nil, false
+	}
+
+	arg, ok := e.adapters[fn.FullName()]
+	if !ok || arg < 0 || arg >= len(call.Args) {
+		return nil, false
+	}
+	return call.Args[arg], true
+}
+
+func (e *extractor) handlerObject(obj types.Object) (types.Object, bool) {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin(), true
+	case *types.Var:
+		// only package variables can be parsed as handlers
+		if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// typeArgs returns type arguments of generic handler or factory instance, it returns nil for non-generic handlers
+func (e *extractor) typeArgs(expr ast.Expr) []*typing.Type {
+	var ident *ast.Ident
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return e.typeArgs(x.X)
+	case *ast.IndexExpr:
+		return e.typeArgs(x.X)
+	case *ast.IndexListExpr:
+		return e.typeArgs(x.X)
+	case *ast.CallExpr:
+		if handler, ok := e.adapted(x); ok {
+			return e.typeArgs(handler)
+		}
+		return e.typeArgs(x.Fun)
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	case *ast.Ident:
+		ident = x
+	default:
+		return nil
+	}
+
+	inst, ok := e.info.Instances[ident]
+	if !ok {
+		return nil
+	}
+
+	res := make([]*typing.Type, 0, inst.TypeArgs.Len())
+	for arg := range inst.TypeArgs.Types() {
+		t, err := typing.NewType(arg)
+		if err != nil {
+			logging.Debug("unsupported handler type argument", "type", arg, "error", err)
+			return nil
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+func (e *extractor) stringConst(expr ast.Expr) (string, bool) {
+	tv, ok := e.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// stringConsts returns values of composite literal with constant elements, such as []string{http.MethodGet}
+func (e *extractor) stringConsts(expr ast.Expr) ([]string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	res := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		s, ok := e.stringConst(elt)
+		if !ok {
+			return nil, false
+		}
+		res = append(res, s)
+	}
+	return res, true
+}
+
+// isEchoRouter reports if t is *echo.Echo or *echo.Group
+func isEchoRouter(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != echoPkg {
+		return false
+	}
+
+	name := named.Obj().Name()
+	return name == "Echo" || name == "Group"
+}
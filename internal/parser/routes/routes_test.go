@@ -0,0 +1,182 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	type route struct {
+		method      string
+		path        string
+		handler     string
+		middlewares []string
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    []route
+	}{
+		{
+			name:    "groups in single function",
+			fixture: "static",
+			want: []route{
+				{method: http.MethodGet, path: "/api/v1/users/:id", handler: "GetUser"},
+				{method: http.MethodPost, path: "/api/v1/users", handler: "CreateUser"},
+				{method: http.MethodDelete, path: "/api/v1/users/:id", handler: "DeleteUser"},
+				{method: http.MethodGet, path: "/api/v1/health", handler: "Health"},
+				{method: http.MethodGet, path: "/version", handler: "Version"},
+			},
+		},
+		{
+			name:    "groups passed to functions, any, match and middlewares",
+			fixture: "routes",
+			want: []route{
+				{method: http.MethodGet, path: "/api/users/:id", handler: "GetUser", middlewares: []string{"Auth"}},
+				{method: http.MethodGet, path: "/api/users", handler: "Handle", middlewares: []string{"Auth"}},
+				{method: http.MethodDelete, path: "/api/admin/cache", handler: "PurgeCache", middlewares: []string{"Auth", "Audit"}},
+				{method: http.MethodConnect, path: "/ping", handler: "Ping"},
+				{method: http.MethodDelete, path: "/ping", handler: "Ping"},
+				{method: http.MethodGet, path: "/ping", handler: "Ping"},
+				{method: http.MethodHead, path: "/ping", handler: "Ping"},
+				{method: http.MethodOptions, path: "/ping", handler: "Ping"},
+				{method: http.MethodPatch, path: "/ping", handler: "Ping"},
+				{method: http.MethodPost, path: "/ping", handler: "Ping"},
+				{method: echo.PROPFIND, path: "/ping", handler: "Ping"},
+				{method: http.MethodPut, path: "/ping", handler: "Ping"},
+				{method: http.MethodTrace, path: "/ping", handler: "Ping"},
+				{method: echo.REPORT, path: "/ping", handler: "Ping"},
+				{method: http.MethodGet, path: "/status", handler: "Status", middlewares: []string{"RateLimit"}},
+				{method: http.MethodHead, path: "/status", handler: "Status", middlewares: []string{"RateLimit"}},
+				{method: http.MethodGet, path: "/version", handler: "Version"},
+				{method: http.MethodGet, path: "/healthz", handler: "MapRoutes.func1"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg := testsuite.LoadFixturePackage(t, tc.fixture)
+
+			var got []route
+			for _, r := range Extract(pkg) {
+				var middlewares []string
+				for _, m := range r.Middlewares {
+					middlewares = append(middlewares, m.Name())
+				}
+
+				got = append(got, route{
+					method:      r.Method,
+					path:        r.Path,
+					handler:     r.Handler.Name(),
+					middlewares: middlewares,
+				})
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExtract_EmbeddedMethod(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "routes")
+
+	for _, r := range Extract(pkg) {
+		if r.Path != "/api/users/:id" {
+			continue
+		}
+
+		require.Equal(t, "(github.com/d1vbyz3r0/typed/testdata/routes.baseHandler).GetUser", r.Key())
+		require.Equal(t, "(github.com/d1vbyz3r0/typed/testdata/routes.baseHandler).GetUser", r.FullName())
+		return
+	}
+
+	require.Fail(t, "route not found")
+}
+
+func TestExtract_NonConstantGroupPrefix(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "routes")
+
+	got := make(map[string]int)
+	for _, r := range Extract(pkg) {
+		got[r.Method+" "+r.Path]++
+	}
+
+	// routes of tenant group would be registered without prefix otherwise
+	require.NotContains(t, got, "DELETE /admin/cache")
+	require.Equal(t, 1, got["GET /status"])
+}
+
+func TestExtract_ReceiverKeys(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "parser/receivers")
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/parser/receivers"
+
+	got := make(map[string]string)
+	for _, r := range Extract(pkg) {
+		got[r.Method+" "+r.Path] = r.Key()
+	}
+	require.Equal(t, map[string]string{
+		"GET /users":  "(*" + pkgPath + ".UserHandler).List",
+		"GET /orders": "(" + pkgPath + ".OrderHandler).List",
+	}, got)
+}
+
+func TestExtract_InlineHandler(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "routes")
+
+	for _, r := range Extract(pkg) {
+		if r.Path != "/healthz" {
+			continue
+		}
+
+		require.Equal(t, "(*github.com/d1vbyz3r0/typed/testdata/routes.Server).MapRoutes.func1", r.Key())
+		require.Equal(t, "(*github.com/d1vbyz3r0/typed/testdata/routes.Server).MapRoutes.func1", r.FullName())
+		return
+	}
+
+	require.Fail(t, "route not found")
+}
+
+func TestExtract_GenericWrapperKey(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "routes")
+
+	for _, r := range Extract(pkg) {
+		if r.Path != "/api/users" || r.Method != http.MethodGet {
+			continue
+		}
+
+		require.Equal(t, "github.com/d1vbyz3r0/typed/testdata/routes.Handle[github.com/d1vbyz3r0/typed/testdata/routes.ListUsersRequest]", r.Key())
+		return
+	}
+
+	require.Fail(t, "route not found")
+}
+
+func TestExtract_Adapters(t *testing.T) {
+	pkg := testsuite.LoadFixturePackage(t, "parser/adapters")
+	const pkgPath = "github.com/d1vbyz3r0/typed/testdata/parser/adapters"
+
+	got := make(map[string]string)
+	for _, r := range Extract(pkg) {
+		got[r.Method+" "+r.Path] = r.FullName()
+	}
+	require.Equal(t, map[string]string{
+		"GET /me":     pkgPath + ".Wrap",
+		"POST /users": pkgPath + ".Named",
+	}, got)
+
+	got = make(map[string]string)
+	adapters := []Adapter{{Func: pkgPath + ".Wrap"}, {Func: pkgPath + ".Named", HandlerArg: 1}}
+	for _, r := range Extract(pkg, WithAdapters(adapters...)) {
+		got[r.Method+" "+r.Path] = r.FullName()
+	}
+	require.Equal(t, map[string]string{
+		"GET /me":     pkgPath + ".GetUser",
+		"POST /users": pkgPath + ".CreateUser",
+	}, got)
+}
@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+const (
+	xnetPkg    = "golang.org/x/net/websocket"
+	gorillaPkg = "github.com/gorilla/websocket"
+	coderPkg   = "github.com/coder/websocket"
+	wsjsonPkg  = "github.com/coder/websocket/wsjson"
+)
+
+type direction int
+
+const (
+	send direction = iota + 1
+	receive
+)
+
+// call describes function, sending or receiving websocket message
+type call struct {
+	pkg  string
+	name string
+	dir  direction
+	// arg is an index of message argument. It's -1 for raw messages, which payload is described as string
+	arg int
+}
+
+var calls = []call{
+	// golang.org/x/net/websocket: websocket.JSON.Send(ws, v), websocket.Message.Receive(ws, &v)
+	{pkg: xnetPkg, name: "Send", dir: send, arg: 1},
+	{pkg: xnetPkg, name: "Receive", dir: receive, arg: 1},
+	// github.com/gorilla/websocket: ws.WriteJSON(v), ws.ReadJSON(&v), ws.WriteMessage(t, data), ws.ReadMessage()
+	{pkg: gorillaPkg, name: "WriteJSON", dir: send, arg: 0},
+	{pkg: gorillaPkg, name: "ReadJSON", dir: receive, arg: 0},
+	{pkg: gorillaPkg, name: "WriteMessage", dir: send, arg: -1},
+	{pkg: gorillaPkg, name: "ReadMessage", dir: receive, arg: -1},
+	// github.com/coder/websocket: wsjson.Write(ctx, conn, v), wsjson.Read(ctx, conn, &v), conn.Write(ctx, t, data), conn.Read(ctx)
+	{pkg: wsjsonPkg, name: "Write", dir: send, arg: 2},
+	{pkg: wsjsonPkg, name: "Read", dir: receive, arg: 2},
+	{pkg: coderPkg, name: "Write", dir: send, arg: -1},
+	{pkg: coderPkg, name: "Read", dir: receive, arg: -1},
+}
+
+var rawMessageType = typing.Basic("string")
+
+// Messages describes messages, sent and received by websocket handler
+type Messages struct {
+	Send    []*typing.Type
+	Receive []*typing.Type
+}
+
+// Models returns types of all messages
+func (m *Messages) Models() []*typing.Type {
+	if m == nil {
+		return nil
+	}
+	return append(append([]*typing.Type(nil), m.Send...), m.Receive...)
+}
+
+// Substitute returns copy of messages with type parameters replaced by args
+func (m *Messages) Substitute(args map[string]*typing.Type) *Messages {
+	if m == nil {
+		return nil
+	}
+
+	substitute := func(types []*typing.Type) []*typing.Type {
+		res := make([]*typing.Type, len(types))
+		for i, t := range types {
+			res[i] = t.Substitute(args)
+		}
+		return res
+	}
+
+	return &Messages{
+		Send:    substitute(m.Send),
+		Receive: substitute(m.Receive),
+	}
+}
+
+// NewMessages extracts types of messages, sent and received with supported websocket libraries.
+// It returns nil if no messages were found
+func NewMessages(funcDecl *ast.FuncDecl, info *types.Info, diags *diag.Collector) *Messages {
+	m := new(Messages)
+	seen := make(map[direction]map[string]struct{})
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		c, ok := lookupCall(callExpr, info)
+		if !ok {
+			return true
+		}
+
+		t := rawMessageType
+		if c.arg >= 0 {
+			if c.arg >= len(callExpr.Args) {
+				return true
+			}
+
+			var err error
+			t, err = messageType(info.TypeOf(callExpr.Args[c.arg]))
+			if err != nil {
+				diags.Addf(callExpr.Pos(), diag.UnsupportedMessageType, "websocket message skipped, unsupported type: %v", err)
+				return true
+			}
+		}
+
+		if seen[c.dir] == nil {
+			seen[c.dir] = make(map[string]struct{})
+		}
+
+		k := t.String()
+		if _, ok := seen[c.dir][k]; ok {
+			return true
+		}
+		seen[c.dir][k] = struct{}{}
+
+		logging.Debug("found websocket message", logging.Handler(funcDecl.Name.Name), "type", k, "send", c.dir == send)
+		switch c.dir {
+		case send:
+			m.Send = append(m.Send, t)
+		case receive:
+			m.Receive = append(m.Receive, t)
+		}
+		return true
+	})
+
+	if len(m.Send) == 0 && len(m.Receive) == 0 {
+		return nil
+	}
+	return m
+}
+
+func lookupCall(callExpr *ast.CallExpr, info *types.Info) (call, bool) {
+	var ident *ast.Ident
+	switch fun := callExpr.Fun.(type) {
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.Ident:
+		ident = fun
+	default:
+		return call{}, false
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return call{}, false
+	}
+
+	for _, c := range calls {
+		if c.pkg == fn.Pkg().Path() && c.name == fn.Name() {
+			return c, true
+		}
+	}
+	return call{}, false
+}
+
+// messageType returns message payload type. Pointers are dereferenced, since received messages are decoded into pointers
+// and encoding of pointer is the same as encoding of value. Raw []byte messages are described as strings
+func messageType(t types.Type) (*typing.Type, error) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	t = types.Default(t)
+	if s, ok := t.Underlying().(*types.Slice); ok {
+		if b, ok := s.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return rawMessageType, nil
+		}
+	}
+
+	return typing.NewType(t)
+}
@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessages(t *testing.T) {
+	const pkg = "websockets"
+	event := typing.Named(pkg, "Event")
+
+	tests := []struct {
+		name        string
+		handlerName string
+		want        *Messages
+	}{
+		{
+			name:        "xnet websocket",
+			handlerName: "XNetWebsocket",
+			want: &Messages{
+				Send:    []*typing.Type{typing.Basic("string"), event},
+				Receive: []*typing.Type{typing.Basic("string")},
+			},
+		},
+		{
+			name:        "gorilla websocket",
+			handlerName: "GorillaWebsocket",
+			want: &Messages{
+				Send:    []*typing.Type{typing.Basic("string"), event},
+				Receive: []*typing.Type{typing.Basic("string"), typing.Named(pkg, "Command")},
+			},
+		},
+		{
+			name:        "coder websocket",
+			handlerName: "CoderWebsocket",
+			want: &Messages{
+				Send:    []*typing.Type{event},
+				Receive: []*typing.Type{typing.Basic("any")},
+			},
+		},
+		{
+			name:        "no websockets",
+			handlerName: "Regular",
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, fun := testsuite.LoadFixtureFunc(t, "websockets", tt.handlerName)
+			require.Equal(t, tt.want, NewMessages(fun, pkg.TypesInfo, nil))
+		})
+	}
+}
@@ -0,0 +1,603 @@
+package specdiff
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Change is a single difference between two specifications
+type Change struct {
+	// Code identifies kind of change, e.g. "operation-removed"
+	Code string `json:"code"`
+	// Breaking reports whether clients of base specification may fail with revision
+	Breaking bool `json:"breaking"`
+	// Operation is "METHOD /path" of changed operation
+	Operation string `json:"operation"`
+	// Location points to changed part of operation, e.g. "response 200 application/json: .items[].id"
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (c Change) String() string {
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s", c.Operation, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Operation, c.Location, c.Message)
+}
+
+// Report is a result of specifications comparison. Changes are ordered by operation
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreaking reports whether report contains breaking changes
+func (r Report) HasBreaking() bool {
+	return slices.ContainsFunc(r.Changes, func(c Change) bool {
+		return c.Breaking
+	})
+}
+
+// Breaking returns breaking changes only
+func (r Report) Breaking() []Change {
+	var res []Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// String formats report as human-readable lists of breaking and non-breaking changes
+func (r Report) String() string {
+	if len(r.Changes) == 0 {
+		return "no changes"
+	}
+
+	var breaking, compatible []string
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c.String())
+		} else {
+			compatible = append(compatible, c.String())
+		}
+	}
+
+	var b strings.Builder
+	write := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s (%d):\n", title, len(lines))
+		for _, line := range lines {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	write("breaking changes", breaking)
+	write("non-breaking changes", compatible)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// direction defines, how schema changes affect clients: they send requests and receive responses
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type comparator struct {
+	base     *openapi3.T
+	revision *openapi3.T
+	changes  []Change
+}
+
+// Compare classifies differences of operations between base and revision as breaking or non-breaking.
+// Nil specification is treated as empty one
+func Compare(base, revision *openapi3.T) Report {
+	c := &comparator{base: base, revision: revision, changes: make([]Change, 0)}
+
+	baseOps, revisionOps := operations(base), operations(revision)
+	for _, key := range slices.Sorted(maps.Keys(baseOps)) {
+		revisionOp, ok := revisionOps[key]
+		if !ok {
+			c.add(key, "", "operation-removed", true, "operation removed")
+			continue
+		}
+		c.operation(key, baseOps[key], revisionOp)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(revisionOps)) {
+		if _, ok := baseOps[key]; !ok {
+			c.add(key, "", "operation-added", false, "operation added")
+		}
+	}
+
+	slices.SortStableFunc(c.changes, func(a, b Change) int {
+		return strings.Compare(a.Operation, b.Operation)
+	})
+	return Report{Changes: c.changes}
+}
+
+func (c *comparator) add(op, location, code string, breaking bool, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Code:      code,
+		Breaking:  breaking,
+		Operation: op,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparator) operation(key string, base, revision *openapi3.Operation) {
+	c.parameters(key, base.Parameters, revision.Parameters)
+	c.requestBody(key, base.RequestBody, revision.RequestBody)
+	c.responses(key, base.Responses, revision.Responses)
+}
+
+func (c *comparator) parameters(op string, base, revision openapi3.Parameters) {
+	index := func(params openapi3.Parameters) map[string]*openapi3.Parameter {
+		res := make(map[string]*openapi3.Parameter)
+		for _, p := range params {
+			if p != nil && p.Value != nil {
+				res[p.Value.In+" "+p.Value.Name] = p.Value
+			}
+		}
+		return res
+	}
+
+	baseParams, revisionParams := index(base), index(revision)
+	for _, key := range slices.Sorted(maps.Keys(baseParams)) {
+		p := baseParams[key]
+		location := fmt.Sprintf("%s parameter %q", p.In, p.Name)
+		next, ok := revisionParams[key]
+		if !ok {
+			c.add(op, location, "parameter-removed", false, "parameter removed")
+			continue
+		}
+
+		switch {
+		case !p.Required && next.Required:
+			c.add(op, location, "parameter-became-required", true, "parameter became required")
+		case p.Required && !next.Required:
+			c.add(op, location, "parameter-became-optional", false, "parameter became optional")
+		}
+
+		c.schema(op, location, request, c.resolve(c.base, p.Schema), c.resolve(c.revision, next.Schema))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(revisionParams)) {
+		if _, ok := baseParams[key]; ok {
+			continue
+		}
+
+		p := revisionParams[key]
+		location := fmt.Sprintf("%s parameter %q", p.In, p.Name)
+		if p.Required {
+			c.add(op, location, "required-parameter-added", true, "required parameter added")
+		} else {
+			c.add(op, location, "optional-parameter-added", false, "optional parameter added")
+		}
+	}
+}
+
+func (c *comparator) requestBody(op string, base, revision *openapi3.RequestBodyRef) {
+	var baseBody, revisionBody *openapi3.RequestBody
+	if base != nil {
+		baseBody = base.Value
+	}
+	if revision != nil {
+		revisionBody = revision.Value
+	}
+
+	switch {
+	case baseBody == nil && revisionBody == nil:
+		return
+	case baseBody == nil:
+		c.add(op, "request body", "request-body-added", revisionBody.Required, "request body added")
+		return
+	case revisionBody == nil:
+		c.add(op, "request body", "request-body-removed", false, "request body removed")
+		return
+	}
+
+	if !baseBody.Required && revisionBody.Required {
+		c.add(op, "request body", "request-body-became-required", true, "request body became required")
+	}
+
+	for _, mime := range slices.Sorted(maps.Keys(baseBody.Content)) {
+		location := "request body " + mime
+		next, ok := revisionBody.Content[mime]
+		if !ok {
+			c.add(op, location, "request-media-type-removed", true, "media type removed")
+			continue
+		}
+		c.schema(op, location, request, c.resolve(c.base, baseBody.Content[mime].Schema), c.resolve(c.revision, next.Schema))
+	}
+
+	for _, mime := range slices.Sorted(maps.Keys(revisionBody.Content)) {
+		if _, ok := baseBody.Content[mime]; !ok {
+			c.add(op, "request body "+mime, "request-media-type-added", false, "media type added")
+		}
+	}
+}
+
+func (c *comparator) responses(op string, base, revision *openapi3.Responses) {
+	baseResponses, revisionResponses := responsesMap(base), responsesMap(revision)
+	for _, status := range slices.Sorted(maps.Keys(baseResponses)) {
+		location := "response " + status
+		next, ok := revisionResponses[status]
+		if !ok {
+			c.add(op, location, "response-removed", true, "response removed")
+			continue
+		}
+
+		prev := baseResponses[status]
+		for _, mime := range slices.Sorted(maps.Keys(prev.Content)) {
+			mimeLocation := location + " " + mime
+			nextMedia, ok := next.Content[mime]
+			if !ok {
+				c.add(op, mimeLocation, "response-media-type-removed", true, "media type removed")
+				continue
+			}
+			c.schema(op, mimeLocation, response, c.resolve(c.base, prev.Content[mime].Schema), c.resolve(c.revision, nextMedia.Schema))
+		}
+
+		for _, mime := range slices.Sorted(maps.Keys(next.Content)) {
+			if _, ok := prev.Content[mime]; !ok {
+				c.add(op, location+" "+mime, "response-media-type-added", false, "media type added")
+			}
+		}
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(revisionResponses)) {
+		if _, ok := baseResponses[status]; !ok {
+			c.add(op, "response "+status, "response-added", false, "response added")
+		}
+	}
+}
+
+func responsesMap(responses *openapi3.Responses) map[string]*openapi3.Response {
+	res := make(map[string]*openapi3.Response)
+	if responses == nil {
+		return res
+	}
+
+	for status, ref := range responses.Map() {
+		if ref != nil && ref.Value != nil {
+			res[status] = ref.Value
+		}
+	}
+	return res
+}
+
+// schemaPair is a pair of compared schemas. It's used to stop comparison of recursive schemas
+type schemaPair struct {
+	base     *openapi3.Schema
+	revision *openapi3.Schema
+}
+
+// schema compares schemas of request or response. Location is extended with path to nested properties
+func (c *comparator) schema(op, location string, dir direction, base, revision *openapi3.Schema) {
+	c.schemaAt(op, location, "", dir, base, revision, make(map[schemaPair]struct{}))
+}
+
+func (c *comparator) schemaAt(
+	op, location, path string,
+	dir direction,
+	base, revision *openapi3.Schema,
+	visited map[schemaPair]struct{},
+) {
+	if base == nil || revision == nil {
+		return
+	}
+
+	pair := schemaPair{base: base, revision: revision}
+	if _, ok := visited[pair]; ok {
+		return
+	}
+	visited[pair] = struct{}{}
+
+	// allOf members describe one object, so embedded components are compared as if they were flattened
+	base = c.flatten(c.base, base, make(map[*openapi3.Schema]struct{}))
+	revision = c.flatten(c.revision, revision, make(map[*openapi3.Schema]struct{}))
+
+	at := location
+	if path != "" {
+		at += ": " + path
+	}
+
+	if prev, next := schemaType(base), schemaType(revision); prev != next {
+		c.add(op, at, "type-changed", true, "type changed from %s to %s", prev, next)
+		return
+	}
+
+	if len(base.Enum) == 0 && len(revision.Enum) > 0 {
+		c.add(op, at, "enum-added", dir == request, "values restricted to enum")
+	}
+
+	removed, added := enumDiff(base.Enum, revision.Enum)
+	if len(removed) > 0 {
+		// narrowed enum breaks clients, sending removed values; clients don't receive removed values anymore
+		c.add(op, at, "enum-values-removed", dir == request, "enum values removed: %s", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		c.add(op, at, "enum-values-added", false, "enum values added: %s", strings.Join(added, ", "))
+	}
+
+	c.properties(op, location, path, dir, base, revision, visited)
+	c.additionalProperties(op, location, path, dir, base, revision, visited)
+
+	if base.Items != nil && revision.Items != nil {
+		c.schemaAt(op, location, path+"[]", dir, c.resolve(c.base, base.Items), c.resolve(c.revision, revision.Items), visited)
+	}
+
+	c.variants(op, location, path, "oneOf", dir, base.OneOf, revision.OneOf, visited)
+	c.variants(op, location, path, "anyOf", dir, base.AnyOf, revision.AnyOf, visited)
+}
+
+// flatten merges properties, required names and type of allOf members into schema. Own properties take
+// precedence over the ones of members. Schema without allOf is returned as is
+func (c *comparator) flatten(spec *openapi3.T, s *openapi3.Schema, seen map[*openapi3.Schema]struct{}) *openapi3.Schema {
+	if len(s.AllOf) == 0 {
+		return s
+	}
+
+	if _, ok := seen[s]; ok {
+		return s
+	}
+	seen[s] = struct{}{}
+
+	merged := *s
+	merged.AllOf = nil
+	merged.Properties = maps.Clone(s.Properties)
+	if merged.Properties == nil {
+		merged.Properties = make(openapi3.Schemas)
+	}
+	merged.Required = slices.Clone(s.Required)
+
+	for _, ref := range s.AllOf {
+		member := c.resolve(spec, ref)
+		if member == nil {
+			continue
+		}
+		member = c.flatten(spec, member, seen)
+
+		for name, prop := range member.Properties {
+			if _, ok := merged.Properties[name]; !ok {
+				merged.Properties[name] = prop
+			}
+		}
+		for _, name := range member.Required {
+			if !slices.Contains(merged.Required, name) {
+				merged.Required = append(merged.Required, name)
+			}
+		}
+
+		if merged.Type == nil {
+			merged.Type = member.Type
+		}
+		if merged.Items == nil {
+			merged.Items = member.Items
+		}
+		if merged.AdditionalProperties.Has == nil && merged.AdditionalProperties.Schema == nil {
+			merged.AdditionalProperties = member.AdditionalProperties
+		}
+		merged.OneOf = append(merged.OneOf, member.OneOf...)
+		merged.AnyOf = append(merged.AnyOf, member.AnyOf...)
+	}
+	return &merged
+}
+
+// additionalProperties compares whether object allows properties, which are not listed, and their schemas
+func (c *comparator) additionalProperties(
+	op, location, path string,
+	dir direction,
+	base, revision *openapi3.Schema,
+	visited map[schemaPair]struct{},
+) {
+	at := location
+	if path != "" {
+		at += ": " + path
+	}
+
+	prev, next := base.AdditionalProperties, revision.AdditionalProperties
+	prevAllowed, nextAllowed := additionalAllowed(prev), additionalAllowed(next)
+	switch {
+	case prevAllowed && !nextAllowed:
+		c.add(op, at, "additional-properties-disallowed", dir == request, "additional properties disallowed")
+		return
+	case !prevAllowed && nextAllowed:
+		c.add(op, at, "additional-properties-allowed", false, "additional properties allowed")
+		return
+	case !prevAllowed:
+		return
+	}
+
+	if prev.Schema == nil && next.Schema == nil {
+		return
+	}
+
+	// absent schema allows values of any type
+	prevSchema, nextSchema := &openapi3.Schema{}, &openapi3.Schema{}
+	if prev.Schema != nil {
+		prevSchema = c.resolve(c.base, prev.Schema)
+	}
+	if next.Schema != nil {
+		nextSchema = c.resolve(c.revision, next.Schema)
+	}
+	c.schemaAt(op, location, path+"{}", dir, prevSchema, nextSchema, visited)
+}
+
+func additionalAllowed(p openapi3.AdditionalProperties) bool {
+	return p.Has == nil || *p.Has || p.Schema != nil
+}
+
+// variants compares oneOf or anyOf members. Members are matched by component name, inline members by position.
+// New variant breaks clients, receiving response, and removed one breaks clients, sending request
+func (c *comparator) variants(
+	op, location, path, kind string,
+	dir direction,
+	base, revision openapi3.SchemaRefs,
+	visited map[schemaPair]struct{},
+) {
+	if len(base) == 0 && len(revision) == 0 {
+		return
+	}
+
+	at := func(key string) string {
+		return location + ": " + path + "." + kind + "[" + key + "]"
+	}
+
+	prev, next := variantsMap(base), variantsMap(revision)
+	for _, key := range slices.Sorted(maps.Keys(prev)) {
+		ref, ok := next[key]
+		if !ok {
+			c.add(op, at(key), "variant-removed", dir == request, "%s member removed", kind)
+			continue
+		}
+		c.schemaAt(op, location, path+"."+kind+"["+key+"]", dir, c.resolve(c.base, prev[key]), c.resolve(c.revision, ref), visited)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(next)) {
+		if _, ok := prev[key]; !ok {
+			c.add(op, at(key), "variant-added", dir == response, "%s member added", kind)
+		}
+	}
+}
+
+func variantsMap(refs openapi3.SchemaRefs) map[string]*openapi3.SchemaRef {
+	res := make(map[string]*openapi3.SchemaRef, len(refs))
+	for i, ref := range refs {
+		if ref == nil {
+			continue
+		}
+
+		key := strconv.Itoa(i)
+		if name, ok := strings.CutPrefix(ref.Ref, "#/components/schemas/"); ok {
+			key = name
+		}
+		res[key] = ref
+	}
+	return res
+}
+
+func (c *comparator) properties(
+	op, location, path string,
+	dir direction,
+	base, revision *openapi3.Schema,
+	visited map[schemaPair]struct{},
+) {
+	at := func(name string) string {
+		return location + ": " + path + "." + name
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(base.Properties)) {
+		wasRequired := slices.Contains(base.Required, name)
+		next, ok := revision.Properties[name]
+		if !ok {
+			switch {
+			case dir == response && wasRequired:
+				c.add(op, at(name), "required-property-removed", true, "required property removed")
+			default:
+				c.add(op, at(name), "property-removed", false, "property removed")
+			}
+			continue
+		}
+
+		isRequired := slices.Contains(revision.Required, name)
+		switch {
+		case dir == request && !wasRequired && isRequired:
+			c.add(op, at(name), "property-became-required", true, "property became required")
+		case dir == response && wasRequired && !isRequired:
+			c.add(op, at(name), "property-became-optional", true, "property became optional")
+		case wasRequired != isRequired:
+			c.add(op, at(name), "property-required-changed", false, "property required changed to %t", isRequired)
+		}
+
+		c.schemaAt(op, location, path+"."+name, dir, c.resolve(c.base, base.Properties[name]), c.resolve(c.revision, next), visited)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(revision.Properties)) {
+		if _, ok := base.Properties[name]; ok {
+			continue
+		}
+
+		if dir == request && slices.Contains(revision.Required, name) {
+			c.add(op, at(name), "required-property-added", true, "required property added")
+		} else {
+			c.add(op, at(name), "property-added", false, "property added")
+		}
+	}
+}
+
+// resolve returns schema of ref. Refs to component schemas are resolved in spec, when ref has no value
+func (c *comparator) resolve(spec *openapi3.T, ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil {
+		return nil
+	}
+
+	if ref.Value != nil {
+		return ref.Value
+	}
+
+	name, ok := strings.CutPrefix(ref.Ref, "#/components/schemas/")
+	if !ok || spec == nil || spec.Components == nil {
+		return nil
+	}
+
+	component, ok := spec.Components.Schemas[name]
+	if !ok || component == nil || component.Ref == ref.Ref {
+		return nil
+	}
+	return c.resolve(spec, component)
+}
+
+func schemaType(s *openapi3.Schema) string {
+	var res string
+	if s.Type != nil {
+		res = strings.Join(s.Type.Slice(), "|")
+	}
+	if s.Format != "" {
+		res += "/" + s.Format
+	}
+	if res == "" {
+		return "any"
+	}
+	return res
+}
+
+// enumDiff returns formatted values, removed from and added to enum. Empty enum allows any value,
+// so values are not reported, when one of enums is empty
+func enumDiff(base, revision []any) (removed, added []string) {
+	if len(base) == 0 || len(revision) == 0 {
+		return nil, nil
+	}
+
+	format := func(values []any) map[string]struct{} {
+		res := make(map[string]struct{}, len(values))
+		for _, v := range values {
+			res[fmt.Sprintf("%v", v)] = struct{}{}
+		}
+		return res
+	}
+
+	prev, next := format(base), format(revision)
+	for _, v := range slices.Sorted(maps.Keys(prev)) {
+		if _, ok := next[v]; !ok {
+			removed = append(removed, v)
+		}
+	}
+	for _, v := range slices.Sorted(maps.Keys(next)) {
+		if _, ok := prev[v]; !ok {
+			added = append(added, v)
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,447 @@
+package specdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const baseSpec = `
+openapi: 3.0.0
+info: {title: api, version: "1"}
+paths:
+  /users:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: role, in: query, schema: {type: string, enum: [admin, user, guest]}}
+      responses:
+        "200":
+          description: users
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/User"}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CreateUser"}
+      responses:
+        "201": {description: created}
+  /health:
+    get:
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+        email: {type: string}
+        manager: {$ref: "#/components/schemas/User"}
+    CreateUser:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        email: {type: string}
+`
+
+func loadSpec(t *testing.T, data string) *openapi3.T {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	require.NoError(t, err)
+	return spec
+}
+
+type change struct {
+	code     string
+	breaking bool
+	location string
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision string
+		want     []change
+	}{
+		{
+			name:     "equal",
+			revision: baseSpec,
+		},
+		{
+			name: "operations",
+			revision: `
+openapi: 3.0.0
+info: {title: api, version: "1"}
+paths:
+  /users:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: role, in: query, schema: {type: string, enum: [admin, user, guest]}}
+      responses:
+        "200":
+          description: users
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/User"}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CreateUser"}
+      responses:
+        "201": {description: created}
+  /version:
+    get:
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+        email: {type: string}
+        manager: {$ref: "#/components/schemas/User"}
+    CreateUser:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        email: {type: string}
+`,
+			want: []change{
+				{code: "operation-removed", breaking: true},
+				{code: "operation-added"},
+			},
+		},
+		{
+			name: "parameters and request body",
+			revision: `
+openapi: 3.0.0
+info: {title: api, version: "1"}
+paths:
+  /users:
+    get:
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer}}
+        - {name: role, in: query, schema: {type: string, enum: [admin, user, owner]}}
+        - {name: X-Tenant, in: header, required: true, schema: {type: string}}
+        - {name: sort, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: users
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/User"}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CreateUser"}
+      responses:
+        "201": {description: created}
+  /health:
+    get:
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+        email: {type: string}
+        manager: {$ref: "#/components/schemas/User"}
+    CreateUser:
+      type: object
+      required: [name, email, password]
+      properties:
+        name: {type: string}
+        email: {type: string}
+        password: {type: string}
+`,
+			want: []change{
+				{code: "parameter-became-required", breaking: true, location: `query parameter "limit"`},
+				{code: "enum-values-removed", breaking: true, location: `query parameter "role"`},
+				{code: "enum-values-added", location: `query parameter "role"`},
+				{code: "required-parameter-added", breaking: true, location: `header parameter "X-Tenant"`},
+				{code: "optional-parameter-added", location: `query parameter "sort"`},
+				{code: "property-became-required", breaking: true, location: "request body application/json: .email"},
+				{code: "required-property-added", breaking: true, location: "request body application/json: .password"},
+			},
+		},
+		{
+			name: "responses",
+			revision: `
+openapi: 3.0.0
+info: {title: api, version: "1"}
+paths:
+  /users:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: role, in: query, schema: {type: string, enum: [admin, user, guest]}}
+      responses:
+        "200":
+          description: users
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/User"}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CreateUser"}
+      responses:
+        "200": {description: created}
+  /health:
+    get:
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string, format: uuid}
+        email: {type: string}
+        manager: {$ref: "#/components/schemas/User"}
+        createdAt: {type: string, format: date-time}
+    CreateUser:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        email: {type: string}
+`,
+			// recursive manager property is not compared again
+			want: []change{
+				{code: "type-changed", breaking: true, location: "response 200 application/json: [].id"},
+				{code: "required-property-removed", breaking: true, location: "response 200 application/json: [].name"},
+				{code: "property-added", location: "response 200 application/json: [].createdAt"},
+				{code: "response-removed", breaking: true, location: "response 201"},
+				{code: "response-added", location: "response 200"},
+			},
+		},
+	}
+
+	base := loadSpec(t, baseSpec)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(base, loadSpec(t, tt.revision))
+
+			got := make([]change, 0, len(report.Changes))
+			for _, c := range report.Changes {
+				got = append(got, change{code: c.Code, breaking: c.Breaking, location: c.Location})
+			}
+			if len(tt.want) == 0 {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tt.want, got)
+			}
+			require.Equal(t, len(report.Breaking()) > 0, report.HasBreaking())
+		})
+	}
+}
+
+const compositionSpec = `
+openapi: 3.0.0
+info: {title: api, version: "1"}
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CreateUser"}
+      responses:
+        "201": {description: created}
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pet
+          content:
+            application/json:
+              schema:
+                oneOf:
+                  - {$ref: "#/components/schemas/Cat"}
+                  - {$ref: "#/components/schemas/Dog"}
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id, created_at]
+      properties:
+        id: {type: integer}
+        created_at: {type: string, format: date-time}
+    User:
+      allOf:
+        - {$ref: "#/components/schemas/Base"}
+        - type: object
+          required: [name]
+          properties:
+            name: {type: string}
+    CreateUser:
+      type: object
+      properties:
+        labels: {type: object, additionalProperties: {type: string}}
+    Cat:
+      type: object
+      required: [lives]
+      properties:
+        lives: {type: integer}
+    Dog:
+      type: object
+      properties:
+        breed: {type: string}
+`
+
+func TestCompare_Composition(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision string
+		want     []change
+	}{
+		{
+			name:     "equal",
+			revision: compositionSpec,
+		},
+		{
+			name: "all of flattened",
+			revision: strings.Replace(compositionSpec, `    User:
+      allOf:
+        - {$ref: "#/components/schemas/Base"}
+        - type: object
+          required: [name]
+          properties:
+            name: {type: string}`, `    User:
+      type: object
+      required: [id, created_at, name]
+      properties:
+        id: {type: integer}
+        created_at: {type: string, format: date-time}
+        name: {type: string}`, 1),
+		},
+		{
+			name: "all of member changed",
+			revision: strings.Replace(compositionSpec, `      required: [id, created_at]
+      properties:
+        id: {type: integer}`, `      required: [id]
+      properties:
+        id: {type: string}`, 1),
+			want: []change{
+				{code: "property-became-optional", breaking: true, location: "response 200 application/json: .created_at"},
+				{code: "type-changed", breaking: true, location: "response 200 application/json: .id"},
+			},
+		},
+		{
+			name: "one of members",
+			revision: strings.Replace(strings.Replace(compositionSpec, `                  - {$ref: "#/components/schemas/Dog"}`, `                  - {$ref: "#/components/schemas/Parrot"}`, 1),
+				`      required: [lives]
+      properties:
+        lives: {type: integer}`, `      properties:
+        lives: {type: integer}
+    Parrot:
+      type: object`, 1),
+			want: []change{
+				{code: "property-became-optional", breaking: true, location: "response 200 application/json: .oneOf[Cat].lives"},
+				{code: "variant-removed", location: "response 200 application/json: .oneOf[Dog]"},
+				{code: "variant-added", breaking: true, location: "response 200 application/json: .oneOf[Parrot]"},
+			},
+		},
+		{
+			name:     "additional properties schema",
+			revision: strings.Replace(compositionSpec, `additionalProperties: {type: string}`, `additionalProperties: {type: integer}`, 1),
+			want: []change{
+				{code: "type-changed", breaking: true, location: "request body application/json: .labels{}"},
+			},
+		},
+		{
+			name:     "additional properties disallowed",
+			revision: strings.Replace(compositionSpec, `additionalProperties: {type: string}`, `additionalProperties: false`, 1),
+			want: []change{
+				{code: "additional-properties-disallowed", breaking: true, location: "request body application/json: .labels"},
+			},
+		},
+	}
+
+	base := loadSpec(t, compositionSpec)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.want) == 0 && tt.name != "equal" {
+				require.NotEqual(t, compositionSpec, tt.revision, "revision must differ from base")
+			}
+			report := Compare(base, loadSpec(t, tt.revision))
+
+			got := make([]change, 0, len(report.Changes))
+			for _, c := range report.Changes {
+				got = append(got, change{code: c.Code, breaking: c.Breaking, location: c.Location})
+			}
+			if len(tt.want) == 0 {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompare_UnresolvedRefs(t *testing.T) {
+	spec := func(idType string) *openapi3.T {
+		t := &openapi3.T{
+			OpenAPI: "3.0.0",
+			Paths:   openapi3.NewPaths(),
+			Components: &openapi3.Components{Schemas: openapi3.Schemas{
+				"User": openapi3.NewSchemaRef("", openapi3.NewObjectSchema().
+					WithProperty("id", &openapi3.Schema{Type: &openapi3.Types{idType}}).
+					WithRequired([]string{"id"})),
+			}},
+		}
+		t.AddOperation("/users", "GET", &openapi3.Operation{
+			Responses: openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{
+				Value: openapi3.NewResponse().WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/User", nil)),
+			})),
+		})
+		return t
+	}
+
+	report := Compare(spec(openapi3.TypeInteger), spec(openapi3.TypeString))
+	require.Len(t, report.Changes, 1)
+	require.Equal(t, "type-changed", report.Changes[0].Code)
+	require.True(t, report.HasBreaking())
+}
+
+func TestReport_String(t *testing.T) {
+	require.Equal(t, "no changes", Report{}.String())
+
+	r := Report{Changes: []Change{
+		{Code: "operation-removed", Breaking: true, Operation: "GET /health", Message: "operation removed"},
+		{Code: "property-added", Operation: "GET /users", Location: "response 200 application/json: .email", Message: "property added"},
+	}}
+	require.Equal(t, `breaking changes (1):
+  GET /health: operation removed
+non-breaking changes (1):
+  GET /users: response 200 application/json: .email: property added`, r.String())
+}
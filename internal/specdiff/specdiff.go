@@ -0,0 +1,120 @@
+// Package specdiff compares OpenAPI specifications
+package specdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Summary lists operations and component schemas, which differ between two specifications.
+// Operations are identified as "METHOD /path", schemas by component names
+type Summary struct {
+	AddedOperations   []string `json:"added_operations,omitempty"`
+	RemovedOperations []string `json:"removed_operations,omitempty"`
+	ChangedOperations []string `json:"changed_operations,omitempty"`
+	AddedSchemas      []string `json:"added_schemas,omitempty"`
+	RemovedSchemas    []string `json:"removed_schemas,omitempty"`
+	ChangedSchemas    []string `json:"changed_schemas,omitempty"`
+}
+
+// Summarize compares specifications. Nil specification is treated as empty one
+func Summarize(base, revision *openapi3.T) Summary {
+	var s Summary
+	s.AddedOperations, s.RemovedOperations, s.ChangedOperations = compare(operations(base), operations(revision))
+	s.AddedSchemas, s.RemovedSchemas, s.ChangedSchemas = compare(schemas(base), schemas(revision))
+	return s
+}
+
+// Empty reports whether specifications are equal in operations and schemas
+func (s Summary) Empty() bool {
+	return len(s.AddedOperations) == 0 &&
+		len(s.RemovedOperations) == 0 &&
+		len(s.ChangedOperations) == 0 &&
+		len(s.AddedSchemas) == 0 &&
+		len(s.RemovedSchemas) == 0 &&
+		len(s.ChangedSchemas) == 0
+}
+
+// String formats summary as lines with "+", "-" and "~" prefixes for added, removed and changed entries
+func (s Summary) String() string {
+	if s.Empty() {
+		return "no changes"
+	}
+
+	var b strings.Builder
+	write := func(title, prefix string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n", title)
+		for _, name := range names {
+			fmt.Fprintf(&b, "  %s %s\n", prefix, name)
+		}
+	}
+
+	write("operations added", "+", s.AddedOperations)
+	write("operations removed", "-", s.RemovedOperations)
+	write("operations changed", "~", s.ChangedOperations)
+	write("schemas added", "+", s.AddedSchemas)
+	write("schemas removed", "-", s.RemovedSchemas)
+	write("schemas changed", "~", s.ChangedSchemas)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func operations(spec *openapi3.T) map[string]*openapi3.Operation {
+	res := make(map[string]*openapi3.Operation)
+	if spec == nil || spec.Paths == nil {
+		return res
+	}
+
+	for path, item := range spec.Paths.Map() {
+		for method, op := range item.Operations() {
+			res[method+" "+path] = op
+		}
+	}
+	return res
+}
+
+func schemas(spec *openapi3.T) openapi3.Schemas {
+	if spec == nil || spec.Components == nil {
+		return nil
+	}
+	return spec.Components.Schemas
+}
+
+// compare returns sorted keys of added, removed and changed values. Values are compared in JSON representation,
+// so refs are compared by their targets names, not by resolved schemas
+func compare[M ~map[string]V, V any](base, revision M) (added, removed, changed []string) {
+	for _, k := range slices.Sorted(maps.Keys(revision)) {
+		prev, ok := base[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case !equal(prev, revision[k]):
+			changed = append(changed, k)
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(base)) {
+		if _, ok := revision[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	return added, removed, changed
+}
+
+func equal(a, b any) bool {
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aj) == string(bj)
+}
@@ -0,0 +1,108 @@
+package specdiff
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpec(ops map[string]map[string]*openapi3.Operation, schemas openapi3.Schemas) *openapi3.T {
+	spec := &openapi3.T{
+		OpenAPI:    "3.0.0",
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+	for path, methods := range ops {
+		for method, op := range methods {
+			spec.AddOperation(path, method, op)
+		}
+	}
+	return spec
+}
+
+func TestSummarize(t *testing.T) {
+	base := newSpec(
+		map[string]map[string]*openapi3.Operation{
+			"/users": {
+				"GET":  {OperationID: "ListUsers"},
+				"POST": {OperationID: "CreateUser"},
+			},
+			"/users/{id}": {
+				"DELETE": {OperationID: "DeleteUser"},
+			},
+		},
+		openapi3.Schemas{
+			"User":  openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithProperty("id", openapi3.NewIntegerSchema())),
+			"Admin": openapi3.NewSchemaRef("", openapi3.NewObjectSchema()),
+		},
+	)
+
+	revision := newSpec(
+		map[string]map[string]*openapi3.Operation{
+			"/users": {
+				"GET":  {OperationID: "ListUsers"},
+				"POST": {OperationID: "CreateUser", Description: "creates user"},
+			},
+			"/users/{id}": {
+				"GET": {OperationID: "GetUser"},
+			},
+		},
+		openapi3.Schemas{
+			"User":  openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithProperty("id", openapi3.NewStringSchema())),
+			"Group": openapi3.NewSchemaRef("", openapi3.NewObjectSchema()),
+		},
+	)
+
+	tests := []struct {
+		name     string
+		base     *openapi3.T
+		revision *openapi3.T
+		want     Summary
+	}{
+		{
+			name:     "changes",
+			base:     base,
+			revision: revision,
+			want: Summary{
+				AddedOperations:   []string{"GET /users/{id}"},
+				RemovedOperations: []string{"DELETE /users/{id}"},
+				ChangedOperations: []string{"POST /users"},
+				AddedSchemas:      []string{"Group"},
+				RemovedSchemas:    []string{"Admin"},
+				ChangedSchemas:    []string{"User"},
+			},
+		},
+		{
+			name:     "equal",
+			base:     base,
+			revision: base,
+		},
+		{
+			name:     "nil base",
+			revision: base,
+			want: Summary{
+				AddedOperations: []string{"DELETE /users/{id}", "GET /users", "POST /users"},
+				AddedSchemas:    []string{"Admin", "User"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(tt.base, tt.revision)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.want.Empty(), got.Empty())
+		})
+	}
+}
+
+func TestSummary_String(t *testing.T) {
+	require.Equal(t, "no changes", Summary{}.String())
+
+	s := Summary{
+		AddedOperations: []string{"GET /users/{id}"},
+		ChangedSchemas:  []string{"User"},
+	}
+	require.Equal(t, "operations added:\n  + GET /users/{id}\nschemas changed:\n  ~ User", s.String())
+}
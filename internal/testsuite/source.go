@@ -0,0 +1,59 @@
+package testsuite
+
+import (
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sync"
+	"testing"
+)
+
+var (
+	sourceMu       sync.Mutex
+	sourceFset     = token.NewFileSet()
+	sourceImporter = importer.ForCompiler(sourceFset, "source", nil)
+)
+
+// CheckSource parses and type checks single file package source. Imports are resolved from sources of module
+// dependencies and cached between calls. Soft errors, such as unused imports, are ignored.
+func CheckSource(t testing.TB, src string) (*ast.File, *types.Info) {
+	t.Helper()
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+
+	file, err := parser.ParseFile(sourceFset, "source.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse source: %v", err)
+	}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+
+	var hardErrs []error
+	conf := types.Config{
+		Importer: sourceImporter,
+		Error: func(err error) {
+			var typeErr types.Error
+			if errors.As(err, &typeErr) && typeErr.Soft {
+				return
+			}
+			hardErrs = append(hardErrs, err)
+		},
+	}
+
+	_, _ = conf.Check(file.Name.Name, sourceFset, []*ast.File{file}, info)
+	if len(hardErrs) > 0 {
+		t.Fatalf("type check source: %v", errors.Join(hardErrs...))
+	}
+
+	return file, info
+}
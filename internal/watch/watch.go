@@ -0,0 +1,274 @@
+// Package watch detects changes of Go source files in directories and of separate files by polling. It doesn't depend on
+// filesystem notifications, so it works the same way on every platform and in containers with mounted sources.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d1vbyz3r0/typed/logging"
+)
+
+const (
+	defaultInterval = 500 * time.Millisecond
+	defaultDebounce = 300 * time.Millisecond
+)
+
+// Dir is a watched directory
+type Dir struct {
+	Path      string
+	Recursive bool
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileState) equal(other fileState) bool {
+	return s.modTime.Equal(other.modTime) && s.size == other.size
+}
+
+type snapshot map[string]fileState
+
+type watchOpts struct {
+	interval time.Duration
+	debounce time.Duration
+	ignore   map[string]struct{}
+	files    []string
+}
+
+type WatchOpt func(opts *watchOpts)
+
+// WithInterval sets interval between directories scans
+func WithInterval(interval time.Duration) WatchOpt {
+	return func(opts *watchOpts) {
+		if interval > 0 {
+			opts.interval = interval
+		}
+	}
+}
+
+// WithDebounce sets duration without changes, after which changes are reported. Series of saves,
+// made by editor or formatter, are reported once
+func WithDebounce(debounce time.Duration) WatchOpt {
+	return func(opts *watchOpts) {
+		if debounce > 0 {
+			opts.debounce = debounce
+		}
+	}
+}
+
+// WithIgnore excludes files from watching, for example generated files in watched directories
+func WithIgnore(paths ...string) WatchOpt {
+	return func(opts *watchOpts) {
+		for _, p := range paths {
+			if abs, err := filepath.Abs(p); err == nil {
+				opts.ignore[abs] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithFiles adds files to watching, for example config or overlays. Files are watched regardless of their extension,
+// missing files are reported, when they are created
+func WithFiles(paths ...string) WatchOpt {
+	return func(opts *watchOpts) {
+		opts.files = append(opts.files, paths...)
+	}
+}
+
+type Watcher struct {
+	dirs  []Dir
+	files []string
+	opts  *watchOpts
+	last  snapshot
+}
+
+// New creates watcher of Go source files in dirs. Test files are not watched.
+// Current state of files is scanned immediately, so only changes made after New are reported
+func New(dirs []Dir, opts ...WatchOpt) (*Watcher, error) {
+	o := &watchOpts{
+		interval: defaultInterval,
+		debounce: defaultDebounce,
+		ignore:   make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	w := &Watcher{
+		dirs:  dirs,
+		files: o.files,
+		opts:  o,
+	}
+
+	last, err := w.scan()
+	if err != nil {
+		return nil, fmt.Errorf("scan directories: %w", err)
+	}
+	w.last = last
+
+	return w, nil
+}
+
+// Update replaces watched directories, files and ignored files, for example after config is reloaded. Files are
+// set with WithFiles and WithIgnore options, interval and debounce are kept. Current state of files is scanned again,
+// so only changes made after Update are reported. It's safe to call Update from onChange callback of Run
+func (w *Watcher) Update(dirs []Dir, opts ...WatchOpt) error {
+	o := &watchOpts{ignore: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.interval, o.debounce = w.opts.interval, w.opts.debounce
+
+	prev := *w
+	w.dirs, w.files, w.opts = dirs, o.files, o
+
+	last, err := w.scan()
+	if err != nil {
+		*w = prev
+		return fmt.Errorf("scan directories: %w", err)
+	}
+	w.last = last
+	return nil
+}
+
+// Run polls directories until ctx is done and calls onChange with sorted paths of changed, added and removed files.
+// onChange is called synchronously, so changes made while it's running are reported after it returns
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) error {
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+
+	var (
+		pending    = make(map[string]struct{})
+		lastChange time.Time
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := w.scan()
+		if err != nil {
+			logging.Warn("failed to scan watched directories", "error", err)
+			continue
+		}
+
+		if changed := diff(w.last, current); len(changed) > 0 {
+			for _, path := range changed {
+				pending[path] = struct{}{}
+			}
+			lastChange = time.Now()
+			w.last = current
+			logging.Debug("detected changes", "files", changed)
+			continue
+		}
+
+		if len(pending) > 0 && time.Since(lastChange) >= w.opts.debounce {
+			changed := slices.Sorted(maps.Keys(pending))
+			clear(pending)
+			onChange(changed)
+		}
+	}
+}
+
+func (w *Watcher) scan() (snapshot, error) {
+	res := make(snapshot)
+	for _, dir := range w.dirs {
+		root, err := filepath.Abs(dir.Path)
+		if err != nil {
+			return nil, fmt.Errorf("get absolute path of %s: %w", dir.Path, err)
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if path == root {
+					return nil
+				}
+				if !dir.Recursive || skipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !isSourceFile(d.Name()) {
+				return nil
+			}
+
+			if _, ok := w.opts.ignore[path]; ok {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			res[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("walk %s: %w", root, err)
+		}
+	}
+
+	for _, file := range w.files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("get absolute path of %s: %w", file, err)
+		}
+
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", path, err)
+		}
+		res[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return res, nil
+}
+
+// diff returns paths of files, which were changed, added or removed
+func diff(prev, current snapshot) []string {
+	var res []string
+	for path, state := range current {
+		if prevState, ok := prev[path]; !ok || !prevState.equal(state) {
+			res = append(res, path)
+		}
+	}
+
+	for path := range prev {
+		if _, ok := current[path]; !ok {
+			res = append(res, path)
+		}
+	}
+
+	slices.Sort(res)
+	return res
+}
+
+// skipDir reports whether directory is ignored by go tool
+func skipDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func isSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
@@ -0,0 +1,121 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	handlers := filepath.Join(root, "handlers.go")
+	generated := filepath.Join(root, "spec.go")
+	nested := filepath.Join(root, "nested", "nested.go")
+	writeFile(t, handlers, "package api\n")
+	writeFile(t, nested, "package nested\n")
+
+	w, err := New(
+		[]Dir{{Path: root}},
+		WithInterval(10*time.Millisecond),
+		WithDebounce(50*time.Millisecond),
+		WithIgnore(generated),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	changes := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx, func(changed []string) {
+			changes <- changed
+		})
+	}()
+
+	// ignored, non-recursive and non-source files are not reported
+	writeFile(t, generated, "package api\n")
+	writeFile(t, nested, "package nested\n\nvar x int\n")
+	writeFile(t, filepath.Join(root, "api_test.go"), "package api\n")
+	writeFile(t, filepath.Join(root, "README.md"), "# api\n")
+
+	// series of changes is reported once
+	writeFile(t, handlers, "package api\n\nvar a int\n")
+	writeFile(t, filepath.Join(root, "models.go"), "package api\n")
+
+	select {
+	case changed := <-changes:
+		require.Equal(t, []string{handlers, filepath.Join(root, "models.go")}, changed)
+	case <-ctx.Done():
+		t.Fatal("changes are not reported")
+	}
+
+	require.NoError(t, os.Remove(handlers))
+	select {
+	case changed := <-changes:
+		require.Equal(t, []string{handlers}, changed)
+	case <-ctx.Done():
+		t.Fatal("removal is not reported")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Empty(t, changes)
+}
+
+func TestWatcher_Recursive(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "nested", "nested.go")
+	writeFile(t, nested, "package nested\n")
+	writeFile(t, filepath.Join(root, "testdata", "fixture.go"), "package fixture\n")
+
+	w, err := New([]Dir{{Path: root, Recursive: true}, {Path: filepath.Join(root, "missing")}})
+	require.NoError(t, err)
+	require.Len(t, w.last, 1)
+
+	writeFile(t, nested, "package nested\n\nvar x int\n")
+	current, err := w.scan()
+	require.NoError(t, err)
+	require.Equal(t, []string{nested}, diff(w.last, current))
+}
+
+func TestWatcher_Files(t *testing.T) {
+	root := t.TempDir()
+	config := filepath.Join(root, "typed.yaml")
+	overlay := filepath.Join(root, "overlay.yaml")
+	writeFile(t, config, "input: {}\n")
+
+	w, err := New(nil, WithFiles(config, overlay))
+	require.NoError(t, err)
+	require.Len(t, w.last, 1, "missing files are skipped")
+
+	writeFile(t, config, "input:\n  handlers: []\n")
+	writeFile(t, overlay, "overlay: 1.0.0\n")
+	current, err := w.scan()
+	require.NoError(t, err)
+	require.Equal(t, []string{overlay, config}, diff(w.last, current))
+
+	handlers := filepath.Join(root, "api", "handlers.go")
+	writeFile(t, handlers, "package api\n")
+	generated := filepath.Join(root, "api", "spec.go")
+	writeFile(t, generated, "package api\n")
+	require.NoError(t, w.Update([]Dir{{Path: filepath.Join(root, "api")}}, WithFiles(config), WithIgnore(generated)))
+	require.Len(t, w.last, 2)
+
+	writeFile(t, overlay, "overlay: 1.0.1\n")
+	writeFile(t, generated, "package api\n\nvar a int\n")
+	writeFile(t, handlers, "package api\n\nvar a int\n")
+	current, err = w.scan()
+	require.NoError(t, err)
+	require.Equal(t, []string{handlers}, diff(w.last, current), "files, removed or ignored by update, are not watched")
+}
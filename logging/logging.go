@@ -2,7 +2,9 @@ package logging
 
 import (
 	"fmt"
+	"go/token"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
 	"sync"
@@ -17,6 +19,7 @@ const (
 	LevelError
 )
 
+// Logger logs messages with key-value pairs and slog.Attr attributes. *slog.Logger implements it, see NewSlogLogger
 type Logger interface {
 	Debug(msg string, attrs ...any)
 	Info(msg string, attrs ...any)
@@ -24,6 +27,106 @@ type Logger interface {
 	Error(msg string, attrs ...any)
 }
 
+var _ Logger = (*slog.Logger)(nil)
+
+// Format is an output format of slog logger
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses text or json format
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q, expected text or json", s)
+	}
+}
+
+// ParseLevel parses debug, info, warn (warning) or error level
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", s)
+	}
+}
+
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// NewSlogLogger creates slog logger, writing records of level and above to w with text or JSON handler
+func NewSlogLogger(w io.Writer, format Format, level Level) *slog.Logger {
+	if w == nil {
+		w = io.Discard
+	}
+
+	opts := &slog.HandlerOptions{Level: level.slogLevel()}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Configure sets slog logger with format and level, writing to w, as default logger of this package.
+// Empty format and level default to text and warn
+func Configure(w io.Writer, format, level string) error {
+	var (
+		f   = FormatText
+		l   = LevelWarn
+		err error
+	)
+	if format != "" {
+		if f, err = ParseFormat(format); err != nil {
+			return err
+		}
+	}
+	if level != "" {
+		if l, err = ParseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	SetDefault(NewSlogLogger(w, f, l))
+	return nil
+}
+
+// Handler is an attribute with handler name
+func Handler(name string) slog.Attr {
+	return slog.String("handler", name)
+}
+
+// Pkg is an attribute with package path
+func Pkg(path string) slog.Attr {
+	return slog.String("pkg", path)
+}
+
+// Pos is an attribute with source position in file:line:column format
+func Pos(pos token.Position) slog.Attr {
+	return slog.String("pos", pos.String())
+}
+
 type stdLogger struct {
 	w     io.Writer
 	level Level
@@ -125,13 +228,19 @@ func formatAttrs(attrs []any) string {
 	}
 
 	parts := make([]string, 0, (len(attrs)+1)/2)
-	for i := 0; i < len(attrs); i += 2 {
+	for i := 0; i < len(attrs); i++ {
+		if attr, ok := attrs[i].(slog.Attr); ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", attr.Key, attr.Value))
+			continue
+		}
+
 		key := fmt.Sprint(attrs[i])
 		if i+1 >= len(attrs) {
 			parts = append(parts, key)
 			continue
 		}
 		parts = append(parts, fmt.Sprintf("%s=%v", key, attrs[i+1]))
+		i++
 	}
 
 	return strings.Join(parts, ", ")
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{in: "debug", want: LevelDebug},
+		{in: "INFO", want: LevelInfo},
+		{in: "warn", want: LevelWarn},
+		{in: "warning", want: LevelWarn},
+		{in: "error", want: LevelError},
+		{in: "trace", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(&buf, FormatJSON, LevelInfo)
+
+	logger.Debug("hidden")
+	logger.Info(
+		"found handler",
+		Pkg("example.com/api"),
+		Handler("GetUser"),
+		Pos(token.Position{Filename: "users.go", Line: 42, Column: 9}),
+		"count", 2,
+	)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "found handler", record["msg"])
+	require.Equal(t, "example.com/api", record["pkg"])
+	require.Equal(t, "GetUser", record["handler"])
+	require.Equal(t, "users.go:42:9", record["pos"])
+	require.Equal(t, float64(2), record["count"])
+}
+
+func TestStdLoggerAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(&buf, LevelDebug)
+
+	logger.Warn("handler not found", Pkg("example.com/api"), "name", "GetUser", "dangling")
+	require.Equal(t, "warning: handler not found (pkg=example.com/api, name=GetUser, dangling)\n", buf.String())
+}
@@ -0,0 +1,117 @@
+package typed
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/internal/parser/headers"
+	"github.com/d1vbyz3r0/typed/internal/parser/request/path"
+	"github.com/d1vbyz3r0/typed/internal/parser/request/query"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+// ModelSource provides schemas for models, found by parser in handlers and packages
+type ModelSource interface {
+	// GenerateRefs generates schemas for all known models and saves components to schemas
+	GenerateRefs(schemas openapi3.Schemas) error
+	// SchemaRef returns schema for model and saves referenced components to schemas
+	SchemaRef(model *typing.Type, schemas openapi3.Schemas) (*openapi3.SchemaRef, error)
+	// Params returns parameters declared in bind model with binding tags.
+	// in is one of openapi3.ParameterInPath, openapi3.ParameterInQuery or openapi3.ParameterInHeader
+	Params(model *typing.Type, in string) (openapi3.Parameters, error)
+}
+
+type registryModelSource struct {
+	generator *openapi3gen.Generator
+	registry  *Registry
+}
+
+// NewRegistryModelSource creates ModelSource, which generates schemas from registry values with reflection
+func NewRegistryModelSource(g *openapi3gen.Generator, registry *Registry) ModelSource {
+	return &registryModelSource{
+		generator: g,
+		registry:  registry,
+	}
+}
+
+func (s *registryModelSource) GenerateRefs(schemas openapi3.Schemas) error {
+	return GenerateRefs(s.generator, schemas, s.registry)
+}
+
+func (s *registryModelSource) SchemaRef(model *typing.Type, schemas openapi3.Schemas) (*openapi3.SchemaRef, error) {
+	val, ok := s.registry.LookupValue(model)
+	if !ok {
+		return nil, fmt.Errorf("model not found in registry: %s", model)
+	}
+
+	return s.generator.NewSchemaRefForValue(val, schemas)
+}
+
+func (s *registryModelSource) Params(model *typing.Type, in string) (openapi3.Parameters, error) {
+	obj, ok := s.registry.LookupValue(model)
+	if !ok {
+		return nil, fmt.Errorf("bind model not found in registry: %s", model)
+	}
+
+	t := typing.DerefReflectPtr(reflect.TypeOf(obj))
+	var params openapi3.Parameters
+	add := func(param *openapi3.Parameter, paramType reflect.Type) error {
+		schema, err := s.generator.GenerateSchemaRef(paramType)
+		if err != nil {
+			return fmt.Errorf("failed to generate schema ref for param %s: %w", param.Name, err)
+		}
+
+		param.Schema = &openapi3.SchemaRef{
+			Value: schema.Value,
+		}
+		params = append(params, &openapi3.ParameterRef{Value: param})
+		return nil
+	}
+
+	switch in {
+	case openapi3.ParameterInPath:
+		typedParams, err := path.NewStructPathParams(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract path params from bind model: %w", err)
+		}
+
+		for _, p := range typedParams {
+			// TODO: process required more precise
+			if err := add(openapi3.NewPathParameter(p.Name).WithRequired(true), p.Type); err != nil {
+				return nil, err
+			}
+		}
+
+	case openapi3.ParameterInQuery:
+		typedParams, err := query.NewStructQueryParams(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract query params from bind model: %w", err)
+		}
+
+		for _, p := range typedParams {
+			isRequired := p.Type.Kind() != reflect.Pointer
+			if err := add(openapi3.NewQueryParameter(p.Name).WithRequired(isRequired), p.Type); err != nil {
+				return nil, err
+			}
+		}
+
+	case openapi3.ParameterInHeader:
+		typedParams, err := headers.NewStructRequestHeaders(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract header params from bind model: %w", err)
+		}
+
+		for _, p := range typedParams {
+			if err := add(openapi3.NewHeaderParameter(p.Name).WithRequired(p.Required), p.Type); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported parameter location: %s", in)
+	}
+
+	return params, nil
+}
@@ -3,13 +3,10 @@ package typed
 import (
 	"fmt"
 	"net/http"
-	"reflect"
 
-	"github.com/d1vbyz3r0/typed/common/typing"
 	"github.com/d1vbyz3r0/typed/handlers"
 	"github.com/d1vbyz3r0/typed/internal/parser/headers"
-	"github.com/d1vbyz3r0/typed/internal/parser/request/path"
-	"github.com/d1vbyz3r0/typed/internal/parser/request/query"
+	"github.com/d1vbyz3r0/typed/internal/parser/response"
 	"github.com/d1vbyz3r0/typed/logging"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
@@ -19,7 +16,10 @@ type OperationBuilder struct {
 	op        *openapi3.Operation
 	handler   handlers.Handler
 	generator *openapi3gen.Generator
-	registry  *Registry
+	models    ModelSource
+	namer     *ComponentNamer
+	// defaulted are inline parameters, which types aren't inferred from usage
+	defaulted []*openapi3.Parameter
 	err       error
 }
 
@@ -32,39 +32,27 @@ func NewOperationBuilder(
 		op:        openapi3.NewOperation(),
 		handler:   h,
 		generator: g,
-		registry:  reg,
+		models:    NewRegistryModelSource(g, reg),
 	}
 }
 
-func (b *OperationBuilder) AddPathParams() *OperationBuilder {
-	b.step("add path params", func() error {
-		params := make(map[string]path.Param, len(b.handler.PathParams()))
-		model := b.handler.BindModel()
-		if model != nil {
-			obj, ok := b.registry.LookupValue(model)
-			if !ok {
-				return fmt.Errorf("bind model not found in registry: %s", model)
-			}
-
-			typedParams, err := path.NewStructPathParams(typing.DerefReflectPtr(reflect.TypeOf(obj)))
-			if err != nil {
-				return fmt.Errorf("failed to extract path params from bind model: %w", err)
-			}
+// WithModelSource sets source of model schemas. By default, models are looked up in registry
+func (b *OperationBuilder) WithModelSource(models ModelSource) *OperationBuilder {
+	b.models = models
+	return b
+}
 
-			for _, p := range typedParams {
-				// TODO: process required more precise
-				param := openapi3.NewPathParameter(p.Name).WithRequired(true)
-				schema, err := b.generator.GenerateSchemaRef(p.Type)
-				if err != nil {
-					return fmt.Errorf("failed to generate schema ref for param %s: %w", p.Name, err)
-				}
+// WithComponentNamer sets namer, used to give operation-based names to anonymous schemas, such as inline forms
+func (b *OperationBuilder) WithComponentNamer(namer *ComponentNamer) *OperationBuilder {
+	b.namer = namer
+	return b
+}
 
-				param.Schema = &openapi3.SchemaRef{
-					Value: schema.Value,
-				}
-				params[p.Name] = p
-				b.op.AddParameter(param)
-			}
+func (b *OperationBuilder) AddPathParams() *OperationBuilder {
+	b.step("add path params", func() error {
+		params, err := b.addModelParams(openapi3.ParameterInPath)
+		if err != nil {
+			return err
 		}
 
 		for _, p := range b.handler.PathParams() {
@@ -84,6 +72,9 @@ func (b *OperationBuilder) AddPathParams() *OperationBuilder {
 				Value: schema.Value,
 			}
 			b.op.AddParameter(param)
+			if !p.Inferred {
+				b.defaulted = append(b.defaulted, param)
+			}
 		}
 
 		return nil
@@ -94,33 +85,9 @@ func (b *OperationBuilder) AddPathParams() *OperationBuilder {
 
 func (b *OperationBuilder) AddQueryParams() *OperationBuilder {
 	b.step("add query params", func() error {
-		params := make(map[string]query.Param, len(b.handler.QueryParams()))
-		model := b.handler.BindModel()
-		if model != nil {
-			obj, ok := b.registry.LookupValue(model)
-			if !ok {
-				return fmt.Errorf("bind model not found in registry: %s", model)
-			}
-
-			typedParams, err := query.NewStructQueryParams(typing.DerefReflectPtr(reflect.TypeOf(obj)))
-			if err != nil {
-				return fmt.Errorf("failed to extract query params from bind model: %w", err)
-			}
-
-			for _, p := range typedParams {
-				isRequired := p.Type.Kind() != reflect.Pointer
-				param := openapi3.NewQueryParameter(p.Name).WithRequired(isRequired)
-				schema, err := b.generator.GenerateSchemaRef(p.Type)
-				if err != nil {
-					return fmt.Errorf("failed to generate schema ref for param %s: %w", p.Name, err)
-				}
-
-				param.Schema = &openapi3.SchemaRef{
-					Value: schema.Value,
-				}
-				params[p.Name] = p
-				b.op.AddParameter(param)
-			}
+		params, err := b.addModelParams(openapi3.ParameterInQuery)
+		if err != nil {
+			return err
 		}
 
 		for _, p := range b.handler.QueryParams() {
@@ -140,6 +107,9 @@ func (b *OperationBuilder) AddQueryParams() *OperationBuilder {
 				Value: schema.Value,
 			}
 			b.op.AddParameter(param)
+			if !p.Inferred {
+				b.defaulted = append(b.defaulted, param)
+			}
 		}
 
 		return nil
@@ -148,12 +118,36 @@ func (b *OperationBuilder) AddQueryParams() *OperationBuilder {
 	return b
 }
 
+// addModelParams adds parameters declared in bind model and returns set of their names
+func (b *OperationBuilder) addModelParams(in string) (map[string]struct{}, error) {
+	model := b.handler.BindModel()
+	if model == nil {
+		return nil, nil
+	}
+
+	params, err := b.models.Params(model, in)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(params))
+	for _, p := range params {
+		names[p.Value.Name] = struct{}{}
+		b.op.AddParameter(p.Value)
+	}
+	return names, nil
+}
+
 func (b *OperationBuilder) AddRequestBody(schemas openapi3.Schemas) *OperationBuilder {
 	b.step("add request body", func() error {
 		content := make(openapi3.Content)
 		request := b.handler.Request()
 		for contentType, reqBody := range request.ContentTypeMapping {
 			if reqBody.Form != nil {
+				if b.namer != nil {
+					b.namer.Hint(reqBody.Form, b.handler.HandlerName()+"Form")
+				}
+
 				ref, err := b.generator.GenerateSchemaRef(reqBody.Form)
 				if err != nil {
 					return fmt.Errorf("failed to generate schema ref for form: %w", err)
@@ -162,16 +156,11 @@ func (b *OperationBuilder) AddRequestBody(schemas openapi3.Schemas) *OperationBu
 			}
 
 			if request.ModelType == nil {
-				logging.Debug("request contains empty bind model", "handler", b.handler.HandlerName())
+				logging.Debug("request contains empty bind model", logging.Handler(b.handler.HandlerName()))
 				continue
 			}
 
-			obj, ok := b.registry.LookupValue(request.ModelType)
-			if !ok {
-				return fmt.Errorf("bind model not found in registry: %s", request.ModelType)
-			}
-
-			ref, err := b.generator.NewSchemaRefForValue(obj, schemas)
+			ref, err := b.models.SchemaRef(request.ModelType, schemas)
 			if err != nil {
 				return fmt.Errorf("failed to generate schema ref for bind model %s: %w", request.ModelType, err)
 			}
@@ -198,30 +187,89 @@ func (b *OperationBuilder) AddResponses(schemas openapi3.Schemas) *OperationBuil
 			return nil
 		}
 
+		inlineObjects := 0
+		for _, responses := range statusCodeMapping {
+			for _, resp := range responses {
+				if resp.Object != nil {
+					inlineObjects++
+				}
+			}
+		}
+
 		for status, responses := range statusCodeMapping {
 			content := make(openapi3.Content, len(responses))
 			mergedHeaders := make([]headers.Header, 0, len(responses))
+			// different models returned with same status code and content type are merged into oneOf
+			contentRefs := make(map[string][]*openapi3.SchemaRef, len(responses))
+			seenModels := make(map[string]struct{}, len(responses))
+			// schemas of server-sent events payloads by content type
+			eventRefs := make(map[string]*openapi3.SchemaRef)
+
+			for i, resp := range responses {
+				mergedHeaders = append(mergedHeaders, resp.Headers...)
+				if resp.ContentType == "" {
+					continue
+				}
+
+				refs := contentRefs[resp.ContentType]
+				if resp.Object != nil {
+					name := b.handler.HandlerName() + "Response"
+					if inlineObjects > 1 {
+						name = fmt.Sprintf("%s%dResponse", b.handler.HandlerName(), status)
+					}
+
+					key := fmt.Sprintf("%s %s %d %d", b.handler.Method(), b.handler.Path(), status, i)
+					ref, err := b.inlineObjectRef(resp.Object, key, name, schemas)
+					if err != nil {
+						return fmt.Errorf("failed to generate schema ref for inline response object: %w", err)
+					}
+
+					refs = append(refs, ref)
+				}
 
-			for _, resp := range responses {
-				mediaType := openapi3.NewMediaType()
 				if resp.ModelType != nil {
-					val, ok := b.registry.LookupValue(resp.ModelType)
-					if !ok {
-						return fmt.Errorf("response model type not found in registry: %s", resp.ModelType)
+					k := resp.ContentType + " " + resp.ModelType.String()
+					if _, ok := seenModels[k]; ok {
+						continue
 					}
+					seenModels[k] = struct{}{}
 
-					ref, err := b.generator.NewSchemaRefForValue(val, schemas)
+					ref, err := b.models.SchemaRef(resp.ModelType, schemas)
 					if err != nil {
 						return fmt.Errorf("failed to generate schema ref for response model %s: %w", resp.ModelType, err)
 					}
 
-					mediaType = mediaType.WithSchemaRef(ref)
+					refs = append(refs, ref)
 				}
+				if resp.EventType != nil {
+					ref, err := b.models.SchemaRef(resp.EventType, schemas)
+					if err != nil {
+						return fmt.Errorf("failed to generate schema ref for event type %s: %w", resp.EventType, err)
+					}
+					eventRefs[resp.ContentType] = ref
+				}
+				contentRefs[resp.ContentType] = refs
+			}
 
-				mergedHeaders = append(mergedHeaders, resp.Headers...)
-				if resp.ContentType != "" {
-					content[resp.ContentType] = mediaType
+			for contentType, refs := range contentRefs {
+				mediaType := openapi3.NewMediaType()
+				switch len(refs) {
+				case 0:
+					if contentType == response.MIMETextEventStream {
+						mediaType = mediaType.WithSchema(openapi3.NewStringSchema())
+					}
+				case 1:
+					mediaType = mediaType.WithSchemaRef(refs[0])
+				default:
+					mediaType = mediaType.WithSchema(&openapi3.Schema{OneOf: refs})
 				}
+
+				if ref, ok := eventRefs[contentType]; ok {
+					mediaType.Extensions = map[string]any{
+						"x-sse-event-schema": ref,
+					}
+				}
+				content[contentType] = mediaType
 			}
 
 			resp := openapi3.
@@ -229,7 +277,7 @@ func (b *OperationBuilder) AddResponses(schemas openapi3.Schemas) *OperationBuil
 				WithContent(content).
 				WithDescription(http.StatusText(status))
 
-			logging.Debug("going to set headers for response", "handler", b.handler.HandlerName(), "status_code", status, "responses", responses)
+			logging.Debug("going to set headers for response", logging.Handler(b.handler.HandlerName()), "status_code", status, "responses", responses)
 
 			resp.Headers = make(openapi3.Headers, len(mergedHeaders))
 			for _, header := range mergedHeaders {
@@ -263,35 +311,64 @@ func (b *OperationBuilder) AddResponses(schemas openapi3.Schemas) *OperationBuil
 	return b
 }
 
-func (b *OperationBuilder) AddHeaders() *OperationBuilder {
-	b.step("add headers", func() error {
-		model := b.handler.BindModel()
-		request := b.handler.Request()
-		params := make(map[string]struct{}, len(request.Headers))
-		if model != nil {
-			obj, ok := b.registry.LookupValue(model)
-			if !ok {
-				return fmt.Errorf("bind model type not found in registry: %s", model)
+// inlineObjectRef saves inline object schema as component, named after operation, and returns reference to it
+func (b *OperationBuilder) inlineObjectRef(
+	obj *response.InlineObject,
+	key string,
+	name string,
+	schemas openapi3.Schemas,
+) (*openapi3.SchemaRef, error) {
+	schema, err := b.inlineObjectSchema(obj, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.namer != nil {
+		name = b.namer.SyntheticName(key, name)
+	}
+
+	schemas[name] = openapi3.NewSchemaRef("", schema)
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, schema), nil
+}
+
+func (b *OperationBuilder) inlineObjectSchema(obj *response.InlineObject, schemas openapi3.Schemas) (*openapi3.Schema, error) {
+	schema := openapi3.NewObjectSchema()
+	for _, p := range obj.Properties {
+		var ref *openapi3.SchemaRef
+		switch {
+		case p.Object != nil:
+			nested, err := b.inlineObjectSchema(p.Object, schemas)
+			if err != nil {
+				return nil, err
 			}
+			ref = openapi3.NewSchemaRef("", nested)
 
-			typedParams, err := headers.NewStructRequestHeaders(typing.DerefReflectPtr(reflect.TypeOf(obj)))
+		case p.Type != nil:
+			var err error
+			ref, err = b.models.SchemaRef(p.Type, schemas)
 			if err != nil {
-				return fmt.Errorf("failed to extract header params from bind model: %w", err)
+				return nil, fmt.Errorf("property %s: %w", p.Name, err)
 			}
 
-			for _, p := range typedParams {
-				param := openapi3.NewHeaderParameter(p.Name).WithRequired(p.Required)
-				schema, err := b.generator.GenerateSchemaRef(p.Type)
-				if err != nil {
-					return fmt.Errorf("failed to generate schema ref for header param %s: %w", p.Name, err)
-				}
+		default:
+			// free-form value
+			ref = openapi3.NewSchemaRef("", &openapi3.Schema{})
+		}
 
-				param.Schema = &openapi3.SchemaRef{
-					Value: schema.Value,
-				}
-				params[p.Name] = struct{}{}
-				b.op.AddParameter(param)
-			}
+		schema.Properties[p.Name] = ref
+		if p.Required {
+			schema.Required = append(schema.Required, p.Name)
+		}
+	}
+	return schema, nil
+}
+
+func (b *OperationBuilder) AddHeaders() *OperationBuilder {
+	b.step("add headers", func() error {
+		request := b.handler.Request()
+		params, err := b.addModelParams(openapi3.ParameterInHeader)
+		if err != nil {
+			return err
 		}
 
 		for _, header := range request.Headers {
@@ -347,6 +424,11 @@ func (b *OperationBuilder) AddOperationDescription() *OperationBuilder {
 	return b
 }
 
+// DefaultedParams returns inline path and query parameters, which types aren't inferred from usage and default to string
+func (b *OperationBuilder) DefaultedParams() []*openapi3.Parameter {
+	return b.defaulted
+}
+
 func (b *OperationBuilder) Build() (*openapi3.Operation, error) {
 	return b.op, b.err
 }
@@ -0,0 +1,180 @@
+package typed
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
+	"github.com/d1vbyz3r0/typed/internal/parser/response"
+	"github.com/d1vbyz3r0/typed/testdata/dto"
+	"github.com/d1vbyz3r0/typed/testdata/polymorph"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const dtoPkg = "github.com/d1vbyz3r0/typed/testdata/dto"
+
+func TestOperationBuilder_AddResponsesMergesModels(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(dto.User), Type: typing.Named(dtoPkg, "User")},
+		T{Val: new(polymorph.Created), Type: typing.Named(polymorphPkg, "Created")},
+	)
+
+	h := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/users"},
+		nil,
+		parser.Handler{
+			Name:    "getUser",
+			Request: &request.Request{},
+			Responses: response.StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(dtoPkg, "User")},
+					{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(polymorphPkg, "Created")},
+					{ContentType: echo.MIMEApplicationJSON, ModelType: typing.Named(dtoPkg, "User")},
+					{ContentType: echo.MIMEApplicationXML, ModelType: typing.Named(dtoPkg, "User")},
+				},
+			},
+		},
+	)
+
+	schemas := make(openapi3.Schemas)
+	op, err := NewOperationBuilder(NewGenerator(registry), h, registry).
+		AddResponses(schemas).
+		Build()
+	require.NoError(t, err)
+
+	resp := op.Responses.Status(http.StatusOK).Value
+	json := resp.Content.Get(echo.MIMEApplicationJSON).Schema
+	require.Empty(t, json.Ref)
+	require.Len(t, json.Value.OneOf, 2)
+	require.Equal(t, "#/components/schemas/dto.User", json.Value.OneOf[0].Ref)
+	require.Equal(t, "#/components/schemas/polymorph.Created", json.Value.OneOf[1].Ref)
+
+	xml := resp.Content.Get(echo.MIMEApplicationXML).Schema
+	require.Equal(t, "#/components/schemas/dto.User", xml.Ref)
+}
+
+func TestOperationBuilder_AddResponsesInlineObjects(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(dto.User), Type: typing.Named(dtoPkg, "User")},
+		T{Val: new(string), Type: typing.Basic("string")},
+	)
+
+	user := &response.InlineObject{
+		Properties: []response.InlineProperty{
+			{Name: "user", Required: true, Type: typing.Named(dtoPkg, "User")},
+			{Name: "meta", Object: &response.InlineObject{
+				Properties: []response.InlineProperty{
+					{Name: "source", Required: true, Type: typing.Basic("string")},
+					{Name: "extra", Required: true},
+				},
+			}},
+		},
+	}
+	errObj := &response.InlineObject{
+		Properties: []response.InlineProperty{
+			{Name: "error", Required: true, Type: typing.Basic("string")},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		responses response.StatusCodeMapping
+		want      map[int]string
+	}{
+		{
+			name: "single inline object is named after operation",
+			responses: response.StatusCodeMapping{
+				http.StatusOK: {{ContentType: echo.MIMEApplicationJSON, Object: user}},
+			},
+			want: map[int]string{http.StatusOK: "getUserResponse"},
+		},
+		{
+			name: "multiple inline objects are named after status codes",
+			responses: response.StatusCodeMapping{
+				http.StatusOK:         {{ContentType: echo.MIMEApplicationJSON, Object: user}},
+				http.StatusBadRequest: {{ContentType: echo.MIMEApplicationJSON, Object: errObj}},
+			},
+			want: map[int]string{
+				http.StatusOK:         "getUser200Response",
+				http.StatusBadRequest: "getUser400Response",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := handlers.NewHandler(
+				echo.Route{Method: http.MethodGet, Path: "/users"},
+				nil,
+				parser.Handler{
+					Name:      "getUser",
+					Request:   &request.Request{},
+					Responses: tc.responses,
+				},
+			)
+
+			schemas := make(openapi3.Schemas)
+			op, err := NewOperationBuilder(NewGenerator(registry), h, registry).
+				WithComponentNamer(NewComponentNamer(registry)).
+				AddResponses(schemas).
+				Build()
+			require.NoError(t, err)
+
+			for status, name := range tc.want {
+				ref := op.Responses.Status(status).Value.Content.Get(echo.MIMEApplicationJSON).Schema
+				require.Equal(t, "#/components/schemas/"+name, ref.Ref)
+				require.Contains(t, schemas, name)
+			}
+
+			if name, ok := tc.want[http.StatusOK]; ok {
+				schema := schemas[name].Value
+				require.Equal(t, []string{"user"}, schema.Required)
+				require.Equal(t, "#/components/schemas/dto.User", schema.Properties["user"].Ref)
+
+				meta := schema.Properties["meta"].Value
+				require.True(t, meta.Type.Is(openapi3.TypeObject))
+				require.True(t, meta.Properties["source"].Value.Type.Is(openapi3.TypeString))
+				require.Nil(t, meta.Properties["extra"].Value.Type, "unknown values are free-form")
+			}
+		})
+	}
+}
+
+func TestOperationBuilder_AddResponsesServerSentEvents(t *testing.T) {
+	registry := MustNewRegistry(
+		T{Val: new(dto.User), Type: typing.Named(dtoPkg, "User")},
+	)
+
+	h := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/users/events"},
+		nil,
+		parser.Handler{
+			Name:    "userEvents",
+			Request: &request.Request{},
+			Responses: response.StatusCodeMapping{
+				http.StatusOK: {
+					{ContentType: response.MIMETextEventStream, EventType: typing.Named(dtoPkg, "User")},
+				},
+			},
+		},
+	)
+
+	schemas := make(openapi3.Schemas)
+	op, err := NewOperationBuilder(NewGenerator(registry), h, registry).
+		AddResponses(schemas).
+		Build()
+	require.NoError(t, err)
+
+	media := op.Responses.Status(http.StatusOK).Value.Content.Get(response.MIMETextEventStream)
+	require.NotNil(t, media)
+	require.True(t, media.Schema.Value.Type.Is(openapi3.TypeString))
+
+	event, ok := media.Extensions["x-sse-event-schema"].(*openapi3.SchemaRef)
+	require.True(t, ok)
+	require.Equal(t, "#/components/schemas/dto.User", event.Ref)
+}
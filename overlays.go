@@ -0,0 +1,65 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/d1vbyz3r0/typed/internal/overlay"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ApplyOverlays merges hand-written fragments from files into generated spec. Files are YAML or JSON documents in
+// OpenAPI Overlay 1.0 format with JSONPath targets or partial OpenAPI documents, deep-merged into spec root.
+// Files are applied in order, so later files override values of previous ones. Replaced values and
+// actions with unmatched targets are logged as warnings
+func ApplyOverlays(spec *openapi3.T, paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	overlays := make([]overlay.Overlay, 0, len(paths))
+	for _, path := range paths {
+		o, err := overlay.Load(path)
+		if err != nil {
+			return fmt.Errorf("load overlay: %w", err)
+		}
+		overlays = append(overlays, o)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("unmarshal spec: %w", err)
+	}
+
+	conflicts, unmatched, err := overlay.Apply(doc, overlays...)
+	if err != nil {
+		return fmt.Errorf("apply overlays: %w", err)
+	}
+
+	for _, c := range conflicts {
+		logging.Warn("overlay replaced spec value", "overlay", c.Overlay, "path", c.Path, "old", c.Old, "new", c.New)
+	}
+
+	for _, target := range unmatched {
+		logging.Warn("overlay action target matched nothing", "target", target)
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal merged spec: %w", err)
+	}
+
+	var merged openapi3.T
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return fmt.Errorf("unmarshal merged spec: %w", err)
+	}
+
+	*spec = merged
+	return nil
+}
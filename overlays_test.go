@@ -0,0 +1,60 @@
+package typed
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverlays(t *testing.T) {
+	dir := t.TempDir()
+	overlayPath := filepath.Join(dir, "docs.yaml")
+	require.NoError(t, os.WriteFile(overlayPath, []byte(`
+overlay: 1.0.0
+info: {title: docs, version: 1.0.0}
+actions:
+  - target: $.paths['/users'].get
+    update:
+      description: Lists users
+      responses:
+        "200":
+          content:
+            application/json:
+              example: [{id: 1}]
+  - target: $.servers
+    update:
+      url: https://staging.example.com
+`), 0644))
+
+	partialPath := filepath.Join(dir, "partial.json")
+	require.NoError(t, os.WriteFile(partialPath, []byte(`{"info": {"description": "Users API"}}`), 0644))
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "api", Version: "1"},
+		Servers: openapi3.Servers{{URL: "https://api.example.com"}},
+	}
+	spec.AddOperation("/users", http.MethodGet, &openapi3.Operation{
+		OperationID: "ListUsers",
+		Responses: openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().WithDescription("users").WithJSONSchema(openapi3.NewArraySchema()),
+		})),
+	})
+
+	require.NoError(t, ApplyOverlays(spec, overlayPath, partialPath))
+
+	op := spec.Paths.Find("/users").GetOperation(http.MethodGet)
+	require.Equal(t, "ListUsers", op.OperationID)
+	require.Equal(t, "Lists users", op.Description)
+	media := op.Responses.Status(http.StatusOK).Value.Content.Get("application/json")
+	require.Equal(t, []any{map[string]any{"id": float64(1)}}, media.Example)
+	require.Equal(t, openapi3.TypeArray, media.Schema.Value.Type.Slice()[0])
+	require.Equal(t, "Users API", spec.Info.Description)
+	require.Len(t, spec.Servers, 2)
+
+	require.ErrorContains(t, ApplyOverlays(spec, filepath.Join(dir, "missing.yaml")), "load overlay")
+}
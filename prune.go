@@ -0,0 +1,156 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// pruneComponents removes component schemas, which aren't referenced by spec directly or through other schemas,
+// and security schemes, which aren't required by operations and global security
+func pruneComponents(spec *openapi3.T) error {
+	if spec.Components == nil {
+		return nil
+	}
+
+	if err := pruneSchemas(spec, nil); err != nil {
+		return err
+	}
+
+	pruneSecuritySchemes(spec)
+	return nil
+}
+
+// pruneSchemas removes component schemas, which aren't reachable from spec and extra documents, ex: AsyncAPI document,
+// through references. Schemas with names matching keep are kept along with schemas they reference
+func pruneSchemas(spec *openapi3.T, keep []*regexp.Regexp, docs ...any) error {
+	if spec.Components == nil {
+		return nil
+	}
+
+	schemas := spec.Components.Schemas
+	spec.Components.Schemas = nil
+	queue, err := schemaRefs(spec)
+	spec.Components.Schemas = schemas
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		refs, err := schemaRefs(doc)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, refs...)
+	}
+
+	for name := range schemas {
+		if slices.ContainsFunc(keep, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+			queue = append(queue, name)
+		}
+	}
+
+	reachable, err := reachableSchemas(schemas, queue)
+	if err != nil {
+		return err
+	}
+
+	for name := range schemas {
+		if !reachable[name] {
+			delete(schemas, name)
+		}
+	}
+
+	return nil
+}
+
+// reachableSchemas returns names of schemas, referenced from queue directly or through other schemas
+func reachableSchemas(schemas openapi3.Schemas, queue []string) (map[string]bool, error) {
+	reachable := make(map[string]bool, len(schemas))
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		ref, ok := schemas[name]
+		if !ok || reachable[name] {
+			continue
+		}
+		reachable[name] = true
+
+		refs, err := schemaRefs(ref)
+		if err != nil {
+			return nil, fmt.Errorf("collect refs of schema %s: %w", name, err)
+		}
+		queue = append(queue, refs...)
+	}
+	return reachable, nil
+}
+
+// pruneSecuritySchemes removes security schemes, which aren't required by operations and global security
+func pruneSecuritySchemes(spec *openapi3.T) {
+	required := make(map[string]bool)
+	addRequirements := func(reqs openapi3.SecurityRequirements) {
+		for _, req := range reqs {
+			for name := range req {
+				required[name] = true
+			}
+		}
+	}
+
+	addRequirements(spec.Security)
+	if spec.Paths != nil {
+		for _, item := range spec.Paths.Map() {
+			for _, op := range item.Operations() {
+				if op.Security != nil {
+					addRequirements(*op.Security)
+				}
+			}
+		}
+	}
+
+	for name := range spec.Components.SecuritySchemes {
+		if !required[name] {
+			delete(spec.Components.SecuritySchemes, name)
+		}
+	}
+}
+
+// schemaRefs returns names of component schemas, referenced by v. Discriminator mappings are references too,
+// so any string value pointing to component schema is collected
+func schemaRefs(v any) ([]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	var refs []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		case string:
+			if name, ok := strings.CutPrefix(v, schemaRefPrefix); ok {
+				refs = append(refs, strings.NewReplacer("~1", "/", "~0", "~").Replace(name))
+			}
+		}
+	}
+	walk(doc)
+
+	return refs, nil
+}
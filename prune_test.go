@@ -0,0 +1,70 @@
+package typed
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneSchemas(t *testing.T) {
+	schemaRef := func(name string) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+	}
+
+	tests := []struct {
+		name string
+		keep []*regexp.Regexp
+		docs []any
+		want []string
+	}{
+		{
+			name: "reachable from operations",
+			want: []string{"User", "Address"},
+		},
+		{
+			name: "always included with references",
+			keep: []*regexp.Regexp{regexp.MustCompile(`^Pet$`)},
+			want: []string{"User", "Address", "Pet", "Cat"},
+		},
+		{
+			name: "reachable from extra documents",
+			docs: []any{map[string]any{"payload": map[string]any{"$ref": "#/components/schemas/Event"}}},
+			want: []string{"User", "Address", "Event"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &openapi3.T{
+				Components: &openapi3.Components{
+					Schemas: openapi3.Schemas{
+						"User": openapi3.NewObjectSchema().
+							WithPropertyRef("address", schemaRef("Address")).
+							NewRef(),
+						"Address": openapi3.NewObjectSchema().NewRef(),
+						"Pet":     openapi3.NewObjectSchema().NewRef(),
+						"Cat":     openapi3.NewObjectSchema().NewRef(),
+						"Event":   openapi3.NewObjectSchema().NewRef(),
+						"Unused":  openapi3.NewObjectSchema().WithPropertyRef("user", schemaRef("User")).NewRef(),
+					},
+				},
+			}
+			// Cat is referenced by discriminator mapping only
+			spec.Components.Schemas["Pet"].Value.Discriminator = &openapi3.Discriminator{
+				PropertyName: "type",
+				Mapping:      openapi3.StringMap[openapi3.MappingRef]{"cat": {Ref: "#/components/schemas/Cat"}},
+			}
+			spec.AddOperation("/users/me", http.MethodGet, &openapi3.Operation{
+				Responses: openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{
+					Value: openapi3.NewResponse().WithDescription("OK").WithJSONSchemaRef(schemaRef("User")),
+				})),
+			})
+
+			require.NoError(t, pruneSchemas(spec, tt.keep, tt.docs...))
+			require.ElementsMatch(t, tt.want, keys(spec.Components.Schemas))
+		})
+	}
+}
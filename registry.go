@@ -72,6 +72,15 @@ func (r *Registry) LookupEnumValues(pkg string, name string) ([]any, bool) {
 	return item.Type.EnumValues(), true
 }
 
+// LookupImplementations returns types implementing requested interface and discriminator property name, if interface was registered
+func (r *Registry) LookupImplementations(pkg string, name string) ([]typing.Implementation, string, bool) {
+	item, ok := r.Lookup(pkg, name)
+	if !ok || item.Type.Kind() != typing.TypeKindInterface {
+		return nil, "", false
+	}
+	return item.Type.Implementations(), item.Type.Discriminator(), true
+}
+
 // Values returns iterator over registry items.
 // Items are sorted by type descriptor (typing.Type) string representation
 func (r *Registry) Values() iter.Seq[any] {
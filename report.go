@@ -0,0 +1,203 @@
+package typed
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RouteStatus is a result of processing of registered route
+type RouteStatus string
+
+const (
+	// RouteMatched route is matched with parsed handler and added to spec
+	RouteMatched RouteStatus = "matched"
+	// RouteUnmatched route handler isn't found in parsed packages, route isn't added to spec
+	RouteUnmatched RouteStatus = "unmatched"
+	// RouteIgnored route handler is hidden with //typed:ignore directive
+	RouteIgnored RouteStatus = "ignored"
+	// RouteExcluded route is rejected by route filters
+	RouteExcluded RouteStatus = "excluded"
+)
+
+// ErrLowCoverage is returned by Report.CheckCoverage
+var ErrLowCoverage = errors.New("documentation coverage is below minimum")
+
+// Report describes generation results: how registered routes were processed, which constructs of handlers
+// were skipped by parser and which parameter types weren't inferred. See GenerateOptions.Report
+type Report struct {
+	Summary ReportSummary `json:"summary"`
+	Routes  []RouteReport `json:"routes"`
+}
+
+type ReportSummary struct {
+	Routes    int `json:"routes"`
+	Matched   int `json:"matched"`
+	Unmatched int `json:"unmatched"`
+	Ignored   int `json:"ignored"`
+	Excluded  int `json:"excluded"`
+	// Documented is a number of documented routes, see RouteReport.Documented
+	Documented int `json:"documented"`
+	// Skipped is a number of skipped constructs of matched routes handlers
+	Skipped             int `json:"skipped"`
+	DefaultedParameters int `json:"defaultedParameters"`
+	WithoutResponses    int `json:"withoutResponses"`
+	// Coverage is a percentage of documented routes among routes, which aren't ignored or excluded
+	Coverage float64 `json:"coverage"`
+}
+
+type RouteReport struct {
+	Method string `json:"method"`
+	// Path is an OpenAPI path of route, such as /users/{id}
+	Path    string      `json:"path"`
+	Handler string      `json:"handler"`
+	Status  RouteStatus `json:"status"`
+	// Skipped are constructs of handler, which parser couldn't represent in spec
+	Skipped    []SkippedConstruct `json:"skipped,omitempty"`
+	Parameters []ParameterReport  `json:"parameters,omitempty"`
+	// NoResponses is set, when no responses were found in handler and operation has only default response
+	NoResponses bool `json:"noResponses,omitempty"`
+}
+
+// Documented reports if route is added to spec with responses and nothing in its handler was skipped
+func (r RouteReport) Documented() bool {
+	return r.Status == RouteMatched && !r.NoResponses && len(r.Skipped) == 0
+}
+
+// SkippedConstruct is a construct of handler source code, such as non-constant status code or parameter name
+type SkippedConstruct struct {
+	// Pos is a position in file:line:column format
+	Pos string `json:"pos"`
+	// Code is a stable code of diagnostic, such as TYP001
+	Code string `json:"code"`
+	// Severity is warning or error
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+// ParameterReport describes path or query parameter of operation
+type ParameterReport struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+	// Inferred is false, when type isn't inferred from parameter usage or bind model and defaults to string
+	Inferred bool `json:"inferred"`
+}
+
+// newRouteReport builds report of route, processed with status. Operation is nil, if it isn't built
+func newRouteReport(h handlers.Handler, status RouteStatus, op *openapi3.Operation, defaulted []*openapi3.Parameter) RouteReport {
+	res := RouteReport{
+		Method:  h.Method(),
+		Path:    h.Path(),
+		Handler: h.Pkg() + "." + h.HandlerName(),
+		Status:  status,
+	}
+	if op == nil {
+		return res
+	}
+
+	for _, d := range h.Diagnostics() {
+		res.Skipped = append(res.Skipped, SkippedConstruct{
+			Pos:      d.Pos.String(),
+			Code:     string(d.Code),
+			Severity: string(d.Severity),
+			Reason:   d.Message,
+		})
+	}
+
+	for _, ref := range op.Parameters {
+		p := ref.Value
+		if p == nil || p.In != openapi3.ParameterInPath && p.In != openapi3.ParameterInQuery {
+			continue
+		}
+
+		param := ParameterReport{
+			Name:     p.Name,
+			In:       p.In,
+			Inferred: !slices.Contains(defaulted, p),
+		}
+		if p.Schema != nil && p.Schema.Value != nil {
+			if p.Schema.Value.Type != nil {
+				param.Type = strings.Join(p.Schema.Value.Type.Slice(), ",")
+			}
+			param.Format = p.Schema.Value.Format
+		}
+		res.Parameters = append(res.Parameters, param)
+	}
+
+	res.NoResponses = len(h.Responses()) == 0
+	return res
+}
+
+// summarize sorts routes by path and method and fills summary
+func (r *Report) summarize() {
+	slices.SortStableFunc(r.Routes, func(a, b RouteReport) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+
+	s := ReportSummary{Routes: len(r.Routes)}
+	for _, route := range r.Routes {
+		switch route.Status {
+		case RouteMatched:
+			s.Matched++
+		case RouteUnmatched:
+			s.Unmatched++
+		case RouteIgnored:
+			s.Ignored++
+		case RouteExcluded:
+			s.Excluded++
+		}
+
+		if route.Status != RouteMatched {
+			continue
+		}
+
+		if route.Documented() {
+			s.Documented++
+		}
+		if route.NoResponses {
+			s.WithoutResponses++
+		}
+		s.Skipped += len(route.Skipped)
+		for _, p := range route.Parameters {
+			if !p.Inferred {
+				s.DefaultedParameters++
+			}
+		}
+	}
+
+	s.Coverage = 100
+	if considered := s.Matched + s.Unmatched; considered > 0 {
+		s.Coverage = float64(s.Documented) * 100 / float64(considered)
+	}
+	r.Summary = s
+}
+
+// CheckCoverage returns ErrLowCoverage, if documentation coverage is below minimum percentage
+func (r *Report) CheckCoverage(minimum float64) error {
+	if r.Summary.Coverage < minimum {
+		return fmt.Errorf("%w: %.1f%% < %.1f%%", ErrLowCoverage, r.Summary.Coverage, minimum)
+	}
+	return nil
+}
+
+// SaveReport saves report as JSON document
+func SaveReport(r *Report, outPath string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+
+	if err := os.WriteFile(outPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
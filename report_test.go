@@ -0,0 +1,136 @@
+package typed
+
+import (
+	"encoding/json"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/diag"
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
+	"github.com/d1vbyz3r0/typed/internal/parser/request/path"
+	"github.com/d1vbyz3r0/typed/internal/parser/response"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateReport(t *testing.T) {
+	newHandler := func(method, p string, h parser.Handler) handlers.Handler {
+		h.Pkg = "example.com/app"
+		if h.Request == nil {
+			h.Request = &request.Request{}
+		}
+		return handlers.NewHandler(echo.Route{Method: method, Path: p}, nil, h)
+	}
+	ok := response.StatusCodeMapping{http.StatusOK: {{}}}
+
+	report := &Report{Routes: []RouteReport{
+		{Method: http.MethodGet, Path: "/static", Handler: "example.com/app.Static", Status: RouteUnmatched},
+	}}
+	err := Generate(GenerateOptions{
+		Spec:     &openapi3.T{},
+		Registry: MustNewRegistry(),
+		Handlers: []handlers.Handler{
+			newHandler(http.MethodGet, "/users/:id", parser.Handler{
+				Name: "GetUser",
+				Request: &request.Request{
+					PathParams: []path.Param{{Name: "id", Type: reflect.TypeFor[int64](), Inferred: true}},
+				},
+				Responses: ok,
+			}),
+			newHandler(http.MethodGet, "/orders/:id", parser.Handler{
+				Name:      "GetOrder",
+				Responses: ok,
+				Diagnostics: []diag.Diagnostic{{
+					Pos:      token.Position{Filename: "orders.go", Line: 10, Column: 9},
+					Handler:  "GetOrder",
+					Severity: diag.SeverityError,
+					Code:     diag.NonConstantStatusCode,
+					Message:  "response skipped, unresolved status code",
+				}},
+			}),
+			newHandler(http.MethodGet, "/health", parser.Handler{Name: "Health"}),
+			newHandler(http.MethodGet, "/state", parser.Handler{Name: "State", Responses: ok, Ignored: true}),
+			newHandler(http.MethodGet, "/debug/pprof", parser.Handler{Name: "Pprof", Responses: ok}),
+		},
+		ExcludeRoutes: []RouteFilter{{PathPrefix: "/debug"}},
+		Report:        report,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, ReportSummary{
+		Routes:              6,
+		Matched:             3,
+		Unmatched:           1,
+		Ignored:             1,
+		Excluded:            1,
+		Documented:          1,
+		Skipped:             1,
+		DefaultedParameters: 1,
+		WithoutResponses:    1,
+		Coverage:            25,
+	}, report.Summary)
+
+	require.Equal(t, []RouteReport{
+		{Method: http.MethodGet, Path: "/debug/pprof", Handler: "example.com/app.Pprof", Status: RouteExcluded},
+		{Method: http.MethodGet, Path: "/health", Handler: "example.com/app.Health", Status: RouteMatched, NoResponses: true},
+		{
+			Method:  http.MethodGet,
+			Path:    "/orders/{id}",
+			Handler: "example.com/app.GetOrder",
+			Status:  RouteMatched,
+			Skipped: []SkippedConstruct{{
+				Pos:      "orders.go:10:9",
+				Code:     "TYP001",
+				Severity: "error",
+				Reason:   "response skipped, unresolved status code",
+			}},
+			Parameters: []ParameterReport{
+				{Name: "id", In: openapi3.ParameterInPath, Type: openapi3.TypeString},
+			},
+		},
+		{Method: http.MethodGet, Path: "/state", Handler: "example.com/app.State", Status: RouteIgnored},
+		{Method: http.MethodGet, Path: "/static", Handler: "example.com/app.Static", Status: RouteUnmatched},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/{id}",
+			Handler: "example.com/app.GetUser",
+			Status:  RouteMatched,
+			Parameters: []ParameterReport{
+				{Name: "id", In: openapi3.ParameterInPath, Type: openapi3.TypeInteger, Format: "int64", Inferred: true},
+			},
+		},
+	}, report.Routes)
+
+	require.ErrorIs(t, report.CheckCoverage(50), ErrLowCoverage)
+	require.NoError(t, report.CheckCoverage(25))
+
+	out := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, SaveReport(report, out))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	var saved Report
+	require.NoError(t, json.Unmarshal(data, &saved))
+	require.Equal(t, *report, saved)
+}
+
+func TestReportUnmatched(t *testing.T) {
+	routes := []handlers.EchoRoute{
+		{Route: echo.Route{Method: http.MethodGet, Path: "/users/:id", Name: "example.com/app.GetUser"}},
+		{Route: echo.Route{Method: http.MethodGet, Path: "/files/*", Name: "example.com/app.Files.func1"}},
+	}
+	matched := []handlers.Handler{newTestHandler(http.MethodGet, "/users/:id", "example.com/app")}
+
+	report := &Report{}
+	reportUnmatched(report, routes, matched)
+	require.Equal(t, []RouteReport{
+		{Method: http.MethodGet, Path: "/files/*", Handler: "example.com/app.Files.func1", Status: RouteUnmatched},
+	}, report.Routes)
+}
@@ -0,0 +1,172 @@
+package typed
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// RouteFilter matches operations, all set fields must match
+type RouteFilter struct {
+	// Path is a regex matching operation path, ex: ^/debug/pprof
+	Path string
+	// PathPrefix matches operation path by segments, ex: /api/admin matches /api/admin/users, but not /api/administrators
+	PathPrefix string
+	// Methods match operation method case-insensitively
+	Methods []string
+	// Tags match operations with any of tags
+	Tags []string
+	// Middleware is a regex matching full function name of any route middleware, see GetMiddlewareFuncName
+	Middleware string
+	// Handler is a regex matching handler name
+	Handler string
+	// HandlerPkg is a regex matching handler package import path
+	HandlerPkg string
+	// Name is a regex matching echo route name. It's a full handler function name, unless it's set explicitly
+	Name string
+}
+
+type routeMatcher struct {
+	filter     RouteFilter
+	path       *regexp.Regexp
+	middleware *regexp.Regexp
+	handler    *regexp.Regexp
+	handlerPkg *regexp.Regexp
+	name       *regexp.Regexp
+}
+
+func newRouteMatcher(f RouteFilter) (routeMatcher, error) {
+	m := routeMatcher{filter: f}
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{name: "path", expr: f.Path, dst: &m.path},
+		{name: "middleware", expr: f.Middleware, dst: &m.middleware},
+		{name: "handler", expr: f.Handler, dst: &m.handler},
+		{name: "handler pkg", expr: f.HandlerPkg, dst: &m.handlerPkg},
+		{name: "name", expr: f.Name, dst: &m.name},
+	} {
+		if re.expr == "" {
+			continue
+		}
+
+		compiled, err := regexp.Compile(re.expr)
+		if err != nil {
+			return routeMatcher{}, fmt.Errorf("compile %s regex: %w", re.name, err)
+		}
+		*re.dst = compiled
+	}
+
+	return m, nil
+}
+
+func (m routeMatcher) match(h handlers.Handler, op *openapi3.Operation) bool {
+	return m.matchRoute(h) && m.matchTags(op)
+}
+
+func (m routeMatcher) matchTags(op *openapi3.Operation) bool {
+	return len(m.filter.Tags) == 0 || slices.ContainsFunc(op.Tags, func(tag string) bool {
+		return slices.Contains(m.filter.Tags, tag)
+	})
+}
+
+// matchRoute matches all fields of filter except tags, which are known after operation is built
+func (m routeMatcher) matchRoute(h handlers.Handler) bool {
+	if m.path != nil && !m.path.MatchString(h.Path()) {
+		return false
+	}
+
+	if m.filter.PathPrefix != "" && !hasPathPrefix(h.Path(), m.filter.PathPrefix) {
+		return false
+	}
+
+	if len(m.filter.Methods) > 0 && !slices.ContainsFunc(m.filter.Methods, func(method string) bool {
+		return strings.EqualFold(method, h.Method())
+	}) {
+		return false
+	}
+
+	if m.middleware != nil && !slices.ContainsFunc(h.Middlewares(), func(mw echo.MiddlewareFunc) bool {
+		return m.middleware.MatchString(GetMiddlewareFuncName(mw))
+	}) {
+		return false
+	}
+
+	if m.handler != nil && !m.handler.MatchString(h.HandlerName()) {
+		return false
+	}
+
+	if m.handlerPkg != nil && !m.handlerPkg.MatchString(h.Pkg()) {
+		return false
+	}
+
+	if m.name != nil && !m.name.MatchString(h.RouteName()) {
+		return false
+	}
+
+	return true
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// routeSelector selects operations matching any include filter or all operations, if include filters are empty,
+// and rejects operations matching any exclude filter
+type routeSelector struct {
+	include []routeMatcher
+	exclude []routeMatcher
+}
+
+func newRouteSelector(include, exclude []RouteFilter) (routeSelector, error) {
+	var s routeSelector
+	for i, f := range include {
+		m, err := newRouteMatcher(f)
+		if err != nil {
+			return routeSelector{}, fmt.Errorf("include filter %d: %w", i, err)
+		}
+		s.include = append(s.include, m)
+	}
+
+	for i, f := range exclude {
+		m, err := newRouteMatcher(f)
+		if err != nil {
+			return routeSelector{}, fmt.Errorf("exclude filter %d: %w", i, err)
+		}
+		s.exclude = append(s.exclude, m)
+	}
+
+	return s, nil
+}
+
+// rejects reports if route is excluded regardless of operation tags, so its operation isn't built
+func (s routeSelector) rejects(h handlers.Handler) bool {
+	if len(s.include) > 0 && !slices.ContainsFunc(s.include, func(m routeMatcher) bool {
+		return m.matchRoute(h)
+	}) {
+		return true
+	}
+
+	return slices.ContainsFunc(s.exclude, func(m routeMatcher) bool {
+		return len(m.filter.Tags) == 0 && m.matchRoute(h)
+	})
+}
+
+func (s routeSelector) selects(h handlers.Handler, op *openapi3.Operation) bool {
+	matches := func(m routeMatcher) bool {
+		return m.match(h, op)
+	}
+
+	if len(s.include) > 0 && !slices.ContainsFunc(s.include, matches) {
+		return false
+	}
+	return !slices.ContainsFunc(s.exclude, matches)
+}
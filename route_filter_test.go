@@ -0,0 +1,92 @@
+package typed
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func adminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+func newTestHandler(method, path, pkg string, middlewares ...echo.MiddlewareFunc) handlers.Handler {
+	return handlers.NewHandler(
+		echo.Route{Method: method, Path: path, Name: pkg + ".Handler"},
+		middlewares,
+		parser.Handler{Pkg: pkg, Name: "Handler", Request: &request.Request{}},
+	)
+}
+
+func TestRouteMatcher(t *testing.T) {
+	h := newTestHandler(http.MethodGet, "/api/admin/users/:id", "example.com/app/admin", adminOnly)
+	op := &openapi3.Operation{Tags: []string{"Users"}}
+
+	tests := []struct {
+		name   string
+		filter RouteFilter
+		want   bool
+	}{
+		{name: "path prefix", filter: RouteFilter{PathPrefix: "/api/admin"}, want: true},
+		{name: "path prefix with slash", filter: RouteFilter{PathPrefix: "/api/admin/"}, want: true},
+		{name: "path prefix by segments", filter: RouteFilter{PathPrefix: "/api/adm"}, want: false},
+		{name: "method", filter: RouteFilter{Methods: []string{"post", "get"}}, want: true},
+		{name: "other method", filter: RouteFilter{Methods: []string{http.MethodDelete}}, want: false},
+		{name: "tag", filter: RouteFilter{Tags: []string{"Orders", "Users"}}, want: true},
+		{name: "other tag", filter: RouteFilter{Tags: []string{"Orders"}}, want: false},
+		{name: "middleware", filter: RouteFilter{Middleware: `typed\.adminOnly$`}, want: true},
+		{name: "other middleware", filter: RouteFilter{Middleware: "echo-jwt"}, want: false},
+		{name: "handler pkg", filter: RouteFilter{HandlerPkg: "/admin$"}, want: true},
+		{name: "path", filter: RouteFilter{Path: `^/api/admin/users/\{id\}$`}, want: true},
+		{name: "other path", filter: RouteFilter{Path: "^/debug/pprof"}, want: false},
+		{name: "handler", filter: RouteFilter{Handler: "^Handler$"}, want: true},
+		{name: "other handler", filter: RouteFilter{Handler: "^Health"}, want: false},
+		{name: "route name", filter: RouteFilter{Name: `admin\.Handler$`}, want: true},
+		{name: "all fields must match", filter: RouteFilter{PathPrefix: "/api/admin", HandlerPkg: "/public$"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newRouteMatcher(tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, m.match(h, op))
+		})
+	}
+}
+
+func TestNewRouteMatcher_InvalidRegex(t *testing.T) {
+	_, err := newRouteMatcher(RouteFilter{HandlerPkg: "("})
+	require.ErrorContains(t, err, "compile handler pkg regex")
+}
+
+func TestRouteSelectorRejects(t *testing.T) {
+	h := newTestHandler(http.MethodGet, "/api/admin/users/:id", "example.com/app/admin", adminOnly)
+
+	tests := []struct {
+		name    string
+		include []RouteFilter
+		exclude []RouteFilter
+		want    bool
+	}{
+		{name: "no filters", want: false},
+		{name: "include path", include: []RouteFilter{{PathPrefix: "/api/admin"}}, want: false},
+		{name: "include other path", include: []RouteFilter{{PathPrefix: "/api/public"}}, want: true},
+		{name: "include tag is checked after build", include: []RouteFilter{{Tags: []string{"Orders"}}}, want: false},
+		{name: "exclude middleware", exclude: []RouteFilter{{Middleware: `typed\.adminOnly$`}}, want: true},
+		{name: "exclude path with tag is checked after build", exclude: []RouteFilter{{PathPrefix: "/api/admin", Tags: []string{"Users"}}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newRouteSelector(tt.include, tt.exclude)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, s.rejects(h))
+		})
+	}
+}
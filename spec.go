@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/d1vbyz3r0/typed/asyncapi"
 	"github.com/getkin/kin-openapi/openapi3"
 	"gopkg.in/yaml.v3"
 )
@@ -34,6 +35,41 @@ func getSpecFormat(path string) SpecFormat {
 }
 
 func SaveSpec(spec *openapi3.T, outPath string) error {
+	return saveDocument(spec, outPath)
+}
+
+// LoadSpec loads spec, saved by SaveSpec, from path
+func LoadSpec(path string) (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ParseSpec parses JSON or YAML spec document, ex: document with info, servers and tags, embedded by generator
+func ParseSpec(data []byte) (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	return spec, nil
+}
+
+func MustParseSpec(data []byte) *openapi3.T {
+	spec, err := ParseSpec(data)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// SaveAsyncAPI saves AsyncAPI document in format defined by outPath extension
+func SaveAsyncAPI(doc *asyncapi.Document, outPath string) error {
+	return saveDocument(doc, outPath)
+}
+
+func saveDocument(doc any, outPath string) error {
 	f, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return fmt.Errorf("open file: %w", err)
@@ -45,7 +81,7 @@ func SaveSpec(spec *openapi3.T, outPath string) error {
 	case YamlFormat:
 		enc := yaml.NewEncoder(f)
 		enc.SetIndent(2)
-		err := enc.Encode(spec)
+		err := enc.Encode(doc)
 		if err != nil {
 			return fmt.Errorf("encode spec: %w", err)
 		}
@@ -53,7 +89,7 @@ func SaveSpec(spec *openapi3.T, outPath string) error {
 	case JsonFormat:
 		enc := json.NewEncoder(f)
 		enc.SetIndent("", "  ")
-		err := enc.Encode(spec)
+		err := enc.Encode(doc)
 		if err != nil {
 			return fmt.Errorf("encode spec: %w", err)
 		}
@@ -0,0 +1,59 @@
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/d1vbyz3r0/typed/internal/specdiff"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SpecDiff is a result of specifications comparison, see DiffSpecs
+type SpecDiff = specdiff.Report
+
+// SpecChange is a single change of operation between specifications, classified as breaking or non-breaking
+type SpecChange = specdiff.Change
+
+// ErrBreakingChanges is returned by FailOnBreakingChanges
+var ErrBreakingChanges = errors.New("breaking changes detected")
+
+// DiffSpecs compares operations of base and revision specs. Removed operations and responses, new required
+// parameters and properties of requests, narrowed request enums, changed types and removed required properties
+// of responses are breaking changes.
+func DiffSpecs(base, revision *openapi3.T) SpecDiff {
+	return specdiff.Compare(base, revision)
+}
+
+// FailOnBreakingChanges can be used as GenerateOptions.OnSpecDiff to fail generation on breaking changes
+func FailOnBreakingChanges(diff SpecDiff) error {
+	if !diff.HasBreaking() {
+		return nil
+	}
+	return fmt.Errorf("%w:\n%s", ErrBreakingChanges, diff)
+}
+
+// diffBaseline compares generated spec with baseline spec and passes changes to opts.OnSpecDiff.
+// Comparison is skipped, if baseline doesn't exist yet
+func diffBaseline(opts GenerateOptions) error {
+	if _, err := os.Stat(opts.BaselineSpecPath); errors.Is(err, os.ErrNotExist) {
+		logging.Info("baseline spec not found, comparison skipped", "path", opts.BaselineSpecPath)
+		return nil
+	}
+
+	baseline, err := LoadSpec(opts.BaselineSpecPath)
+	if err != nil {
+		return fmt.Errorf("load baseline spec: %w", err)
+	}
+
+	diff := DiffSpecs(baseline, opts.Spec)
+	if opts.OnSpecDiff != nil {
+		return opts.OnSpecDiff(diff)
+	}
+
+	for _, change := range diff.Breaking() {
+		logging.Warn("breaking change", "operation", change.Operation, "location", change.Location, "change", change.Message)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package typed
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SpecOutput is an additional document, ex: public or admin API, built by Generate from operations of generated spec,
+// which match route filters. Document has component schemas and security schemes, referenced by its operations only
+type SpecOutput struct {
+	// Name identifies document in logs
+	Name string
+	// Path is a path of document file, see SaveSpecOutputs
+	Path string
+	// Spec holds info, servers, tags, external docs and security of document, paths and components are set by Generate.
+	// Generated spec info and servers are used, if it's nil
+	Spec *openapi3.T
+	// Include selects operations matching any of filters. All operations are selected, if it's empty
+	Include []RouteFilter
+	// Exclude rejects selected operations matching any of filters
+	Exclude []RouteFilter
+	// Overlays are paths of overlay files, merged into document by SaveSpecOutputs. See ApplyOverlays
+	Overlays []string
+}
+
+// builtOperation is an operation of generated spec with its handler, used to select operations of spec outputs
+type builtOperation struct {
+	handler handlers.Handler
+	op      *openapi3.Operation
+}
+
+type specOutputBuilder struct {
+	routeSelector
+	output *SpecOutput
+}
+
+func newSpecOutputBuilder(output *SpecOutput) (specOutputBuilder, error) {
+	s, err := newRouteSelector(output.Include, output.Exclude)
+	if err != nil {
+		return specOutputBuilder{}, err
+	}
+	return specOutputBuilder{routeSelector: s, output: output}, nil
+}
+
+// build fills document with selected operations and components of spec, referenced by them
+func (b specOutputBuilder) build(spec *openapi3.T, ops []builtOperation) error {
+	out := b.output.Spec
+	if out == nil {
+		out = &openapi3.T{
+			OpenAPI: spec.OpenAPI,
+			Info:    spec.Info,
+			Servers: spec.Servers,
+		}
+		b.output.Spec = out
+	}
+
+	components := *spec.Components
+	components.Schemas = maps.Clone(spec.Components.Schemas)
+	components.SecuritySchemes = maps.Clone(spec.Components.SecuritySchemes)
+	out.Components = &components
+	out.Paths = openapi3.NewPaths()
+
+	selected := 0
+	for _, o := range ops {
+		if b.selects(o.handler, o.op) {
+			out.AddOperation(o.handler.Path(), o.handler.Method(), o.op)
+			selected++
+		}
+	}
+
+	if err := pruneComponents(out); err != nil {
+		return fmt.Errorf("prune components: %w", err)
+	}
+
+	logging.Debug("built spec output", "name", b.output.Name, "operations", selected, "schemas", len(out.Components.Schemas))
+	return nil
+}
+
+// SaveSpecOutputs merges overlays into documents, built by Generate, and saves them
+func SaveSpecOutputs(outputs ...*SpecOutput) error {
+	for _, o := range outputs {
+		if o.Spec == nil {
+			return fmt.Errorf("spec %s isn't generated", o.Name)
+		}
+
+		if err := ApplyOverlays(o.Spec, o.Overlays...); err != nil {
+			return fmt.Errorf("apply overlays to spec %s: %w", o.Name, err)
+		}
+
+		if err := SaveSpec(o.Spec, o.Path); err != nil {
+			return fmt.Errorf("save spec %s: %w", o.Name, err)
+		}
+	}
+	return nil
+}
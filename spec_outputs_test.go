@@ -0,0 +1,96 @@
+package typed
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecOutputBuilder_build(t *testing.T) {
+	schemaRef := func(name string) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+	}
+	response := func(name string) *openapi3.Responses {
+		return openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().WithDescription("OK").WithJSONSchemaRef(schemaRef(name)),
+		}))
+	}
+	bearer := openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate("bearer"))
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "app", Version: "1.0.0"},
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"User": openapi3.NewObjectSchema().
+					WithPropertyRef("address", schemaRef("Address")).
+					NewRef(),
+				"Address": openapi3.NewObjectSchema().NewRef(),
+				"Pet":     openapi3.NewObjectSchema().NewRef(),
+				"Cat":     openapi3.NewObjectSchema().NewRef(),
+				"Stats":   openapi3.NewObjectSchema().NewRef(),
+			},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"bearer": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+				"apiKey": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().WithType("apiKey")},
+			},
+		},
+	}
+	// Cat is referenced by discriminator mapping only
+	spec.Components.Schemas["Pet"].Value.Discriminator = &openapi3.Discriminator{
+		PropertyName: "type",
+		Mapping:      openapi3.StringMap[openapi3.MappingRef]{"cat": {Ref: "#/components/schemas/Cat"}},
+	}
+
+	ops := []builtOperation{
+		{
+			handler: newTestHandler(http.MethodGet, "/users/me", "example.com/app/users"),
+			op:      &openapi3.Operation{OperationID: "GetUser", Responses: response("User")},
+		},
+		{
+			handler: newTestHandler(http.MethodGet, "/pets", "example.com/app/pets"),
+			op:      &openapi3.Operation{OperationID: "ListPets", Responses: response("Pet"), Security: bearer},
+		},
+		{
+			handler: newTestHandler(http.MethodGet, "/admin/stats", "example.com/app/admin"),
+			op:      &openapi3.Operation{OperationID: "Stats", Responses: response("Stats")},
+		},
+	}
+
+	output := &SpecOutput{
+		Name:    "public",
+		Path:    filepath.Join(t.TempDir(), "public.yaml"),
+		Exclude: []RouteFilter{{PathPrefix: "/admin"}},
+	}
+	b, err := newSpecOutputBuilder(output)
+	require.NoError(t, err)
+	require.NoError(t, b.build(spec, ops))
+
+	public := output.Spec
+	require.Equal(t, "app", public.Info.Title)
+	require.NotNil(t, public.Paths.Find("/users/me").Get)
+	require.NotNil(t, public.Paths.Find("/pets").Get)
+	require.Nil(t, public.Paths.Find("/admin/stats"))
+	require.ElementsMatch(t, []string{"User", "Address", "Pet", "Cat"}, keys(public.Components.Schemas))
+	require.ElementsMatch(t, []string{"bearer"}, keys(public.Components.SecuritySchemes))
+
+	// generated spec isn't modified
+	require.Len(t, spec.Components.Schemas, 5)
+	require.Len(t, spec.Components.SecuritySchemes, 2)
+
+	require.NoError(t, SaveSpecOutputs(output))
+	saved, err := LoadSpec(output.Path)
+	require.NoError(t, err)
+	require.NoError(t, saved.Validate(t.Context()))
+}
+
+func keys[M ~map[string]V, V any](m M) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
@@ -0,0 +1,822 @@
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	staticMinInt8   = float64(math.MinInt8)
+	staticMaxInt8   = float64(math.MaxInt8)
+	staticMinInt16  = float64(math.MinInt16)
+	staticMaxInt16  = float64(math.MaxInt16)
+	staticZero      = float64(0)
+	staticMaxUint8  = float64(math.MaxUint8)
+	staticMaxUint16 = float64(math.MaxUint16)
+	staticMaxUint32 = float64(math.MaxUint32)
+	staticMaxUint64 = float64(math.MaxUint64)
+)
+
+// StaticModelSource builds model schemas directly from go/types, so spec can be generated without compiling
+// and running generated program. Schemas mirror the ones produced by NewGenerator with default customizers:
+// json/form/xml field naming, required non-nullable fields, excluded param/query/header fields, enums,
+// interfaces as oneOf, time, uuid, multipart files and, with WithStaticEmbeddedAllOf, allOf composition of embedded
+// structs. Customizers registered with RegisterCustomizer are not applied.
+type StaticModelSource struct {
+	pkgs          map[string]*types.Package
+	models        []*typing.Type
+	enums         map[string][]any
+	ifaces        map[string]*typing.Type
+	namer         *ComponentNamer
+	components    openapi3.Schemas
+	inProgress    map[string]struct{}
+	embeddedAllOf bool
+}
+
+type StaticModelSourceOpt func(s *StaticModelSource)
+
+// WithStaticEmbeddedAllOf renders structs with embedded named structs as allOf composition, same as WithEmbeddedAllOf
+// does for reflection-based generation
+func WithStaticEmbeddedAllOf() StaticModelSourceOpt {
+	return func(s *StaticModelSource) {
+		s.embeddedAllOf = true
+	}
+}
+
+// NewStaticModelSource creates model source for models declared in pkgs or their dependencies.
+// Component names are assigned with namer, same as for reflection-based generation.
+func NewStaticModelSource(pkgs []*packages.Package, models []*typing.Type, namer *ComponentNamer, opts ...StaticModelSourceOpt) (*StaticModelSource, error) {
+	if namer == nil {
+		return nil, errors.New("namer is required")
+	}
+
+	s := &StaticModelSource{
+		pkgs:       make(map[string]*types.Package),
+		models:     models,
+		enums:      make(map[string][]any),
+		ifaces:     make(map[string]*typing.Type),
+		namer:      namer,
+		components: make(openapi3.Schemas),
+		inProgress: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.Types != nil {
+			s.addPackage(pkg.Types)
+		}
+	}
+
+	var resolved []types.Type
+	for _, model := range models {
+		switch model.Kind() {
+		case typing.TypeKindEnum:
+			s.enums[makeTypeKey(model.Pkg(), model.Name())] = model.EnumValues()
+		case typing.TypeKindInterface:
+			s.ifaces[makeTypeKey(model.Pkg(), model.Name())] = model
+		}
+
+		t, err := s.goType(model)
+		if err != nil {
+			logging.Warn("failed to resolve model type, skipping", "model", model, "error", err)
+			continue
+		}
+		resolved = append(resolved, t)
+	}
+
+	var (
+		named []namedComponent
+		anons []anonStruct
+	)
+	seen := make(map[string]struct{})
+	for _, t := range resolved {
+		collectStaticComponentTypes(t, seen, &named, &anons)
+	}
+	namer.assignAll(named, anons)
+
+	return s, nil
+}
+
+func (s *StaticModelSource) addPackage(pkg *types.Package) {
+	if _, ok := s.pkgs[pkg.Path()]; ok {
+		return
+	}
+
+	s.pkgs[pkg.Path()] = pkg
+	for _, imp := range pkg.Imports() {
+		s.addPackage(imp)
+	}
+}
+
+func (s *StaticModelSource) GenerateRefs(schemas openapi3.Schemas) error {
+	for _, model := range s.models {
+		if _, err := s.SchemaRef(model, schemas); err != nil {
+			return fmt.Errorf("schema ref for %s: %w", model, err)
+		}
+	}
+	return nil
+}
+
+func (s *StaticModelSource) SchemaRef(model *typing.Type, schemas openapi3.Schemas) (*openapi3.SchemaRef, error) {
+	t, err := s.goType(model)
+	if err != nil {
+		return nil, fmt.Errorf("resolve model type: %w", err)
+	}
+
+	ref, err := s.schemaRef(t, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == nil {
+		return nil, fmt.Errorf("unsupported model type: %s", model)
+	}
+
+	for name, component := range s.components {
+		if schemas != nil {
+			schemas[name] = component
+		}
+	}
+	return ref, nil
+}
+
+func (s *StaticModelSource) Params(model *typing.Type, in string) (openapi3.Parameters, error) {
+	t, err := s.goType(model)
+	if err != nil {
+		return nil, fmt.Errorf("resolve bind model type: %w", err)
+	}
+
+	for {
+		ptr, ok := types.Unalias(t).(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("expected struct, got %s", t)
+	}
+
+	var tagName string
+	switch in {
+	case openapi3.ParameterInPath:
+		tagName = "param"
+	case openapi3.ParameterInQuery:
+		tagName = "query"
+	case openapi3.ParameterInHeader:
+		tagName = "header"
+	default:
+		return nil, fmt.Errorf("unsupported parameter location: %s", in)
+	}
+
+	var params openapi3.Parameters
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		name, ok := reflect.StructTag(st.Tag(i)).Lookup(tagName)
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+
+		_, isPointer := types.Unalias(f.Type()).(*types.Pointer)
+		param := &openapi3.Parameter{
+			Name: name,
+			In:   in,
+			// path params are always required, query params and headers are optional only if they are pointers,
+			// as in parameters of registry models
+			Required: in == openapi3.ParameterInPath || !isPointer,
+		}
+
+		ref, err := s.schemaRef(f.Type(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate schema ref for param %s: %w", name, err)
+		}
+
+		if ref != nil {
+			value := ref.Value
+			if value == nil {
+				value = s.components[strings.TrimPrefix(ref.Ref, "#/components/schemas/")].Value
+			}
+			param.Schema = &openapi3.SchemaRef{Value: value}
+		}
+
+		params = append(params, &openapi3.ParameterRef{Value: param})
+	}
+
+	return params, nil
+}
+
+// goType converts type descriptor to go/types type
+func (s *StaticModelSource) goType(t *typing.Type) (types.Type, error) {
+	switch t.Kind() {
+	case typing.TypeKindNamed, typing.TypeKindEnum, typing.TypeKindInterface:
+		pkg, ok := s.pkgs[t.Pkg()]
+		if !ok {
+			return nil, fmt.Errorf("package %s not loaded", t.Pkg())
+		}
+
+		obj, ok := pkg.Scope().Lookup(t.Name()).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", t.Name(), t.Pkg())
+		}
+
+		if len(t.Params()) == 0 {
+			return obj.Type(), nil
+		}
+
+		args := make([]types.Type, 0, len(t.Params()))
+		for _, p := range t.Params() {
+			arg, err := s.goType(p)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		return types.Instantiate(nil, obj.Type(), args, true)
+
+	case typing.TypeKindBasic:
+		obj := types.Universe.Lookup(t.Name())
+		if obj == nil {
+			return nil, fmt.Errorf("unknown basic type %s", t.Name())
+		}
+		return obj.Type(), nil
+
+	case typing.TypeKindPointer, typing.TypeKindSlice, typing.TypeKindArray:
+		elem, err := s.goType(t.ElemType())
+		if err != nil {
+			return nil, err
+		}
+
+		switch t.Kind() {
+		case typing.TypeKindPointer:
+			return types.NewPointer(elem), nil
+		case typing.TypeKindSlice:
+			return types.NewSlice(elem), nil
+		default:
+			return types.NewArray(elem, t.Size()), nil
+		}
+
+	case typing.TypeKindMap:
+		key, err := s.goType(t.KeyType())
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := s.goType(t.ValueType())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, val), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", typing.ErrTypeUnsupported, t)
+	}
+}
+
+// schemaRef returns schema for t. Named and anonymous structs with properties are saved to components and referenced.
+// Result is nil for types, which can't be described, such as functions and channels.
+func (s *StaticModelSource) schemaRef(t types.Type, root bool) (*openapi3.SchemaRef, error) {
+	nullable := false
+	t = types.Unalias(t)
+	for {
+		ptr, ok := t.(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = types.Unalias(ptr.Elem())
+		nullable = !root
+	}
+
+	schema := &openapi3.Schema{Nullable: nullable}
+	named, _ := t.(*types.Named)
+	if named != nil {
+		if special, ok := specialTypeSchema(named); ok {
+			special.Nullable = nullable
+			return openapi3.NewSchemaRef("", special), nil
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		basicSchema(u, schema)
+
+	case *types.Slice:
+		elem := types.Unalias(u.Elem())
+		if b, ok := elem.Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			schema.Type = &openapi3.Types{openapi3.TypeString}
+			schema.Format = "byte"
+			break
+		}
+
+		schema.Type = &openapi3.Types{openapi3.TypeArray}
+		if isStaticFileHeader(elem) {
+			schema.Items = openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:   &openapi3.Types{openapi3.TypeString},
+				Format: "binary",
+			})
+			break
+		}
+
+		items, err := s.schemaRef(elem, false)
+		if err != nil {
+			return nil, err
+		}
+		schema.Items = items
+
+	case *types.Map:
+		schema.Type = &openapi3.Types{openapi3.TypeObject}
+		additional, err := s.schemaRef(u.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		if additional != nil {
+			schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: additional}
+		}
+
+	case *types.Interface:
+		if named != nil {
+			if err := s.addImplementations(named, schema); err != nil {
+				return nil, err
+			}
+		}
+
+	case *types.Struct:
+		return s.structRef(t, u, nullable)
+
+	case *types.Signature, *types.Chan:
+		return nil, nil
+	}
+
+	if named != nil && named.Obj().Pkg() != nil {
+		if vals, ok := s.enums[makeTypeKey(named.Obj().Pkg().Path(), named.Obj().Name())]; ok {
+			schema.Enum = make([]any, len(vals))
+			copy(schema.Enum, vals)
+		}
+	}
+
+	return openapi3.NewSchemaRef("", schema), nil
+}
+
+// structRef returns reference to struct component. Structs without properties are inlined, since openapi3gen
+// doesn't export them as components either.
+func (s *StaticModelSource) structRef(t types.Type, st *types.Struct, nullable bool) (*openapi3.SchemaRef, error) {
+	key := staticTypeKey(t)
+	pkg, name := staticComponentName(t)
+	if existing, ok := s.namer.names[key]; ok {
+		if _, ok := s.components[existing]; ok {
+			return openapi3.NewSchemaRef("#/components/schemas/"+existing, nil), nil
+		}
+	}
+
+	if _, ok := s.inProgress[key]; ok {
+		// cyclic reference, component will be saved once its generation is finished
+		return openapi3.NewSchemaRef("#/components/schemas/"+s.namer.resolve(key, pkg, name), nil), nil
+	}
+
+	s.inProgress[key] = struct{}{}
+	schema, err := s.structSchema(st)
+	delete(s.inProgress, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schema.Properties) == 0 && len(schema.AllOf) == 0 {
+		schema.Nullable = nullable
+		return openapi3.NewSchemaRef("", schema), nil
+	}
+
+	componentName := s.namer.resolve(key, pkg, name)
+	s.components[componentName] = openapi3.NewSchemaRef("", schema)
+	return openapi3.NewSchemaRef("#/components/schemas/"+componentName, nil), nil
+}
+
+func (s *StaticModelSource) structSchema(st *types.Struct) (*openapi3.Schema, error) {
+	if s.embeddedAllOf {
+		if own, embedded := splitComposedEmbedded(st); len(embedded) > 0 {
+			return s.composedSchema(own, embedded)
+		}
+	}
+
+	schema := &openapi3.Schema{}
+	if err := s.addFields(schema, st); err != nil {
+		return nil, err
+	}
+
+	if schema.Properties != nil {
+		schema.Type = &openapi3.Types{openapi3.TypeObject}
+	}
+
+	schema.Required = staticRequiredFieldNames(st)
+	return schema, nil
+}
+
+// composedSchema describes struct as allOf of embedded struct components and schema of own fields,
+// as newEmbeddedAllOfCustomizer does
+func (s *StaticModelSource) composedSchema(own *types.Struct, embedded []*types.Var) (*openapi3.Schema, error) {
+	layers := make([]*openapi3.SchemaRef, 0, len(embedded)+1)
+	for _, f := range embedded {
+		ref, err := s.schemaRef(f.Type(), false)
+		if err != nil {
+			return nil, fmt.Errorf("embedded %s: %w", f.Name(), err)
+		}
+
+		if ref != nil {
+			layers = append(layers, ref)
+		}
+	}
+
+	ownLayer, err := s.structSchema(own)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ownLayer.Properties) > 0 {
+		ownLayer.Type = &openapi3.Types{openapi3.TypeObject}
+		layers = append(layers, openapi3.NewSchemaRef("", ownLayer))
+	}
+
+	return &openapi3.Schema{AllOf: layers}, nil
+}
+
+// splitComposedEmbedded returns struct without embedded named structs, which are inlined by encoding/json and
+// embedded by value, and these embedded fields. It mirrors composedEmbeddedStructs
+func splitComposedEmbedded(st *types.Struct) (*types.Struct, []*types.Var) {
+	var (
+		fields   []*types.Var
+		tags     []string
+		embedded []*types.Var
+	)
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if isComposedEmbedded(f, reflect.StructTag(st.Tag(i))) {
+			embedded = append(embedded, f)
+			continue
+		}
+
+		fields = append(fields, f)
+		tags = append(tags, st.Tag(i))
+	}
+
+	if len(embedded) == 0 {
+		return st, nil
+	}
+	return types.NewStruct(fields, tags), embedded
+}
+
+func isComposedEmbedded(f *types.Var, tag reflect.StructTag) bool {
+	if !f.Embedded() || !f.Exported() || jsonTagName(tag) != "" {
+		return false
+	}
+
+	named, ok := types.Unalias(f.Type()).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	if _, ok := specialTypeSchema(named); ok {
+		return false
+	}
+
+	_, ok = named.Underlying().(*types.Struct)
+	return ok
+}
+
+// addFields adds struct fields to schema properties. Embedded structs without json tag are flattened.
+func (s *StaticModelSource) addFields(schema *openapi3.Schema, st *types.Struct) error {
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		jsonTag, hasJSON := tag.Lookup("json")
+		if f.Embedded() {
+			if jsonTag == "-" {
+				continue
+			}
+
+			if jsonTag == "" {
+				if embedded, ok := derefStatic(f.Type()).Underlying().(*types.Struct); ok {
+					if err := s.addFields(schema, embedded); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+
+		if !f.Exported() || jsonTag == "-" {
+			continue
+		}
+
+		if isNonBodyTag(tag) {
+			logging.Debug("removed field from final schema since it's not in body", "field", f.Name(), "tag", tag)
+			continue
+		}
+
+		name := f.Name()
+		if n, _, _ := strings.Cut(jsonTag, ","); n != "" {
+			name = n
+		}
+
+		if !hasJSON || jsonTag == "" {
+			if v, ok := tag.Lookup("yaml"); ok && v != "-" {
+				name = v
+			}
+		}
+
+		name = FieldNameGenerator(reflect.StructField{Name: f.Name(), Tag: tag}, name)
+		ref, err := s.schemaRef(f.Type(), false)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name(), err)
+		}
+
+		if ref != nil {
+			schema.WithPropertyRef(name, ref)
+		}
+	}
+	return nil
+}
+
+// addImplementations describes interface as oneOf of its implementations, as NewPolymorphismCustomizer does
+func (s *StaticModelSource) addImplementations(named *types.Named, schema *openapi3.Schema) error {
+	if named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	iface, ok := s.ifaces[makeTypeKey(named.Obj().Pkg().Path(), named.Obj().Name())]
+	if !ok {
+		return nil
+	}
+
+	mapping := make(openapi3.StringMap[openapi3.MappingRef], len(iface.Implementations()))
+	for _, impl := range iface.Implementations() {
+		implType, err := s.goType(impl.Type)
+		if err != nil {
+			logging.Debug("interface implementation not resolved, skipping", "interface", iface, "type", impl.Type, "error", err)
+			continue
+		}
+
+		ref, err := s.schemaRef(implType, false)
+		if err != nil {
+			return fmt.Errorf("implementation %s: %w", impl.Type, err)
+		}
+
+		if ref == nil || ref.Ref == "" {
+			continue
+		}
+
+		schema.OneOf = append(schema.OneOf, openapi3.NewSchemaRef(ref.Ref, nil))
+		if impl.DiscriminatorValue != "" {
+			mapping[impl.DiscriminatorValue] = openapi3.MappingRef{Ref: ref.Ref}
+		}
+	}
+
+	if iface.Discriminator() != "" && len(schema.OneOf) > 0 {
+		schema.Discriminator = &openapi3.Discriminator{PropertyName: iface.Discriminator()}
+		if len(mapping) > 0 {
+			schema.Discriminator.Mapping = mapping
+		}
+	}
+
+	return nil
+}
+
+// specialTypeSchema returns schemas for types, which are described by customizers or openapi3gen itself
+func specialTypeSchema(named *types.Named) (*openapi3.Schema, bool) {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil, false
+	}
+
+	switch obj.Pkg().Path() + "." + obj.Name() {
+	case "time.Time":
+		return &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "date-time"}, true
+	case "github.com/google/uuid.UUID":
+		return &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "uuid"}, true
+	case "mime/multipart.FileHeader":
+		return &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "binary"}, true
+	case "encoding/json.RawMessage":
+		return &openapi3.Schema{}, true
+	}
+	return nil, false
+}
+
+func basicSchema(b *types.Basic, schema *openapi3.Schema) {
+	switch b.Kind() {
+	case types.Bool:
+		schema.Type = &openapi3.Types{openapi3.TypeBoolean}
+	case types.Int:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+	case types.Int8:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticMinInt8
+		schema.Max = &staticMaxInt8
+	case types.Int16:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticMinInt16
+		schema.Max = &staticMaxInt16
+	case types.Int32:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Format = "int32"
+	case types.Int64:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Format = "int64"
+	case types.Uint:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticZero
+	case types.Uint8:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticZero
+		schema.Max = &staticMaxUint8
+	case types.Uint16:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticZero
+		schema.Max = &staticMaxUint16
+	case types.Uint32:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticZero
+		schema.Max = &staticMaxUint32
+	case types.Uint64:
+		schema.Type = &openapi3.Types{openapi3.TypeInteger}
+		schema.Min = &staticZero
+		schema.Max = &staticMaxUint64
+	case types.Float32:
+		schema.Type = &openapi3.Types{openapi3.TypeNumber}
+		schema.Format = "float"
+	case types.Float64:
+		schema.Type = &openapi3.Types{openapi3.TypeNumber}
+		schema.Format = "double"
+	case types.String:
+		schema.Type = &openapi3.Types{openapi3.TypeString}
+	}
+}
+
+// staticRequiredFieldNames mirrors requiredFieldNames for go/types structs
+func staticRequiredFieldNames(st *types.Struct) []string {
+	var res []string
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		if f.Embedded() && tag.Get("json") == "" {
+			if _, ok := derefStatic(f.Type()).Underlying().(*types.Struct); ok {
+				// fields of structs, embedded by pointer, may be omitted
+				if embedded, ok := types.Unalias(f.Type()).Underlying().(*types.Struct); ok {
+					res = append(res, staticRequiredFieldNames(embedded)...)
+				}
+				continue
+			}
+		}
+
+		if !f.Exported() {
+			continue
+		}
+
+		field := reflect.StructField{Name: f.Name(), Tag: tag}
+		if isNonBodyField(field) {
+			continue
+		}
+
+		switch f.Type().Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Map:
+			continue
+		}
+		res = append(res, getFieldNameByTag(field))
+	}
+	return res
+}
+
+func isNonBodyTag(tag reflect.StructTag) bool {
+	for _, tagName := range nonBodyTags {
+		if v, ok := tag.Lookup(tagName); ok && v != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+func isStaticFileHeader(t types.Type) bool {
+	named, ok := derefStatic(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "mime/multipart" && named.Obj().Name() == "FileHeader"
+}
+
+func derefStatic(t types.Type) types.Type {
+	t = types.Unalias(t)
+	for {
+		ptr, ok := t.(*types.Pointer)
+		if !ok {
+			return t
+		}
+		t = types.Unalias(ptr.Elem())
+	}
+}
+
+// staticTypeKey identifies go/types type in ComponentNamer
+func staticTypeKey(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+// staticComponentName returns package path and name of type, formatted as reflect.Type.Name does.
+// Name is empty for anonymous structs.
+func staticComponentName(t types.Type) (string, string) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return "", ""
+	}
+
+	obj := named.Obj()
+	pkg := ""
+	if obj.Pkg() != nil {
+		pkg = obj.Pkg().Path()
+	}
+
+	args := named.TypeArgs()
+	if args.Len() == 0 {
+		return pkg, obj.Name()
+	}
+
+	formatted := make([]string, 0, args.Len())
+	for arg := range args.Types() {
+		formatted = append(formatted, types.TypeString(arg, nil))
+	}
+	return pkg, obj.Name() + "[" + strings.Join(formatted, ",") + "]"
+}
+
+// collectStaticComponentTypes mirrors collectComponentTypes for go/types types
+func collectStaticComponentTypes(t types.Type, seen map[string]struct{}, named *[]namedComponent, anons *[]anonStruct) {
+	t = unwrapStaticType(t)
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	if n, ok := t.(*types.Named); ok {
+		if _, special := specialTypeSchema(n); special {
+			return
+		}
+	}
+
+	key := staticTypeKey(t)
+	if _, ok := seen[key]; ok {
+		return
+	}
+	seen[key] = struct{}{}
+
+	if pkg, name := staticComponentName(t); name != "" {
+		*named = append(*named, namedComponent{
+			key:  key,
+			pkg:  pkg,
+			name: name,
+		})
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		ft := unwrapStaticType(f.Type())
+		if _, ok := ft.(*types.Struct); ok {
+			*anons = append(*anons, anonStruct{
+				key:    staticTypeKey(ft),
+				parent: key,
+				field:  f.Name(),
+			})
+		}
+
+		collectStaticComponentTypes(f.Type(), seen, named, anons)
+	}
+}
+
+// unwrapStaticType mirrors unwrapReflectType for go/types types
+func unwrapStaticType(t types.Type) types.Type {
+	for {
+		t = types.Unalias(t)
+		switch u := t.Underlying().(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		default:
+			return t
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package embedded
+
+import "time"
+
+type Base struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Audit struct {
+	UpdatedBy *string `json:"updated_by"`
+}
+
+type User struct {
+	Base
+	Audit `json:",omitempty"`
+	Name  string `json:"name"`
+}
+
+// Shadowed overrides ID of embedded Base
+type Shadowed struct {
+	Base
+	ID string `json:"id"`
+}
+
+// Tagged embeds Base as a regular property, since json tag has name
+type Tagged struct {
+	Base `json:"base"`
+}
+
+// Pointer embeds Base by pointer, so its fields are flattened and optional
+type Pointer struct {
+	*Base
+	Name string `json:"name"`
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
-	"github.com/labstack/echo/v4"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
 )
 
 type Example struct {
@@ -21,3 +27,128 @@ func Handler(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Example{})
 }
+
+type Profile struct {
+	Bio string
+}
+
+func InlineObjectHandler(c echo.Context) error {
+	name := c.QueryParam("name")
+	if name == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"error": "name is required",
+			"code":  400,
+		})
+	}
+
+	if c.QueryParam("anon") == "true" {
+		return c.JSON(http.StatusCreated, struct {
+			ID      int    `json:"id"`
+			Comment string `json:"comment,omitempty"`
+			Meta    struct {
+				Tags []string `json:"tags"`
+			} `json:"meta"`
+		}{})
+	}
+
+	var data map[string]any
+	return c.JSON(http.StatusOK, map[string]any{
+		"name":    name,
+		"profile": &Profile{},
+		"data":    data,
+		"nested":  echo.Map{"ok": true},
+		"nothing": nil,
+	})
+}
+
+func EventsHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	for i := 0; i < 3; i++ {
+		data, err := json.Marshal(&Example{X: "event", Y: i})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.Response(), "data: %s\n\n", data)
+		c.Response().Flush()
+	}
+	return nil
+}
+
+func MixedEventsHandler(c echo.Context) error {
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	enc := json.NewEncoder(c.Response())
+	_ = enc.Encode(Example{})
+	_ = enc.Encode(Profile{})
+	flusher.Flush()
+	return nil
+}
+
+func PingEventsHandler(c echo.Context) error {
+	for i := 0; i < 3; i++ {
+		_, _ = io.WriteString(c.Response(), "data: ping\n\n")
+		c.Response().Flush()
+	}
+	return nil
+}
+
+func FlushHandler(c echo.Context) error {
+	for i := 0; i < 3; i++ {
+		_, _ = c.Response().Write([]byte("chunk"))
+		c.Response().Flush()
+	}
+	return nil
+}
+
+func DataPrefixHandler(c echo.Context) error {
+	line := c.QueryParam("line")
+	if !strings.HasPrefix(line, "data:") {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	return c.String(http.StatusOK, strings.TrimPrefix(line, "data:"))
+}
+
+func NDJSONHandler(c echo.Context) error {
+	items := []Example{{X: "a", Y: 1}, {X: "b", Y: 2}}
+	for _, item := range items {
+		if err := json.NewEncoder(c.Response()).Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NDJSONFlushHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	enc := json.NewEncoder(c.Response())
+	for i := 0; i < 3; i++ {
+		if err := enc.Encode(Example{Y: i}); err != nil {
+			return err
+		}
+		c.Response().Flush()
+	}
+	return nil
+}
+
+func XMLStreamHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationXMLCharsetUTF8)
+	c.Response().WriteHeader(http.StatusAccepted)
+	enc := xml.NewEncoder(c.Response())
+	return enc.Encode(&Profile{})
+}
+
+func CopyHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+	_, err := io.Copy(c.Response(), strings.NewReader("a,b\n"))
+	return err
+}
+
+func RawWriteHandler(c echo.Context) error {
+	_, err := c.Response().Write([]byte("raw"))
+	return err
+}
@@ -0,0 +1,21 @@
+package dto
+
+type User struct {
+	Name    string `json:"name"`
+	Address struct {
+		City   string `json:"city"`
+		Street struct {
+			Name string `json:"name"`
+		} `json:"street"`
+	} `json:"address"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type User struct {
+	ID string `json:"id"`
+}
+
+type Group struct {
+	Users []User `json:"users"`
+}
@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AppContext struct {
+	echo.Context
+	UserID int
+}
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+// Wrap adapts handler with application context to echo.HandlerFunc
+func Wrap(h func(c *AppContext) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h(&AppContext{Context: c})
+	}
+}
+
+// Named adapts handler with application context, adding name to errors
+func Named(name string, h func(c *AppContext) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := h(&AppContext{Context: c}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, name+": "+err.Error())
+		}
+		return nil
+	}
+}
+
+func GetUser(c *AppContext) error {
+	return c.JSON(http.StatusOK, User{ID: c.UserID})
+}
+
+func CreateUser(c *AppContext) error {
+	var req CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, User{Name: req.Name})
+}
+
+func Register(e *echo.Echo) {
+	e.GET("/me", Wrap(GetUser))
+	e.POST("/users", Named("create", CreateUser))
+}
@@ -0,0 +1,102 @@
+package detection
+
+import (
+	"net/http"
+
+	e "github.com/labstack/echo/v4"
+)
+
+type (
+	Handler = e.HandlerFunc
+	Ctx     = e.Context
+)
+
+// AppContext is a custom context, embedding echo context
+type AppContext struct {
+	e.Context
+}
+
+type Request struct {
+	Name string `json:"name"`
+}
+
+type Result struct {
+	ID int `json:"id"`
+}
+
+type Server struct{}
+
+// Method is a struct method handler
+func (s *Server) Method(c e.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Aliased uses aliased echo import and context alias
+func Aliased(ctx Ctx) error {
+	var req Request
+	if err := ctx.Bind(&req); err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, Result{})
+}
+
+// Wrapper returns alias of echo.HandlerFunc and responds with custom context
+func Wrapper() Handler {
+	return func(c e.Context) error {
+		cc := c.(*AppContext)
+		return cc.JSON(http.StatusCreated, Result{})
+	}
+}
+
+// Variable is a function literal handler
+var Variable = func(c e.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+type holder struct {
+	c e.Context
+}
+
+// Hidden is excluded from spec
+//
+//typed:ignore
+func Hidden(c e.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func FieldContext(c e.Context) error {
+	h := holder{c: c}
+	return h.c.JSON(http.StatusAccepted, Result{})
+}
+
+func NotHandler(x int) error {
+	return nil
+}
+
+var notHandler = func(x int) error {
+	return nil
+}
+
+// Skipped has constructs, which can't be resolved statically
+func Skipped(c e.Context) error {
+	name := "id"
+	id := c.Param(name)
+	status := http.StatusOK
+	return c.String(status, id)
+}
+
+type Router struct {
+	router *e.Echo
+}
+
+func (s *Router) MapRoutes() {
+	middleware := func(next e.HandlerFunc) e.HandlerFunc { return next }
+	s.router.GET("/inline", func(c e.Context) error {
+		return c.JSON(http.StatusOK, Result{})
+	}, middleware)
+	// nested literals are not parsed
+	register := func() {
+		s.router.GET("/nested", func(c e.Context) error { return nil })
+	}
+	register()
+}
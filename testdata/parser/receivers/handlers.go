@@ -0,0 +1,45 @@
+package receivers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Order struct {
+	ID    int `json:"id"`
+	Total int `json:"total"`
+}
+
+type UserHandler struct{}
+
+// List returns users
+func (h *UserHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, []User{})
+}
+
+type OrderHandler struct{}
+
+// List returns orders
+func (h OrderHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, []Order{})
+}
+
+// Health reports service status
+func Health() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func Register(e *echo.Echo) {
+	users := &UserHandler{}
+	orders := OrderHandler{}
+	e.GET("/users", users.List)
+	e.GET("/orders", orders.List)
+}
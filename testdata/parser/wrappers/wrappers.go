@@ -0,0 +1,54 @@
+package wrappers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+type ListUsersRequest struct {
+	Limit int `query:"limit"`
+}
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Handle binds request and responds with result of fn
+func Handle[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error)) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req Req
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, Error{Message: err.Error()})
+		}
+
+		resp, err := fn(c.Request().Context(), req)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, Error{Message: err.Error()})
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func CreateUser(ctx context.Context, req CreateUserRequest) (User, error) {
+	return User{Name: req.Name}, nil
+}
+
+func ListUsers(ctx context.Context, req ListUsersRequest) ([]User, error) {
+	return make([]User, 0, req.Limit), nil
+}
+
+func Register(e *echo.Echo) {
+	e.POST("/users", Handle[CreateUserRequest, User](CreateUser))
+	e.GET("/users", Handle(ListUsers))
+}
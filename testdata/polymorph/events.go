@@ -0,0 +1,41 @@
+package polymorph
+
+const eventDeleted = "deleted"
+
+type Event interface {
+	Type() string
+}
+
+type Created struct {
+	Kind string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func (Created) Type() string {
+	return "created"
+}
+
+type Deleted struct {
+	Kind   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+func (*Deleted) Type() string {
+	return eventDeleted
+}
+
+type Unknown struct {
+	Kind string `json:"type"`
+}
+
+func (u Unknown) Type() string {
+	return u.Kind
+}
+
+type Envelope struct {
+	Event Event `json:"event"`
+}
+
+type Shape interface {
+	Area() float64
+}
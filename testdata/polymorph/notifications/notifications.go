@@ -0,0 +1,33 @@
+package notifications
+
+type Notification interface {
+	Type() string
+}
+
+type Email struct {
+	Kind string `json:"type"`
+	To   string `json:"to"`
+}
+
+func (Email) Type() string {
+	return "email"
+}
+
+// Push has no type property, so it can't be selected by discriminator
+type Push struct {
+	Token string `json:"token"`
+}
+
+func (Push) Type() string {
+	return "push"
+}
+
+// SMS type property can be omitted
+type SMS struct {
+	Kind  string `json:"type,omitempty"`
+	Phone string `json:"phone"`
+}
+
+func (SMS) Type() string {
+	return "sms"
+}
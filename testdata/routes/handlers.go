@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type baseHandler struct{}
+
+func (baseHandler) GetUser(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+// UsersHandler inherits handlers from embedded type
+type UsersHandler struct {
+	baseHandler
+}
+
+type ListUsersRequest struct {
+	Limit int `query:"limit"`
+}
+
+func ListUsers(c echo.Context, req ListUsersRequest) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func Handle[T any](fn func(c echo.Context, req T) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req T
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		return fn(c, req)
+	}
+}
+
+func PurgeCache(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+func Ping(c echo.Context) error {
+	return c.String(http.StatusOK, "pong")
+}
+
+func Status(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+var Version = func(c echo.Context) error {
+	return c.String(http.StatusOK, "v1")
+}
+
+func Auth(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+func Audit() echo.MiddlewareFunc {
+	return Auth
+}
+
+func RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Server struct {
+	router *echo.Echo
+	api    *echo.Group
+}
+
+func (s *Server) MapRoutes() {
+	s.api = s.router.Group("/api", Auth)
+	s.mapUsers(s.api.Group("/users"))
+
+	admin := s.api.Group("/admin")
+	admin.Use(Audit())
+	mapAdmin(admin)
+
+	s.router.Any("/ping", Ping)
+	s.router.Match([]string{http.MethodGet, http.MethodHead}, "/status", Status, RateLimit)
+	s.router.GET("/version", Version)
+	s.router.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+func (s *Server) mapUsers(g *echo.Group) {
+	h := &UsersHandler{}
+	g.GET("/:id", h.GetUser)
+	g.GET("", Handle[ListUsersRequest](ListUsers))
+}
+
+func mapAdmin(g *echo.Group) {
+	g.DELETE("/cache", PurgeCache)
+}
+
+// mapTenant registers routes under prefix, known at runtime only, so their paths can't be resolved statically
+func (s *Server) mapTenant(tenant string) {
+	g := s.router.Group("/tenants/" + tenant)
+	g.GET("/status", Status)
+	g.Group("/admin").DELETE("/cache", PurgeCache)
+}
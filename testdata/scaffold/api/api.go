@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID int `json:"id"`
+}
+
+func GetUser(c echo.Context) error {
+	return c.JSON(http.StatusOK, User{})
+}
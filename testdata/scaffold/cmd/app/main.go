@@ -0,0 +1,12 @@
+package main
+
+import "github.com/d1vbyz3r0/typed/testdata/scaffold/server"
+
+// NewServer of main package can't be imported by spec builder
+func NewServer() *server.Server {
+	return server.NewServer()
+}
+
+func main() {
+	NewServer().ProvideRoutes()
+}
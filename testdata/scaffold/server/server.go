@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/d1vbyz3r0/typed/testdata/scaffold/api"
+	"github.com/labstack/echo/v4"
+)
+
+type Server struct {
+	e *echo.Echo
+}
+
+func NewServer() *Server {
+	return &Server{e: echo.New()}
+}
+
+// NewRouter isn't a constructor of routes provider, it takes parameters
+func NewRouter(e *echo.Echo) *Server {
+	return &Server{e: e}
+}
+
+func (s *Server) OnRouteAdded(f func(host string, route echo.Route, handler echo.HandlerFunc, middleware []echo.MiddlewareFunc)) {
+	s.e.OnAddRouteHandler = f
+}
+
+func (s *Server) ProvideRoutes() {
+	s.e.GET("/users/:id", api.GetUser)
+}
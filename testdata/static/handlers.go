@@ -0,0 +1,69 @@
+package static
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type User struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	Address   *Address  `json:"address"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	Manager   *User     `json:"manager"`
+}
+
+type RequestMeta struct {
+	Source string `json:"source"`
+}
+
+type CreateUserRequest struct {
+	RequestMeta
+	Name    string `json:"name"`
+	Role    Role   `json:"role"`
+	TraceID string `header:"X-Trace-Id"`
+}
+
+type UsersHandler struct{}
+
+// GetUser returns user by id
+func (h UsersHandler) GetUser(c echo.Context) error {
+	return c.JSON(http.StatusOK, User{})
+}
+
+func (h UsersHandler) CreateUser(c echo.Context) error {
+	var req CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, User{})
+}
+
+func DeleteUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func Health(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+func Version(c echo.Context) error {
+	return c.String(http.StatusOK, "v1")
+}
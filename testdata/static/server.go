@@ -0,0 +1,26 @@
+package static
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const usersPrefix = "/users"
+
+type Server struct {
+	router *echo.Echo
+}
+
+func (s *Server) MapRoutes() {
+	api := s.router.Group("/api/v1")
+
+	users := api.Group(usersPrefix)
+	h := UsersHandler{}
+	users.GET("/:id", h.GetUser)
+	users.POST("", h.CreateUser)
+	users.Add(http.MethodDelete, "/:id", DeleteUser())
+
+	api.Group("/health").GET("", Health)
+	s.router.GET("/version", Version)
+}
@@ -18,6 +18,14 @@ var (
 	upgrader = gorilla.Upgrader{}
 )
 
+type Event struct {
+	Name string `json:"name"`
+}
+
+type Command struct {
+	Action string `json:"action"`
+}
+
 func XNetWebsocket(c echo.Context) error {
 	xnet.Handler(func(ws *xnet.Conn) {
 		defer ws.Close()
@@ -33,6 +41,10 @@ func XNetWebsocket(c echo.Context) error {
 				c.Logger().Error("failed to write WS message", "error", err)
 			}
 			fmt.Printf("%s\n", msg)
+
+			if err := xnet.JSON.Send(ws, Event{}); err != nil {
+				c.Logger().Error("failed to write WS message", "error", err)
+			}
 		}
 	}).ServeHTTP(c.Response(), c.Request())
 	return nil
@@ -58,6 +70,15 @@ func GorillaWebsocket(c echo.Context) error {
 			c.Logger().Error("failed to read WS message", "error", err)
 		}
 		fmt.Printf("%s\n", msg)
+
+		var cmd Command
+		if err := ws.ReadJSON(&cmd); err != nil {
+			c.Logger().Error("failed to read WS message", "error", err)
+		}
+
+		if err := ws.WriteJSON(Event{Name: cmd.Action}); err != nil {
+			c.Logger().Error("failed to write WS message", "error", err)
+		}
 	}
 }
 
@@ -81,6 +102,11 @@ func CoderWebsocket(c echo.Context) error {
 
 	c.Logger().Printf("received: %v", v)
 
+	err = wsjson.Write(ctx, conn, &Event{})
+	if err != nil {
+		return err
+	}
+
 	conn.Close(coder.StatusNormalClosure, "")
 	return nil
 }
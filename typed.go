@@ -3,10 +3,14 @@ package typed
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 
+	"github.com/d1vbyz3r0/typed/asyncapi"
 	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parsecache"
+	"github.com/d1vbyz3r0/typed/logging"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
 	"github.com/labstack/echo/v4"
@@ -15,26 +19,89 @@ import (
 // GenerateOptions configures OpenAPI generation from parsed handlers and
 // routes registered at runtime.
 type GenerateOptions struct {
-	Generator      *openapi3gen.Generator
+	Generator *openapi3gen.Generator
+	// Namer names component schemas. It's also used by the default Generator, when both are omitted.
+	Namer *ComponentNamer
+	// Models provides model schemas. By default, schemas are generated from Registry values.
+	Models         ModelSource
 	Spec           *openapi3.T
 	Registry       *Registry
 	Routes         []handlers.EchoRoute
 	SearchPatterns []handlers.SearchPattern
-	APIPrefix      *string
-	Concurrency    int
+	// RoutesProviderPkg is an import path of package with routes registration code.
+	// If set, routes found in its source code are used to cross-check runtime handlers matching.
+	RoutesProviderPkg string
+	// Adapters are functions, adapting handlers with custom context to echo.HandlerFunc. Adapted handlers
+	// are matched through routes found in RoutesProviderPkg.
+	Adapters []handlers.Adapter
+	// Handlers are already matched handlers. If set, Routes and SearchPatterns are ignored and finder isn't run.
+	Handlers []handlers.Handler
+	// HandlersData is serialized handlers.Data, embedded into generated program by generator. If set, packages
+	// are not loaded and parsed, handlers are matched with Routes only, SearchPatterns and RoutesProviderPkg are ignored.
+	HandlersData []byte
+	APIPrefix    *string
+	Concurrency  int
+	// AsyncAPI is filled with channels of websocket handlers, if set. See NewAsyncAPIDocument
+	AsyncAPI *asyncapi.Document
+	// CacheDir is a directory of parsing results cache, shared with generator. Unchanged packages are not loaded
+	// with types and parsed again. Cache is disabled, if it's empty. See DefaultCacheDir
+	CacheDir string
+	// BaselineSpecPath is a path of previously saved spec. If set, generated spec is compared with it
+	// and changes are passed to OnSpecDiff. Comparison is skipped, if file doesn't exist
+	BaselineSpecPath string
+	// OnSpecDiff is called with changes between baseline and generated spec. Its error is returned by Generate,
+	// see FailOnBreakingChanges. Breaking changes are logged, if it's nil
+	OnSpecDiff func(diff SpecDiff) error
+	// Outputs are additional documents, built from operations of generated spec. See SpecOutput
+	Outputs []*SpecOutput
+	// IncludeRoutes selects routes added to spec, all routes are added if it's empty
+	IncludeRoutes []RouteFilter
+	// ExcludeRoutes rejects routes selected by IncludeRoutes, ex: health checks or pprof endpoints
+	ExcludeRoutes []RouteFilter
+	// KeepUnusedComponents keeps all generated component schemas. By default, only schemas reachable from operations,
+	// AsyncAPI channels and AlwaysIncludeComponents through references are kept
+	KeepUnusedComponents bool
+	// AlwaysIncludeComponents are regexes of component schema names, kept in spec with schemas they reference,
+	// ex: models referenced by overlays only
+	AlwaysIncludeComponents []string
+	// Report is filled with processing results of routes and documentation coverage, if it's set.
+	// Routes, added to report before generation, such as unmatched static routes, are kept
+	Report *Report
+}
+
+// DefaultCacheDir returns default directory of parsing results cache or empty string, if user cache directory is unknown
+func DefaultCacheDir() string {
+	dir, err := parsecache.DefaultDir()
+	if err != nil {
+		logging.Warn("failed to get default cache dir, cache is disabled", "error", err)
+		return ""
+	}
+	return dir
 }
 
 func (o *GenerateOptions) setDefaults() error {
-	if o.Registry == nil {
+	if o.Registry == nil && o.Models == nil {
 		return errors.New("registry is required")
 	}
 
+	if o.Registry == nil {
+		o.Registry = MustNewRegistry()
+	}
+
 	if o.Spec == nil {
 		return errors.New("spec is required")
 	}
 
+	if o.Namer == nil {
+		o.Namer = NewComponentNamer(o.Registry)
+	}
+
 	if o.Generator == nil {
-		o.Generator = NewGenerator(o.Registry)
+		o.Generator = NewGenerator(o.Registry, WithComponentNamer(o.Namer))
+	}
+
+	if o.Models == nil {
+		o.Models = NewRegistryModelSource(o.Generator, o.Registry)
 	}
 
 	if o.Spec.Components == nil {
@@ -62,27 +129,86 @@ func Generate(opts GenerateOptions) error {
 		return err
 	}
 
-	if err := GenerateRefs(opts.Generator, opts.Spec.Components.Schemas, opts.Registry); err != nil {
-		return fmt.Errorf("generate refs: %w", err)
+	selector, err := newRouteSelector(opts.IncludeRoutes, opts.ExcludeRoutes)
+	if err != nil {
+		return fmt.Errorf("route filters: %w", err)
 	}
 
-	finder, err := handlers.NewFinder()
-	if err != nil {
-		return fmt.Errorf("create handlers finder: %w", err)
+	alwaysInclude := make([]*regexp.Regexp, 0, len(opts.AlwaysIncludeComponents))
+	for _, expr := range opts.AlwaysIncludeComponents {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("compile always included components regex: %w", err)
+		}
+		alwaysInclude = append(alwaysInclude, re)
 	}
 
-	err = finder.Find(opts.SearchPatterns, handlers.WithConcurrency(opts.Concurrency))
-	if err != nil {
-		return fmt.Errorf("run finder: %w", err)
+	outputs := make([]specOutputBuilder, 0, len(opts.Outputs))
+	for _, o := range opts.Outputs {
+		b, err := newSpecOutputBuilder(o)
+		if err != nil {
+			return fmt.Errorf("spec output %s: %w", o.Name, err)
+		}
+		outputs = append(outputs, b)
+	}
+
+	if err := opts.Models.GenerateRefs(opts.Spec.Components.Schemas); err != nil {
+		return fmt.Errorf("generate refs: %w", err)
 	}
 
-	matchedHandlers := finder.Match(opts.Routes)
+	matchedHandlers := opts.Handlers
+	if matchedHandlers == nil {
+		finder, err := handlers.NewFinder()
+		if err != nil {
+			return fmt.Errorf("create handlers finder: %w", err)
+		}
+
+		if len(opts.HandlersData) > 0 {
+			err = finder.Load(opts.HandlersData)
+			if err != nil {
+				return fmt.Errorf("load handlers data: %w", err)
+			}
+		} else {
+			err = finder.Find(
+				opts.SearchPatterns,
+				handlers.WithConcurrency(opts.Concurrency),
+				handlers.WithRoutesProviderPkg(opts.RoutesProviderPkg),
+				handlers.WithAdapters(opts.Adapters...),
+				handlers.WithCacheDir(opts.CacheDir),
+			)
+			if err != nil {
+				return fmt.Errorf("run finder: %w", err)
+			}
+		}
+
+		matchedHandlers = finder.Match(opts.Routes)
+		if opts.Report != nil {
+			reportUnmatched(opts.Report, opts.Routes, matchedHandlers)
+		}
+	}
+
+	built := make([]builtOperation, 0, len(matchedHandlers))
 	for _, handler := range matchedHandlers {
+		if handler.Ignored() {
+			logging.Debug("skipping route of handler with ignore directive", "method", handler.Method(), "path", handler.Path(), logging.Handler(handler.HandlerName()))
+			opts.addRoute(newRouteReport(handler, RouteIgnored, nil, nil))
+			continue
+		}
+
+		// path, method, handler and middleware filters are applied before build, so excluded routes can't fail generation
+		if selector.rejects(handler) {
+			logging.Debug("skipping route excluded by route filters", "method", handler.Method(), "path", handler.Path(), logging.Handler(handler.HandlerName()))
+			opts.addRoute(newRouteReport(handler, RouteExcluded, nil, nil))
+			continue
+		}
+
 		b := NewOperationBuilder(
 			opts.Generator,
 			handler,
 			opts.Registry,
 		).
+			WithModelSource(opts.Models).
+			WithComponentNamer(opts.Namer).
 			AddPathParams().
 			AddQueryParams().
 			AddRequestBody(opts.Spec.Components.Schemas).
@@ -100,14 +226,100 @@ func Generate(opts GenerateOptions) error {
 			return fmt.Errorf("build operation %s: %w", handler.HandlerName(), err)
 		}
 
+		if !selector.selects(handler, op) {
+			logging.Debug("skipping route excluded by tag filters", "method", handler.Method(), "path", handler.Path(), logging.Handler(handler.HandlerName()))
+			opts.addRoute(newRouteReport(handler, RouteExcluded, nil, nil))
+			continue
+		}
+		opts.addRoute(newRouteReport(handler, RouteMatched, op, b.DefaultedParams()))
+
 		// TODO: move up from global state
 		RunHandlerHooks(opts.Spec, op, handler)
 		opts.Spec.AddOperation(handler.Path(), handler.Method(), op)
+		built = append(built, builtOperation{handler: handler, op: op})
+
+		if opts.AsyncAPI != nil {
+			err := addAsyncAPIChannel(opts.AsyncAPI, handler, opts.Models, opts.Spec.Components.Schemas)
+			if err != nil {
+				return fmt.Errorf("add asyncapi channel %s: %w", handler.HandlerName(), err)
+			}
+		}
+	}
+
+	if !opts.KeepUnusedComponents {
+		var docs []any
+		if opts.AsyncAPI != nil {
+			docs = append(docs, opts.AsyncAPI)
+		}
+
+		total := len(opts.Spec.Components.Schemas)
+		if err := pruneSchemas(opts.Spec, alwaysInclude, docs...); err != nil {
+			return fmt.Errorf("prune unused components: %w", err)
+		}
+		logging.Debug("pruned unused component schemas", "removed", total-len(opts.Spec.Components.Schemas), "kept", len(opts.Spec.Components.Schemas))
+	}
+
+	if opts.AsyncAPI != nil {
+		schemas, err := channelSchemas(opts.AsyncAPI, opts.Spec.Components.Schemas)
+		if err != nil {
+			return fmt.Errorf("collect asyncapi component schemas: %w", err)
+		}
+
+		if opts.AsyncAPI.Components == nil {
+			opts.AsyncAPI.Components = &asyncapi.Components{}
+		}
+		opts.AsyncAPI.Components.Schemas = schemas
+	}
+
+	for _, b := range outputs {
+		if err := b.build(opts.Spec, built); err != nil {
+			return fmt.Errorf("build spec output %s: %w", b.output.Name, err)
+		}
+	}
+
+	if opts.BaselineSpecPath != "" {
+		if err := diffBaseline(opts); err != nil {
+			return fmt.Errorf("compare with baseline spec: %w", err)
+		}
+	}
+
+	if opts.Report != nil {
+		opts.Report.summarize()
+		s := opts.Report.Summary
+		logging.Info("documentation coverage", "coverage", fmt.Sprintf("%.1f%%", s.Coverage), "routes", s.Routes, "documented", s.Documented, "unmatched", s.Unmatched, "skipped", s.Skipped)
 	}
 
 	return nil
 }
 
+func (o *GenerateOptions) addRoute(r RouteReport) {
+	if o.Report != nil {
+		o.Report.Routes = append(o.Report.Routes, r)
+	}
+}
+
+// reportUnmatched adds routes, which handlers aren't matched, to report
+func reportUnmatched(report *Report, routes []handlers.EchoRoute, matched []handlers.Handler) {
+	found := make(map[string]bool, len(matched))
+	for _, h := range matched {
+		found[h.Method()+" "+h.Path()] = true
+	}
+
+	for _, r := range routes {
+		path := handlers.OpenAPIPath(r.Route.Path)
+		if found[r.Route.Method+" "+path] {
+			continue
+		}
+
+		report.Routes = append(report.Routes, RouteReport{
+			Method:  r.Route.Method,
+			Path:    path,
+			Handler: r.Route.Name,
+			Status:  RouteUnmatched,
+		})
+	}
+}
+
 // CollectRoutes captures routes registered by provider.
 func CollectRoutes(provider RoutesProvider) []handlers.EchoRoute {
 	var routes []handlers.EchoRoute
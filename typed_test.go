@@ -1,9 +1,14 @@
 package typed
 
 import (
+	"net/http"
+	"path/filepath"
 	"testing"
 
 	"github.com/d1vbyz3r0/typed/common/typing"
+	"github.com/d1vbyz3r0/typed/handlers"
+	"github.com/d1vbyz3r0/typed/internal/parser"
+	"github.com/d1vbyz3r0/typed/internal/parser/request"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
@@ -71,6 +76,85 @@ func TestGenerateWithDefaults(t *testing.T) {
 	require.NotNil(t, spec.Components.Schemas)
 }
 
+func TestGenerateBaselineSpec(t *testing.T) {
+	registry := MustNewRegistry(T{
+		Val:  new(string),
+		Type: typing.Basic("string"),
+	})
+
+	baseline := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "api", Version: "1"}}
+	baseline.AddOperation("/health", http.MethodGet, &openapi3.Operation{Responses: openapi3.NewResponses()})
+	baselinePath := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, SaveSpec(baseline, baselinePath))
+
+	var diff SpecDiff
+	err := Generate(GenerateOptions{
+		Spec:             &openapi3.T{},
+		Registry:         registry,
+		Handlers:         []handlers.Handler{},
+		BaselineSpecPath: baselinePath,
+		OnSpecDiff: func(d SpecDiff) error {
+			diff = d
+			return FailOnBreakingChanges(d)
+		},
+	})
+	require.ErrorIs(t, err, ErrBreakingChanges)
+	require.Len(t, diff.Changes, 1)
+	require.Equal(t, "GET /health", diff.Changes[0].Operation)
+	require.True(t, diff.Changes[0].Breaking)
+
+	err = Generate(GenerateOptions{
+		Spec:             &openapi3.T{},
+		Registry:         registry,
+		Handlers:         []handlers.Handler{},
+		BaselineSpecPath: filepath.Join(t.TempDir(), "missing.yaml"),
+		OnSpecDiff: func(d SpecDiff) error {
+			t.Fatal("hook is called without baseline spec")
+			return nil
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestGenerateRouteFilters(t *testing.T) {
+	hidden := handlers.NewHandler(
+		echo.Route{Method: http.MethodGet, Path: "/internal/state"},
+		nil,
+		parser.Handler{Pkg: "example.com/app/internal", Name: "State", Request: &request.Request{}, Ignored: true},
+	)
+
+	// request model isn't registered, so operation of excluded route fails to build
+	broken := handlers.NewHandler(
+		echo.Route{Method: http.MethodPost, Path: "/debug/state"},
+		nil,
+		parser.Handler{Pkg: "example.com/app/debug", Name: "SetState", Request: &request.Request{
+			ModelType:          typing.Named("example.com/app/debug", "State"),
+			ContentTypeMapping: request.ContentTypeMapping{echo.MIMEApplicationJSON: {}},
+		}},
+	)
+
+	spec := &openapi3.T{}
+	err := Generate(GenerateOptions{
+		Spec:     spec,
+		Registry: MustNewRegistry(),
+		Handlers: []handlers.Handler{
+			newTestHandler(http.MethodGet, "/users", "example.com/app/users"),
+			newTestHandler(http.MethodGet, "/health", "example.com/app/health"),
+			newTestHandler(http.MethodGet, "/debug/pprof/heap", "net/http/pprof"),
+			hidden,
+			broken,
+		},
+		ExcludeRoutes: []RouteFilter{
+			{Path: "^/debug/"},
+			{HandlerPkg: "/health$"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, spec.Paths.Len())
+	require.NotNil(t, spec.Paths.Find("/users"))
+}
+
 func TestCollectRoutes(t *testing.T) {
 	handler := func(echo.Context) error { return nil }
 	middleware := func(next echo.HandlerFunc) echo.HandlerFunc { return next }